@@ -3,21 +3,26 @@ package main
 import (
 	"context"
 	"github.com/craftizmv/rewards/config"
+	"github.com/craftizmv/rewards/internal/app/fraud"
 	consumers2 "github.com/craftizmv/rewards/internal/app/handlers/consumers"
+	"github.com/craftizmv/rewards/internal/app/scheduler"
 	"github.com/craftizmv/rewards/internal/app/usecase"
 	"github.com/craftizmv/rewards/internal/data/infrastructure/cache"
 	"github.com/craftizmv/rewards/internal/data/infrastructure/database"
 	"github.com/craftizmv/rewards/internal/data/infrastructure/external/proxies"
 	"github.com/craftizmv/rewards/internal/data/infrastructure/external/service"
+	"github.com/craftizmv/rewards/internal/data/infrastructure/lock"
 	"github.com/craftizmv/rewards/internal/data/infrastructure/queue"
 	"github.com/craftizmv/rewards/internal/data/infrastructure/queue/consumers"
 	"github.com/craftizmv/rewards/internal/data/infrastructure/queue/events"
 	"github.com/craftizmv/rewards/internal/data/infrastructure/queue/publisher"
 	repository_impl "github.com/craftizmv/rewards/internal/data/repository-impl"
 	"github.com/craftizmv/rewards/internal/domain/entities"
+	"github.com/craftizmv/rewards/internal/domain/services"
 	"github.com/craftizmv/rewards/pkg/logger"
 	"github.com/craftizmv/rewards/server"
 	"go.uber.org/zap"
+	"os"
 	"time"
 )
 
@@ -58,15 +63,58 @@ func main() {
 		log.Info("Current time from PostgreSQL:", currentTime)
 	}
 
+	migrator, err := database.NewMigrator(postgresDB, log)
+	if err != nil {
+		log.Error("Could not load schema migrations:", err)
+		return
+	}
+
+	// `rewards migrate ...` only manages the schema and exits.
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(appCtx, migrator, os.Args[2:], log); err != nil {
+			log.Error("Migration failed:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// refuse to run against a schema which does not match this binary
+	if err := migrator.EnsureUpToDate(ctx); err != nil {
+		log.Error("Schema check failed:", err)
+		return
+	}
+
 	rewardRepo := repository_impl.NewPostgresRewardRepository(postgresDB)
-	campaignProxy := proxies.NewCampaignProxy()
+
+	// order level locks are taken from a single backend, an unreachable backend fails the order level flows
+	orderLocker, err := lock.NewLocker(cfg.LockCfg, postgresDB, log)
+	if err != nil {
+		log.Error("Could not initialize order locker:", err)
+		return
+	}
+
+	campaignRepo := repository_impl.NewPostgresCampaignRepository(postgresDB)
+	budgetRepo := repository_impl.NewPostgresCampaignBudgetRepository(postgresDB)
+	userRewardRepo := repository_impl.NewPostgresUserRewardRepository(postgresDB)
+	fraudRepo := repository_impl.NewPostgresFraudRepository(postgresDB)
+	reviewRepo := repository_impl.NewPostgresRewardReviewRepository(postgresDB)
+	voucherRepo := repository_impl.NewPostgresVoucherRepository(postgresDB)
+	redemptionRepo := repository_impl.NewPostgresRewardRedemptionRepository(postgresDB)
+	expiryRepo := repository_impl.NewPostgresRewardExpiryRepository(postgresDB)
+	catalogRepo := repository_impl.NewPostgresRewardCatalogRepository(postgresDB)
+	pointsRepo := repository_impl.NewPostgresPointsLedgerRepository(postgresDB)
+	referralRepo := repository_impl.NewPostgresReferralRepository(postgresDB)
+	giftChoiceRepo := repository_impl.NewPostgresGiftChoiceRepository(postgresDB)
+	campaignProxy := proxies.NewCampaignProxy(campaignRepo)
 	inventoryProxy := proxies.NewInventoryProxy()
 	mailer := service.NewSomeConcreteMailer()
 	emailProxy := proxies.NewEmailProxy(mailer, log)
 
 	shipper := service.NewLogiDeli()
 	shippingProxy := proxies.NewShippingProxy(shipper)
+	discountProxy := proxies.NewDiscountProxy(service.NewLocalDiscountIssuer(), log)
 	userProxy := proxies.NewUserProxy()
+	segmentProxy := proxies.NewSegmentProxy(service.NewLocalSegmentService(), cfg.SegmentCfg)
 	orderProxy := proxies.NewOrderProxy()
 
 	rewardProxies := &usecase.RewardProxies{
@@ -74,13 +122,38 @@ func main() {
 		InventoryProxy: inventoryProxy,
 		EmailProxy:     emailProxy,
 		ShippingProxy:  shippingProxy,
+		DiscountProxy:  discountProxy,
 		UserProxy:      userProxy,
+		SegmentProxy:   segmentProxy,
 		OrderProxy:     orderProxy,
 	}
-	rewardUseCase := usecase.NewRewardUseCaseImpl(redisCache, rewardRepo, log, rewardProxies)
+	campaignSelector, err := services.NewCampaignSelector(cfg.SelectionCfg)
+	if err != nil {
+		log.Error("Could not initialize campaign selector:", err)
+		return
+	}
+
+	rewardRepos := &usecase.RewardRepositories{
+		RewardRepo:     rewardRepo,
+		BudgetRepo:     budgetRepo,
+		UserRewardRepo: userRewardRepo,
+		ReviewRepo:     reviewRepo,
+		VoucherRepo:    voucherRepo,
+		RedemptionRepo: redemptionRepo,
+		PointsRepo:     pointsRepo,
+		ReferralRepo:   referralRepo,
+		GiftChoiceRepo: giftChoiceRepo,
+	}
+	fraudScreener := fraud.NewScreener(cfg.FraudCfg, fraudRepo, log)
+	rewardUseCase := usecase.NewRewardUseCaseImpl(redisCache, rewardRepos, orderLocker, campaignSelector, fraudScreener, cfg.ReviewCfg, log, rewardProxies)
+
+	campaignUseCase := usecase.NewCampaignUseCaseImpl(campaignRepo, budgetRepo, entities.NewCampaignValidator(), log)
+	voucherUseCase := usecase.NewVoucherUseCaseImpl(voucherRepo, log)
+	catalogUseCase := usecase.NewRewardCatalogUseCaseImpl(catalogRepo, log)
+	pointsUseCase := usecase.NewPointsUseCaseImpl(pointsRepo, log)
 
-	// start the echo server.
-	server.NewEchoServer(cfg.EchoCfg, log, rewardUseCase).Start()
+	// activates and ends campaigns at their start and end dates
+	go scheduler.NewCampaignScheduler(cfg.SchedulerCfg, campaignUseCase, log).Start(appCtx)
 
 	// init RabbitMQ
 	conn, err := queue.NewRabbitMQConn(cfg.Rabbitmq, appCtx)
@@ -99,6 +172,10 @@ func main() {
 	// create rabbitMQ publisher which will help with re-allocating when an order is cancelled.
 	pub := publisher.NewPublisher(ctx, cfg.Rabbitmq, conn, log)
 
+	// expires unredeemed rewards and points, and publishes a RewardExpired event for each expired reward
+	rewardExpiryUseCase := usecase.NewRewardExpiryUseCaseImpl(expiryRepo, voucherRepo, pub, log, rewardProxies)
+	go scheduler.NewRewardExpirySweeper(cfg.SweeperCfg, rewardExpiryUseCase, pointsUseCase, rewardUseCase, log).Start(appCtx)
+
 	eligibleOrder := queue.OrderDeliveryBase{
 		Ctx:          ctx,
 		Log:          log,
@@ -111,6 +188,8 @@ func main() {
 	allocateOrderConsumer := consumers.NewOrderConfirmedConsumer[*queue.OrderDeliveryBase](ctx, cfg.Rabbitmq, conn, log, consumers2.HandleAllocateReward)
 	allocateOrderFromBufferConsumer := consumers.NewOrderConfirmedBufferConsumer[*queue.OrderDeliveryBase](ctx, cfg.Rabbitmq, conn, log, consumers2.HandleAllocateFromBufferReward)
 	cancelOrderConsumer := consumers.NewOrderCancelledConsumer[*queue.OrderDeliveryBase](ctx, cfg.Rabbitmq, conn, log, consumers2.HandleCancelReward)
+	redeemRewardConsumer := consumers.NewRewardRedeemedConsumer[*queue.OrderDeliveryBase](ctx, cfg.Rabbitmq, conn, log, consumers2.HandleRedeemReward)
+	confirmReferralConsumer := consumers.NewReferralConfirmedConsumer[*queue.OrderDeliveryBase](ctx, cfg.Rabbitmq, conn, log, consumers2.HandleConfirmReferral)
 	go func() {
 		e := allocateOrderConsumer.ConsumeMessage(events.AllocateReward{}, &eligibleOrder)
 		if e != nil {
@@ -132,4 +211,21 @@ func main() {
 			log.Error("Failed to consume order:", "err", e)
 		}
 	}()
+
+	go func() {
+		e := redeemRewardConsumer.ConsumeMessage(events.RedeemReward{}, &eligibleOrder)
+		if e != nil {
+			log.Error("Failed to consume redemption:", "err", e)
+		}
+	}()
+
+	go func() {
+		e := confirmReferralConsumer.ConsumeMessage(events.ReferralConfirmed{}, &eligibleOrder)
+		if e != nil {
+			log.Error("Failed to consume referral:", "err", e)
+		}
+	}()
+
+	// start the echo server last, it blocks until the server stops.
+	server.NewEchoServer(cfg.EchoCfg, log, rewardUseCase, campaignUseCase, voucherUseCase, catalogUseCase, pointsUseCase).Start()
 }
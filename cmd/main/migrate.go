@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/craftizmv/rewards/internal/data/infrastructure/database"
+	"github.com/craftizmv/rewards/pkg/logger"
+	"strconv"
+)
+
+const migrateUsage = "usage: rewards migrate [up | down [steps] | status]"
+
+// runMigrate handles the migrate subcommand
+func runMigrate(ctx context.Context, migrator *database.Migrator, args []string, log logger.ILogger) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		log.Infof("migrated up, %d migration(s) applied, schema at version %d", applied, migrator.LatestVersion())
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid number of steps %q, %s", args[1], migrateUsage)
+			}
+			steps = n
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		log.Infof("migrated down, %d migration(s) reverted", reverted)
+
+	case "status":
+		current, err := migrator.CurrentVersion(ctx)
+		if err != nil {
+			return err
+		}
+		log.Infof("schema at version %d, latest version is %d", current, migrator.LatestVersion())
+
+	default:
+		return errors.New(migrateUsage)
+	}
+
+	return nil
+}
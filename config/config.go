@@ -1,9 +1,15 @@
 package config
 
 import (
+	"github.com/craftizmv/rewards/internal/app/fraud"
+	"github.com/craftizmv/rewards/internal/app/scheduler"
+	"github.com/craftizmv/rewards/internal/app/usecase"
 	"github.com/craftizmv/rewards/internal/data/infrastructure/cache"
 	"github.com/craftizmv/rewards/internal/data/infrastructure/database"
+	"github.com/craftizmv/rewards/internal/data/infrastructure/external/proxies"
+	"github.com/craftizmv/rewards/internal/data/infrastructure/lock"
 	"github.com/craftizmv/rewards/internal/data/infrastructure/queue"
+	"github.com/craftizmv/rewards/internal/domain/services"
 	"github.com/craftizmv/rewards/pkg/logger"
 	"github.com/craftizmv/rewards/server"
 	"github.com/spf13/viper"
@@ -11,12 +17,19 @@ import (
 )
 
 type Config struct {
-	ServiceName string                `mapstructure:"serviceName"`
-	Logger      *logger.LoggerConfig  `mapstructure:"logger"`
-	Rabbitmq    *queue.RabbitMQConfig `mapstructure:"rabbitmq"`
-	EchoCfg     *server.EchoConfig    `mapstructure:"echo"`
-	CacheCfg    *cache.Config         `mapstructure:"cache"`
-	DBCfg       *database.Config      `mapstructure:"db"`
+	ServiceName  string                            `mapstructure:"serviceName"`
+	Logger       *logger.LoggerConfig              `mapstructure:"logger"`
+	Rabbitmq     *queue.RabbitMQConfig             `mapstructure:"rabbitmq"`
+	EchoCfg      *server.EchoConfig                `mapstructure:"echo"`
+	CacheCfg     *cache.Config                     `mapstructure:"cache"`
+	DBCfg        *database.Config                  `mapstructure:"db"`
+	LockCfg      *lock.Config                      `mapstructure:"lock"`
+	SchedulerCfg *scheduler.Config                 `mapstructure:"campaignScheduler"`
+	SweeperCfg   *scheduler.SweeperConfig          `mapstructure:"rewardExpiry"`
+	SelectionCfg *services.CampaignSelectionConfig `mapstructure:"campaignSelection"`
+	SegmentCfg   *proxies.SegmentConfig            `mapstructure:"segment"`
+	FraudCfg     *fraud.Config                     `mapstructure:"fraud"`
+	ReviewCfg    *usecase.ReviewConfig             `mapstructure:"review"`
 }
 
 var (
@@ -0,0 +1,11 @@
+package contracts
+
+import "github.com/craftizmv/rewards/internal/data/dtos"
+
+type DiscountIssuer interface {
+	// IssueDiscount Create a coupon or a wallet credit and return its reference
+	IssueDiscount(request *dtos.DiscountIssueRequest) (*dtos.DiscountIssueResponse, error)
+
+	// RevokeDiscount Withdraw an issued discount which has not been used yet
+	RevokeDiscount(reference string) error
+}
@@ -0,0 +1,6 @@
+package contracts
+
+type EventPublisher interface {
+	// PublishMessage Publish a pointer to an event on the exchange named after its type
+	PublishMessage(msg interface{}) error
+}
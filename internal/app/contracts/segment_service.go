@@ -0,0 +1,8 @@
+package contracts
+
+import "github.com/craftizmv/rewards/internal/data/dtos"
+
+type SegmentService interface {
+	// GetUserSegments Get the segments the user currently belongs to
+	GetUserSegments(userID string) ([]dtos.SegmentID, error)
+}
@@ -7,6 +7,12 @@ type Shipper interface {
 	ShipItem(itemID int64, detail *dtos.UserDetail) (*dtos.ShipmentResponse, error)
 	ShipItems(itemID []int64, detail *dtos.UserDetail) (*dtos.ShipmentResponse, error)
 
+	// QuoteShipment Estimate the shipment of the products without shipping anything
+	QuoteShipment(productIDs []int64, detail *dtos.UserDetail) (*dtos.ShipmentResponse, error)
+
+	// CheckCoverage Check whether the carrier delivers to the location
+	CheckCoverage(location *dtos.UserLocation) (bool, error)
+
 	// GetShipmentStatus Get the status of a shipment
 	GetShipmentStatus(shipmentID string) (string, error)
 }
@@ -0,0 +1,148 @@
+package fraud
+
+import (
+	"fmt"
+	"github.com/craftizmv/rewards/internal/app/repository"
+	"github.com/craftizmv/rewards/internal/domain/entities"
+	"time"
+)
+
+// thresholdVerdict maps a count to a verdict, a zero threshold never triggers
+func thresholdVerdict(count, reviewThreshold, denyThreshold int) entities.FraudVerdict {
+	if denyThreshold > 0 && count >= denyThreshold {
+		return entities.FraudDeny
+	}
+	if reviewThreshold > 0 && count >= reviewThreshold {
+		return entities.FraudReview
+	}
+	return entities.FraudAllow
+}
+
+// BlocklistDetector denies users, devices, addresses and emails on the blocklist
+type BlocklistDetector struct {
+	repo repository.FraudRepository
+}
+
+func NewBlocklistDetector(repo repository.FraudRepository) *BlocklistDetector {
+	return &BlocklistDetector{repo: repo}
+}
+
+func (d *BlocklistDetector) Name() string {
+	return "blocklist"
+}
+
+func (d *BlocklistDetector) Detect(subject *entities.FraudSubject) (*entities.FraudSignal, error) {
+	entries, err := d.repo.FindBlocklistEntries(map[entities.FingerprintKind]string{
+		entities.FingerprintUser:    subject.UserID,
+		entities.FingerprintDevice:  subject.DeviceID,
+		entities.FingerprintAddress: subject.AddressHash,
+		entities.FingerprintEmail:   subject.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	return &entities.FraudSignal{
+		Verdict: entities.FraudDeny,
+		Reason:  fmt.Sprintf("%s is blocklisted: %s", entries[0].Kind, entries[0].Reason),
+	}, nil
+}
+
+// VelocityDetector flags too many rewarded orders from the same user, device or address within a window
+type VelocityDetector struct {
+	repo            repository.FraudRepository
+	kind            entities.FingerprintKind
+	window          time.Duration
+	reviewThreshold int
+	denyThreshold   int
+}
+
+func NewVelocityDetector(repo repository.FraudRepository, kind entities.FingerprintKind, window time.Duration, reviewThreshold, denyThreshold int) *VelocityDetector {
+	return &VelocityDetector{
+		repo:            repo,
+		kind:            kind,
+		window:          window,
+		reviewThreshold: reviewThreshold,
+		denyThreshold:   denyThreshold,
+	}
+}
+
+func (d *VelocityDetector) Name() string {
+	return "velocity_" + string(d.kind)
+}
+
+func (d *VelocityDetector) Detect(subject *entities.FraudSubject) (*entities.FraudSignal, error) {
+	if d.reviewThreshold <= 0 && d.denyThreshold <= 0 {
+		return nil, nil
+	}
+
+	var value string
+	switch d.kind {
+	case entities.FingerprintUser:
+		value = subject.UserID
+	case entities.FingerprintDevice:
+		value = subject.DeviceID
+	case entities.FingerprintAddress:
+		value = subject.AddressHash
+	}
+	if value == "" {
+		return nil, nil
+	}
+
+	count, err := d.repo.CountOrders(d.kind, value, time.Now().Add(-d.window), subject.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	// the order being screened counts as well
+	verdict := thresholdVerdict(count+1, d.reviewThreshold, d.denyThreshold)
+	if verdict == entities.FraudAllow {
+		return nil, nil
+	}
+
+	return &entities.FraudSignal{
+		Verdict: verdict,
+		Reason:  fmt.Sprintf("%d rewarded orders by %s within %s", count+1, d.kind, d.window),
+	}, nil
+}
+
+// SharedAddressDetector flags rewards shipped to an address other users already got rewards at
+type SharedAddressDetector struct {
+	repo            repository.FraudRepository
+	reviewThreshold int
+	denyThreshold   int
+}
+
+func NewSharedAddressDetector(repo repository.FraudRepository, reviewThreshold, denyThreshold int) *SharedAddressDetector {
+	return &SharedAddressDetector{
+		repo:            repo,
+		reviewThreshold: reviewThreshold,
+		denyThreshold:   denyThreshold,
+	}
+}
+
+func (d *SharedAddressDetector) Name() string {
+	return "shared_address"
+}
+
+func (d *SharedAddressDetector) Detect(subject *entities.FraudSubject) (*entities.FraudSignal, error) {
+	if subject.AddressHash == "" || (d.reviewThreshold <= 0 && d.denyThreshold <= 0) {
+		return nil, nil
+	}
+
+	count, err := d.repo.CountOtherUsersAtAddress(subject.AddressHash, subject.UserID)
+	if err != nil {
+		return nil, err
+	}
+	verdict := thresholdVerdict(count, d.reviewThreshold, d.denyThreshold)
+	if verdict == entities.FraudAllow {
+		return nil, nil
+	}
+
+	return &entities.FraudSignal{
+		Verdict: verdict,
+		Reason:  fmt.Sprintf("%d other users get rewards shipped to the same address", count),
+	}, nil
+}
@@ -0,0 +1,130 @@
+package fraud
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/craftizmv/rewards/internal/app/repository"
+	"github.com/craftizmv/rewards/internal/data/dtos"
+	"github.com/craftizmv/rewards/internal/domain/entities"
+	"github.com/craftizmv/rewards/pkg/logger"
+	"strings"
+	"time"
+)
+
+const defaultVelocityWindow = 24 * time.Hour
+
+// Config struct for fraud screening, a zero threshold disables the check
+type Config struct {
+	Enabled        bool          `mapstructure:"enabled"`
+	VelocityWindow time.Duration `mapstructure:"velocityWindow"` // period the velocity limits apply to
+
+	UserReviewThreshold    int `mapstructure:"userReviewThreshold"` // rewarded orders of a user within the window
+	UserDenyThreshold      int `mapstructure:"userDenyThreshold"`
+	DeviceReviewThreshold  int `mapstructure:"deviceReviewThreshold"` // rewarded orders from a device within the window
+	DeviceDenyThreshold    int `mapstructure:"deviceDenyThreshold"`
+	AddressReviewThreshold int `mapstructure:"addressReviewThreshold"` // rewarded orders shipped to an address within the window
+	AddressDenyThreshold   int `mapstructure:"addressDenyThreshold"`
+
+	SharedAddressReviewThreshold int `mapstructure:"sharedAddressReviewThreshold"` // other users shipping to the same address
+	SharedAddressDenyThreshold   int `mapstructure:"sharedAddressDenyThreshold"`
+}
+
+// Detector looks for one kind of abuse, it returns nil when it found nothing
+type Detector interface {
+	Name() string
+	Detect(subject *entities.FraudSubject) (*entities.FraudSignal, error)
+}
+
+// Screener runs every detector on an allocation and keeps the most severe verdict
+type Screener struct {
+	detectors []Detector
+	repo      repository.FraudRepository
+	log       logger.ILogger
+}
+
+// NewScreener creates a Screener with the default detectors, a disabled config allows everything
+func NewScreener(cfg *Config, repo repository.FraudRepository, log logger.ILogger) *Screener {
+	screener := &Screener{
+		repo: repo,
+		log:  log,
+	}
+	if cfg == nil || !cfg.Enabled {
+		return screener
+	}
+
+	window := cfg.VelocityWindow
+	if window <= 0 {
+		window = defaultVelocityWindow
+	}
+	screener.detectors = []Detector{
+		NewBlocklistDetector(repo),
+		NewVelocityDetector(repo, entities.FingerprintUser, window, cfg.UserReviewThreshold, cfg.UserDenyThreshold),
+		NewVelocityDetector(repo, entities.FingerprintDevice, window, cfg.DeviceReviewThreshold, cfg.DeviceDenyThreshold),
+		NewVelocityDetector(repo, entities.FingerprintAddress, window, cfg.AddressReviewThreshold, cfg.AddressDenyThreshold),
+		NewSharedAddressDetector(repo, cfg.SharedAddressReviewThreshold, cfg.SharedAddressDenyThreshold),
+	}
+	return screener
+}
+
+// WithDetectors adds detectors on top of the default ones
+func (s *Screener) WithDetectors(detectors ...Detector) *Screener {
+	s.detectors = append(s.detectors, detectors...)
+	return s
+}
+
+// Screen runs the detectors and records the fingerprint of the allocation, so that it counts
+// towards the velocity of the allocations screened after it
+func (s *Screener) Screen(subject *entities.FraudSubject) (*entities.FraudResult, error) {
+	result := &entities.FraudResult{Verdict: entities.FraudAllow}
+	for _, detector := range s.detectors {
+		signal, err := detector.Detect(subject)
+		if err != nil {
+			return nil, fmt.Errorf("fraud detector %s failed: %w", detector.Name(), err)
+		}
+		if signal == nil {
+			continue
+		}
+		signal.Detector = detector.Name()
+		result.Signals = append(result.Signals, *signal)
+		result.Verdict = entities.MoreSevere(result.Verdict, signal.Verdict)
+	}
+
+	if len(s.detectors) > 0 {
+		err := s.repo.RecordFingerprint(&entities.RewardFingerprint{
+			OrderID:     subject.OrderID,
+			CampaignID:  subject.CampaignID,
+			UserID:      subject.UserID,
+			DeviceID:    subject.DeviceID,
+			AddressHash: subject.AddressHash,
+			CreatedAt:   time.Now(),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if result.Verdict != entities.FraudAllow {
+		s.log.Warn("allocation flagged by fraud screening", "orderID", subject.OrderID, "userID", subject.UserID, "verdict", result.Verdict, "signals", result.Signals)
+	}
+	return result, nil
+}
+
+// AddressFingerprint normalises the delivery address and hashes it, so that addresses can be compared
+// across users without storing them
+func AddressFingerprint(location *dtos.UserLocation) string {
+	if location == nil || location.StreetAddress == "" {
+		return ""
+	}
+
+	parts := []string{location.StreetAddress, "", location.City, location.PostalCode, location.Country}
+	if location.AddressLine2 != nil {
+		parts[1] = *location.AddressLine2
+	}
+	for i, part := range parts {
+		parts[i] = strings.Join(strings.Fields(strings.ToLower(part)), " ")
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(sum[:])
+}
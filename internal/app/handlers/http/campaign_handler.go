@@ -0,0 +1,198 @@
+package http
+
+import (
+	"errors"
+	"github.com/craftizmv/rewards/internal/app/repository"
+	"github.com/craftizmv/rewards/internal/app/usecase"
+	"github.com/craftizmv/rewards/internal/data/dtos"
+	"github.com/craftizmv/rewards/internal/domain/entities"
+	"github.com/craftizmv/rewards/pkg/logger"
+	"github.com/labstack/echo/v4"
+	uuid "github.com/satori/go.uuid"
+	"net/http"
+)
+
+// HeaderActorID identifies the admin performing a change, it is recorded in the audit trails
+const HeaderActorID = "X-Actor-ID"
+
+type CampaignHandler struct {
+	useCase usecase.CampaignUseCase
+	log     logger.ILogger
+}
+
+func NewCampaignHandler(usecase usecase.CampaignUseCase, logger logger.ILogger) *CampaignHandler {
+	return &CampaignHandler{
+		useCase: usecase,
+		log:     logger,
+	}
+}
+
+func (h *CampaignHandler) CreateCampaign(c echo.Context) error {
+	reqBody := new(dtos.CampaignDTO)
+	if err := c.Bind(reqBody); err != nil {
+		h.log.Errorf("Error binding request body: %v", err)
+		return SendResponse(c, http.StatusBadRequest, "Bad request")
+	}
+
+	campaign, err := h.useCase.CreateCampaign(reqBody)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusCreated, "campaign created", campaign)
+}
+
+func (h *CampaignHandler) UpdateCampaign(c echo.Context) error {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		return SendResponse(c, http.StatusBadRequest, "invalid campaign id")
+	}
+
+	reqBody := new(dtos.CampaignDTO)
+	if err := c.Bind(reqBody); err != nil {
+		h.log.Errorf("Error binding request body: %v", err)
+		return SendResponse(c, http.StatusBadRequest, "Bad request")
+	}
+
+	campaign, err := h.useCase.UpdateCampaign(id, reqBody)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusOK, "campaign updated", campaign)
+}
+
+func (h *CampaignHandler) GetCampaign(c echo.Context) error {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		return SendResponse(c, http.StatusBadRequest, "invalid campaign id")
+	}
+
+	campaign, err := h.useCase.GetCampaign(id)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusOK, "", campaign)
+}
+
+func (h *CampaignHandler) ListCampaigns(c echo.Context) error {
+	campaigns, err := h.useCase.ListCampaigns(dtos.Status(c.QueryParam("status")))
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusOK, "", campaigns)
+}
+
+func (h *CampaignHandler) ListCampaignStatusChanges(c echo.Context) error {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		return SendResponse(c, http.StatusBadRequest, "invalid campaign id")
+	}
+
+	changes, err := h.useCase.ListCampaignStatusChanges(id)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusOK, "", changes)
+}
+
+func (h *CampaignHandler) ScheduleCampaign(c echo.Context) error {
+	return h.changeStatus(c, dtos.Scheduled)
+}
+
+func (h *CampaignHandler) UnscheduleCampaign(c echo.Context) error {
+	return h.changeStatus(c, dtos.Draft)
+}
+
+func (h *CampaignHandler) PauseCampaign(c echo.Context) error {
+	return h.changeStatus(c, dtos.Paused)
+}
+
+func (h *CampaignHandler) ResumeCampaign(c echo.Context) error {
+	return h.changeStatus(c, dtos.Active)
+}
+
+func (h *CampaignHandler) EndCampaign(c echo.Context) error {
+	return h.changeStatus(c, dtos.Ended)
+}
+
+func (h *CampaignHandler) ArchiveCampaign(c echo.Context) error {
+	return h.changeStatus(c, dtos.Archived)
+}
+
+// statusChangeRequest is the optional body of the status change endpoints
+type statusChangeRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (h *CampaignHandler) changeStatus(c echo.Context, target dtos.Status) error {
+	id, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		return SendResponse(c, http.StatusBadRequest, "invalid campaign id")
+	}
+
+	reqBody := new(statusChangeRequest)
+	if err := c.Bind(reqBody); err != nil {
+		h.log.Errorf("Error binding request body: %v", err)
+		return SendResponse(c, http.StatusBadRequest, "Bad request")
+	}
+
+	actor := c.Request().Header.Get(HeaderActorID)
+	if actor == "" {
+		return SendResponse(c, http.StatusBadRequest, HeaderActorID+" header is required")
+	}
+
+	campaign, err := h.useCase.ChangeCampaignStatus(id, target, actor, reqBody.Reason)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusOK, "campaign status updated", campaign)
+}
+
+// sendError maps use case errors to http status codes
+func (h *CampaignHandler) sendError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrCampaignNotFound):
+		return SendResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, entities.ErrInvalidTransition), errors.Is(err, entities.ErrCampaignReadOnly),
+		errors.Is(err, repository.ErrCampaignStatusChanged):
+		return SendResponse(c, http.StatusConflict, err.Error())
+	case isCampaignValidationError(err):
+		return SendResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	h.log.Errorf("campaign request failed: %v", err)
+	return SendResponse(c, http.StatusInternalServerError, "could not process, please try again")
+}
+
+func isCampaignValidationError(err error) bool {
+	for _, invariant := range []error{
+		entities.ErrInvalidDateRange,
+		entities.ErrInvalidStatus,
+		entities.ErrInvalidBudget,
+		entities.ErrBudgetBelowSpent,
+		entities.ErrNegativeMaxGiftsPerUser,
+		entities.ErrInvalidUserLimitWindow,
+		entities.ErrInvalidProductRules,
+		entities.ErrInvalidGeoTargeting,
+		entities.ErrInvalidTimezone,
+		entities.ErrInvalidTimeWindow,
+		entities.ErrInvalidPaymentRules,
+		entities.ErrInvalidChannelRules,
+		entities.ErrInvalidCombinability,
+		entities.ErrInvalidConditions,
+		entities.ErrInvalidRewardTiers,
+		entities.ErrInvalidReferralRewards,
+		entities.ErrInvalidActivation,
+		entities.ErrInvalidSchedule,
+	} {
+		if errors.Is(err, invariant) {
+			return true
+		}
+	}
+	return false
+}
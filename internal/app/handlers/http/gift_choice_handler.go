@@ -0,0 +1,81 @@
+package http
+
+import (
+	"errors"
+	"github.com/craftizmv/rewards/internal/app/repository"
+	"github.com/craftizmv/rewards/internal/app/usecase"
+	"github.com/craftizmv/rewards/internal/data/dtos"
+	"github.com/craftizmv/rewards/internal/domain/entities"
+	"github.com/craftizmv/rewards/pkg/logger"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strconv"
+)
+
+type GiftChoiceHandler struct {
+	useCase usecase.RewardUseCase
+	log     logger.ILogger
+}
+
+func NewGiftChoiceHandler(usecase usecase.RewardUseCase, logger logger.ILogger) *GiftChoiceHandler {
+	return &GiftChoiceHandler{
+		useCase: usecase,
+		log:     logger,
+	}
+}
+
+// GetGiftChoice returns the options of a gift choice and what was chosen so far
+func (h *GiftChoiceHandler) GetGiftChoice(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return SendResponse(c, http.StatusBadRequest, "invalid gift choice id")
+	}
+
+	choice, err := h.useCase.GetGiftChoice(id)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusOK, "", choice)
+}
+
+// ChooseGift picks one of the options of a gift choice, the chosen product is shipped
+func (h *GiftChoiceHandler) ChooseGift(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return SendResponse(c, http.StatusBadRequest, "invalid gift choice id")
+	}
+
+	reqBody := new(dtos.ChooseGiftRequest)
+	if err := c.Bind(reqBody); err != nil {
+		h.log.Errorf("Error binding request body: %v", err)
+		return SendResponse(c, http.StatusBadRequest, "Bad request")
+	}
+	if reqBody.UserID == "" || reqBody.ProductID <= 0 {
+		return SendResponse(c, http.StatusBadRequest, "user_id and product_id are required")
+	}
+
+	choice, err := h.useCase.ChooseGift(id, reqBody)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusOK, "gift chosen", choice)
+}
+
+// sendError maps use case errors to http status codes
+func (h *GiftChoiceHandler) sendError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrGiftChoiceNotFound):
+		return SendResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, entities.ErrGiftChoiceNotOwned):
+		return SendResponse(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, entities.ErrGiftChoiceClosed):
+		return SendResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, entities.ErrNotAGiftOption):
+		return SendResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	h.log.Errorf("gift choice request failed: %v", err)
+	return SendResponse(c, http.StatusInternalServerError, "could not process, please try again")
+}
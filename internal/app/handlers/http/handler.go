@@ -8,3 +8,62 @@ type IRewardHandler interface {
 	// CheckRewardEligibility - checks if order is eligible for the reward
 	CheckRewardEligibility(c echo.Context) error
 }
+
+type IReviewHandler interface {
+	ListReviews(c echo.Context) error
+	ApproveReview(c echo.Context) error
+	RejectReview(c echo.Context) error
+}
+
+type IRedemptionHandler interface {
+	RedeemReward(c echo.Context) error
+	GetRewardBalance(c echo.Context) error
+}
+
+type IGiftChoiceHandler interface {
+	GetGiftChoice(c echo.Context) error
+	ChooseGift(c echo.Context) error
+}
+
+type IPointsHandler interface {
+	GetPointsBalance(c echo.Context) error
+	GetPointsHistory(c echo.Context) error
+	RedeemPoints(c echo.Context) error
+}
+
+type IRewardCatalogHandler interface {
+	CreateRewardGroup(c echo.Context) error
+	UpdateRewardGroup(c echo.Context) error
+	GetRewardGroup(c echo.Context) error
+	ListRewardGroups(c echo.Context) error
+	AddRewardItem(c echo.Context) error
+	AttachRewardItem(c echo.Context) error
+	DetachRewardItem(c echo.Context) error
+	AddRewardGroupProduct(c echo.Context) error
+	RemoveRewardGroupProduct(c echo.Context) error
+	GetRewardItem(c echo.Context) error
+	UpdateRewardItem(c echo.Context) error
+}
+
+type IVoucherHandler interface {
+	CreatePool(c echo.Context) error
+	GetPool(c echo.Context) error
+	ListPools(c echo.Context) error
+	GenerateCodes(c echo.Context) error
+	ImportCodes(c echo.Context) error
+	ConsumeCode(c echo.Context) error
+}
+
+type ICampaignHandler interface {
+	CreateCampaign(c echo.Context) error
+	UpdateCampaign(c echo.Context) error
+	GetCampaign(c echo.Context) error
+	ListCampaigns(c echo.Context) error
+	ListCampaignStatusChanges(c echo.Context) error
+	ScheduleCampaign(c echo.Context) error
+	UnscheduleCampaign(c echo.Context) error
+	PauseCampaign(c echo.Context) error
+	ResumeCampaign(c echo.Context) error
+	EndCampaign(c echo.Context) error
+	ArchiveCampaign(c echo.Context) error
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"errors"
+	"github.com/craftizmv/rewards/internal/app/usecase"
+	"github.com/craftizmv/rewards/internal/data/dtos"
+	"github.com/craftizmv/rewards/internal/domain/entities"
+	"github.com/craftizmv/rewards/pkg/logger"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strconv"
+)
+
+type PointsHandler struct {
+	useCase usecase.PointsUseCase
+	log     logger.ILogger
+}
+
+func NewPointsHandler(usecase usecase.PointsUseCase, logger logger.ILogger) *PointsHandler {
+	return &PointsHandler{
+		useCase: usecase,
+		log:     logger,
+	}
+}
+
+// GetPointsBalance returns the points balance of a user
+func (h *PointsHandler) GetPointsBalance(c echo.Context) error {
+	balance, err := h.useCase.GetPointsBalance(c.Param("userId"))
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusOK, "", balance)
+}
+
+// GetPointsHistory returns a page of the points transactions of a user, newest first
+func (h *PointsHandler) GetPointsHistory(c echo.Context) error {
+	limit, offset := 0, 0
+	if value := c.QueryParam("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return SendResponse(c, http.StatusBadRequest, "invalid limit")
+		}
+		limit = parsed
+	}
+	if value := c.QueryParam("offset"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return SendResponse(c, http.StatusBadRequest, "invalid offset")
+		}
+		offset = parsed
+	}
+
+	transactions, err := h.useCase.GetPointsHistory(c.Param("userId"), limit, offset)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusOK, "", transactions)
+}
+
+// RedeemPoints spends points of a user
+func (h *PointsHandler) RedeemPoints(c echo.Context) error {
+	reqBody := new(dtos.RedeemPointsRequest)
+	if err := c.Bind(reqBody); err != nil {
+		h.log.Errorf("Error binding request body: %v", err)
+		return SendResponse(c, http.StatusBadRequest, "Bad request")
+	}
+
+	redemption, err := h.useCase.RedeemPoints(c.Param("userId"), reqBody.Points, reqBody.Reference)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusOK, "points redeemed", redemption)
+}
+
+// sendError maps use case errors to http status codes
+func (h *PointsHandler) sendError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, entities.ErrInvalidPoints):
+		return SendResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, entities.ErrInsufficientPoints):
+		return SendResponse(c, http.StatusConflict, err.Error())
+	}
+
+	h.log.Errorf("points request failed: %v", err)
+	return SendResponse(c, http.StatusInternalServerError, "could not process, please try again")
+}
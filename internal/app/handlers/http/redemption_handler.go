@@ -0,0 +1,81 @@
+package http
+
+import (
+	"errors"
+	"github.com/craftizmv/rewards/internal/app/repository"
+	"github.com/craftizmv/rewards/internal/app/usecase"
+	"github.com/craftizmv/rewards/internal/data/dtos"
+	"github.com/craftizmv/rewards/internal/domain/entities"
+	"github.com/craftizmv/rewards/pkg/logger"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strconv"
+)
+
+type RedemptionHandler struct {
+	useCase usecase.RewardUseCase
+	log     logger.ILogger
+}
+
+func NewRedemptionHandler(usecase usecase.RewardUseCase, logger logger.ILogger) *RedemptionHandler {
+	return &RedemptionHandler{
+		useCase: usecase,
+		log:     logger,
+	}
+}
+
+// RedeemReward redeems an allocated reward, or a part of its value
+func (h *RedemptionHandler) RedeemReward(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return SendResponse(c, http.StatusBadRequest, "invalid reward id")
+	}
+
+	reqBody := new(dtos.RedeemRewardRequest)
+	if err := c.Bind(reqBody); err != nil {
+		h.log.Errorf("Error binding request body: %v", err)
+		return SendResponse(c, http.StatusBadRequest, "Bad request")
+	}
+	if reqBody.UserID == "" {
+		return SendResponse(c, http.StatusBadRequest, "user_id is required")
+	}
+
+	balance, err := h.useCase.RedeemReward(id, reqBody)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusOK, "reward redeemed", balance)
+}
+
+// GetRewardBalance returns what is left of an allocated reward and how it was redeemed so far
+func (h *RedemptionHandler) GetRewardBalance(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return SendResponse(c, http.StatusBadRequest, "invalid reward id")
+	}
+
+	balance, err := h.useCase.GetRewardBalance(id)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusOK, "", balance)
+}
+
+// sendError maps use case errors to http status codes
+func (h *RedemptionHandler) sendError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrRewardNotFound):
+		return SendResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, entities.ErrRewardNotOwned):
+		return SendResponse(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, entities.ErrRewardAlreadyRedeemed), errors.Is(err, entities.ErrRewardExpired), errors.Is(err, entities.ErrRedemptionReferenceReused):
+		return SendResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, entities.ErrInvalidRedemptionAmount), errors.Is(err, entities.ErrRedemptionExceedsBalance):
+		return SendResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	h.log.Errorf("redemption request failed: %v", err)
+	return SendResponse(c, http.StatusInternalServerError, "could not process, please try again")
+}
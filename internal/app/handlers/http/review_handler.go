@@ -0,0 +1,90 @@
+package http
+
+import (
+	"errors"
+	"github.com/craftizmv/rewards/internal/app/repository"
+	"github.com/craftizmv/rewards/internal/app/usecase"
+	"github.com/craftizmv/rewards/internal/domain/entities"
+	"github.com/craftizmv/rewards/pkg/logger"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strconv"
+)
+
+type ReviewHandler struct {
+	useCase usecase.RewardUseCase
+	log     logger.ILogger
+}
+
+func NewReviewHandler(usecase usecase.RewardUseCase, logger logger.ILogger) *ReviewHandler {
+	return &ReviewHandler{
+		useCase: usecase,
+		log:     logger,
+	}
+}
+
+// ListReviews lists the held allocations, the pending ones unless another status is asked for
+func (h *ReviewHandler) ListReviews(c echo.Context) error {
+	status := entities.ReviewStatus(c.QueryParam("status"))
+	if status == "" {
+		status = entities.ReviewPending
+	}
+
+	reviews, err := h.useCase.ListReviews(status)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusOK, "", reviews)
+}
+
+func (h *ReviewHandler) ApproveReview(c echo.Context) error {
+	return h.decide(c, h.useCase.ApproveReview, "allocation approved")
+}
+
+func (h *ReviewHandler) RejectReview(c echo.Context) error {
+	return h.decide(c, h.useCase.RejectReview, "allocation rejected")
+}
+
+// reviewDecisionRequest is the optional body of the decision endpoints
+type reviewDecisionRequest struct {
+	Note string `json:"note"`
+}
+
+func (h *ReviewHandler) decide(c echo.Context, decide func(id int64, reviewer string, note string) (*entities.RewardReview, error), message string) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return SendResponse(c, http.StatusBadRequest, "invalid review id")
+	}
+
+	reqBody := new(reviewDecisionRequest)
+	if err := c.Bind(reqBody); err != nil {
+		h.log.Errorf("Error binding request body: %v", err)
+		return SendResponse(c, http.StatusBadRequest, "Bad request")
+	}
+
+	reviewer := c.Request().Header.Get(HeaderActorID)
+	if reviewer == "" {
+		return SendResponse(c, http.StatusBadRequest, HeaderActorID+" header is required")
+	}
+
+	review, err := decide(id, reviewer, reqBody.Note)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusOK, message, review)
+}
+
+// sendError maps use case errors to http status codes
+func (h *ReviewHandler) sendError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrReviewNotFound):
+		return SendResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, repository.ErrReviewNotPending):
+		return SendResponse(c, http.StatusConflict, err.Error())
+	}
+
+	h.log.Errorf("review request failed: %v", err)
+	return SendResponse(c, http.StatusInternalServerError, "could not process, please try again")
+}
@@ -0,0 +1,192 @@
+package http
+
+import (
+	"errors"
+	"github.com/craftizmv/rewards/internal/app/repository"
+	"github.com/craftizmv/rewards/internal/app/usecase"
+	"github.com/craftizmv/rewards/internal/domain/entities"
+	"github.com/craftizmv/rewards/pkg/logger"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strconv"
+)
+
+type RewardCatalogHandler struct {
+	useCase usecase.RewardCatalogUseCase
+	log     logger.ILogger
+}
+
+func NewRewardCatalogHandler(usecase usecase.RewardCatalogUseCase, logger logger.ILogger) *RewardCatalogHandler {
+	return &RewardCatalogHandler{
+		useCase: usecase,
+		log:     logger,
+	}
+}
+
+func (h *RewardCatalogHandler) CreateRewardGroup(c echo.Context) error {
+	reqBody := new(entities.RewardGroup)
+	if err := c.Bind(reqBody); err != nil {
+		h.log.Errorf("Error binding request body: %v", err)
+		return SendResponse(c, http.StatusBadRequest, "Bad request")
+	}
+
+	group, err := h.useCase.CreateRewardGroup(reqBody)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusCreated, "reward group created", group)
+}
+
+func (h *RewardCatalogHandler) UpdateRewardGroup(c echo.Context) error {
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return SendResponse(c, http.StatusBadRequest, "invalid reward group id")
+	}
+
+	reqBody := new(entities.RewardGroup)
+	if err := c.Bind(reqBody); err != nil {
+		h.log.Errorf("Error binding request body: %v", err)
+		return SendResponse(c, http.StatusBadRequest, "Bad request")
+	}
+
+	group, err := h.useCase.UpdateRewardGroup(id, reqBody)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusOK, "reward group updated", group)
+}
+
+func (h *RewardCatalogHandler) GetRewardGroup(c echo.Context) error {
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return SendResponse(c, http.StatusBadRequest, "invalid reward group id")
+	}
+
+	group, err := h.useCase.GetRewardGroup(id)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusOK, "", group)
+}
+
+func (h *RewardCatalogHandler) ListRewardGroups(c echo.Context) error {
+	groups, err := h.useCase.ListRewardGroups()
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusOK, "", groups)
+}
+
+// AddRewardItem creates a reward item of any type and attaches it to the reward group
+func (h *RewardCatalogHandler) AddRewardItem(c echo.Context) error {
+	groupID, ok := h.parseID(c, "id")
+	if !ok {
+		return SendResponse(c, http.StatusBadRequest, "invalid reward group id")
+	}
+
+	// items are active unless the body says otherwise
+	reqBody := entities.NewRewardItem("", "")
+	if err := c.Bind(reqBody); err != nil {
+		h.log.Errorf("Error binding request body: %v", err)
+		return SendResponse(c, http.StatusBadRequest, "Bad request")
+	}
+
+	item, err := h.useCase.AddRewardItem(groupID, reqBody)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusCreated, "reward item created", item)
+}
+
+func (h *RewardCatalogHandler) AttachRewardItem(c echo.Context) error {
+	return h.changeMapping(c, "itemId", h.useCase.AttachRewardItem, "reward item attached")
+}
+
+func (h *RewardCatalogHandler) DetachRewardItem(c echo.Context) error {
+	return h.changeMapping(c, "itemId", h.useCase.DetachRewardItem, "reward item detached")
+}
+
+func (h *RewardCatalogHandler) AddRewardGroupProduct(c echo.Context) error {
+	return h.changeMapping(c, "productId", h.useCase.AddRewardGroupProduct, "product added")
+}
+
+func (h *RewardCatalogHandler) RemoveRewardGroupProduct(c echo.Context) error {
+	return h.changeMapping(c, "productId", h.useCase.RemoveRewardGroupProduct, "product removed")
+}
+
+func (h *RewardCatalogHandler) GetRewardItem(c echo.Context) error {
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return SendResponse(c, http.StatusBadRequest, "invalid reward item id")
+	}
+
+	item, err := h.useCase.GetRewardItem(id)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusOK, "", item)
+}
+
+func (h *RewardCatalogHandler) UpdateRewardItem(c echo.Context) error {
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return SendResponse(c, http.StatusBadRequest, "invalid reward item id")
+	}
+
+	reqBody := new(entities.RewardItem)
+	if err := c.Bind(reqBody); err != nil {
+		h.log.Errorf("Error binding request body: %v", err)
+		return SendResponse(c, http.StatusBadRequest, "Bad request")
+	}
+
+	item, err := h.useCase.UpdateRewardItem(id, reqBody)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusOK, "reward item updated", item)
+}
+
+func (h *RewardCatalogHandler) changeMapping(c echo.Context, param string, change func(rewardGroupID, id int64) (*entities.RewardGroupCatalog, error), message string) error {
+	groupID, ok := h.parseID(c, "id")
+	if !ok {
+		return SendResponse(c, http.StatusBadRequest, "invalid reward group id")
+	}
+	id, ok := h.parseID(c, param)
+	if !ok {
+		return SendResponse(c, http.StatusBadRequest, "invalid "+param)
+	}
+
+	group, err := change(groupID, id)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusOK, message, group)
+}
+
+func (h *RewardCatalogHandler) parseID(c echo.Context, param string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param(param), 10, 64)
+	return id, err == nil && id > 0
+}
+
+// sendError maps use case errors to http status codes
+func (h *RewardCatalogHandler) sendError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrRewardGroupNotFound),
+		errors.Is(err, repository.ErrRewardItemNotFound),
+		errors.Is(err, repository.ErrCatalogMappingNotFound):
+		return SendResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, entities.ErrInvalidRewardCatalog):
+		return SendResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	h.log.Errorf("reward catalog request failed: %v", err)
+	return SendResponse(c, http.StatusInternalServerError, "could not process, please try again")
+}
@@ -29,15 +29,11 @@ func (h *RewardHandler) CheckRewardEligibility(c echo.Context) error {
 		return SendResponse(c, http.StatusBadRequest, "Bad request")
 	}
 
-	if status, err := h.useCase.CheckRewardEligibility(reqBody); err != nil {
+	eligibilityResponse, err := h.useCase.CheckRewardEligibility(reqBody)
+	if err != nil {
+		h.log.Errorf("Error checking reward eligibility: %v", err)
 		return SendResponse(c, http.StatusInternalServerError, "could not check, please try again")
-	} else if status {
-		eligibilityResponse := dtos.RewardEligibilityResponse{
-			Eligible: true,
-			Message:  "reward is eligible",
-		}
-		return SendResponseWithData(c, http.StatusOK, "", eligibilityResponse)
 	}
 
-	return SendResponse(c, http.StatusOK, "OK")
+	return SendResponseWithData(c, http.StatusOK, "", eligibilityResponse)
 }
@@ -0,0 +1,155 @@
+package http
+
+import (
+	"errors"
+	"github.com/craftizmv/rewards/internal/app/repository"
+	"github.com/craftizmv/rewards/internal/app/usecase"
+	"github.com/craftizmv/rewards/internal/data/dtos"
+	"github.com/craftizmv/rewards/internal/domain/entities"
+	"github.com/craftizmv/rewards/pkg/logger"
+	"github.com/labstack/echo/v4"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// voucherImportFormField is the multipart field the CSV of an import is read from
+const voucherImportFormField = "file"
+
+type VoucherHandler struct {
+	useCase usecase.VoucherUseCase
+	log     logger.ILogger
+}
+
+func NewVoucherHandler(usecase usecase.VoucherUseCase, logger logger.ILogger) *VoucherHandler {
+	return &VoucherHandler{
+		useCase: usecase,
+		log:     logger,
+	}
+}
+
+func (h *VoucherHandler) CreatePool(c echo.Context) error {
+	reqBody := new(entities.VoucherPool)
+	if err := c.Bind(reqBody); err != nil {
+		h.log.Errorf("Error binding request body: %v", err)
+		return SendResponse(c, http.StatusBadRequest, "Bad request")
+	}
+
+	pool, err := h.useCase.CreatePool(reqBody)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusCreated, "voucher pool created", pool)
+}
+
+func (h *VoucherHandler) GetPool(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return SendResponse(c, http.StatusBadRequest, "invalid voucher pool id")
+	}
+
+	pool, err := h.useCase.GetPool(id)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusOK, "", pool)
+}
+
+func (h *VoucherHandler) ListPools(c echo.Context) error {
+	pools, err := h.useCase.ListPools()
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusOK, "", pools)
+}
+
+// generateCodesRequest is the body of the generate endpoint
+type generateCodesRequest struct {
+	Count int `json:"count"`
+}
+
+func (h *VoucherHandler) GenerateCodes(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return SendResponse(c, http.StatusBadRequest, "invalid voucher pool id")
+	}
+
+	reqBody := new(generateCodesRequest)
+	if err := c.Bind(reqBody); err != nil {
+		h.log.Errorf("Error binding request body: %v", err)
+		return SendResponse(c, http.StatusBadRequest, "Bad request")
+	}
+
+	pool, err := h.useCase.GenerateCodes(id, reqBody.Count)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusOK, "voucher codes generated", pool)
+}
+
+// ImportCodes takes the CSV either as a multipart file or as the raw request body
+func (h *VoucherHandler) ImportCodes(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return SendResponse(c, http.StatusBadRequest, "invalid voucher pool id")
+	}
+
+	var input io.Reader = c.Request().Body
+	if file, err := c.FormFile(voucherImportFormField); err == nil {
+		src, err := file.Open()
+		if err != nil {
+			h.log.Errorf("Error opening uploaded file: %v", err)
+			return SendResponse(c, http.StatusBadRequest, "Bad request")
+		}
+		defer src.Close()
+		input = src
+	}
+
+	pool, err := h.useCase.ImportCodes(id, input)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusOK, "voucher codes imported", pool)
+}
+
+func (h *VoucherHandler) ConsumeCode(c echo.Context) error {
+	reqBody := new(dtos.ConsumeVoucherCodeRequest)
+	if err := c.Bind(reqBody); err != nil {
+		h.log.Errorf("Error binding request body: %v", err)
+		return SendResponse(c, http.StatusBadRequest, "Bad request")
+	}
+	if reqBody.UserID == "" {
+		return SendResponse(c, http.StatusBadRequest, "user_id is required")
+	}
+
+	code, err := h.useCase.ConsumeCode(c.Param("code"), reqBody.UserID)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return SendResponseWithData(c, http.StatusOK, "voucher code consumed", code)
+}
+
+// sendError maps use case errors to http status codes
+func (h *VoucherHandler) sendError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrVoucherPoolNotFound),
+		errors.Is(err, repository.ErrVoucherCodeNotFound):
+		return SendResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, repository.ErrVoucherCodeNotOwned):
+		return SendResponse(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, repository.ErrVoucherCodeNotActive):
+		return SendResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, entities.ErrInvalidVoucherPool),
+		errors.Is(err, entities.ErrInvalidVoucherBatch):
+		return SendResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	h.log.Errorf("voucher request failed: %v", err)
+	return SendResponse(c, http.StatusInternalServerError, "could not process, please try again")
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	. "github.com/craftizmv/rewards/internal/domain/entities"
+	uuid "github.com/satori/go.uuid"
+)
+
+// CampaignBudgetRepository defines the interface for the campaign budget ledger
+type CampaignBudgetRepository interface {
+	// Debit books the entry against the campaign budget. Unless force is set it fails with
+	// ErrGiftExceedsBudget when the remaining budget can't cover the entry.
+	Debit(entry *BudgetEntry, force bool, fence *Fence) error
+	// QueueDebit keeps a cost which was incurred but could not be debited, BookQueuedDebits books it later
+	QueueDebit(entry *BudgetEntry) error
+	// BookQueuedDebits force-debits up to limit queued costs and returns how many were booked
+	BookQueuedDebits(limit int) (int, error)
+	// Refund books the credit of a part of an allocation which was never handed out, the reward stays allocated
+	Refund(entry *BudgetEntry, fence *Fence) error
+	// CreditOrder reverses everything booked for the order on the campaign and returns the credited amount
+	CreditOrder(campaignID uuid.UUID, orderID int64) (float64, error)
+	GetRemainingBudget(campaignID uuid.UUID) (float64, error)
+	GetSpentBudget(campaignID uuid.UUID) (float64, error)
+}
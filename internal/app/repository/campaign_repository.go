@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"errors"
+	. "github.com/craftizmv/rewards/internal/data/dtos"
+	"github.com/craftizmv/rewards/internal/domain/entities"
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+var (
+	ErrCampaignNotFound      = errors.New("campaign not found")
+	ErrCampaignStatusChanged = errors.New("campaign status was changed concurrently")
+)
+
+// CampaignRepository defines the interface for campaign persistence
+type CampaignRepository interface {
+	CreateCampaign(campaign *CampaignDTO) error
+	UpdateCampaign(campaign *CampaignDTO) error
+	// TransitionCampaignStatus stores the new status together with its audit record,
+	// it fails with ErrCampaignStatusChanged if the campaign is no longer in change.From
+	TransitionCampaignStatus(change *entities.CampaignStatusChange) error
+	ListCampaignStatusChanges(id uuid.UUID) ([]*entities.CampaignStatusChange, error)
+	GetCampaignByID(id uuid.UUID) (*CampaignDTO, error)
+	// ListCampaigns returns all campaigns, or only the ones in the given status when status is not empty
+	ListCampaigns(status Status) ([]*CampaignDTO, error)
+	// GetActiveCampaigns returns the active campaigns running at the given time, oldest first
+	GetActiveCampaigns(at time.Time) ([]*CampaignDTO, error)
+	// GetCampaignsDueForTransition returns the campaigns the scheduler has to start or end at the given time
+	GetCampaignsDueForTransition(at time.Time) ([]*CampaignDTO, error)
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"errors"
+)
+
+var ErrStaleFence = errors.New("write carries a stale fencing token")
+
+// Fence is the fencing token of the distributed lock a write is done under. A write carrying an older token
+// than the newest one seen for the key was done by an owner whose lock expired and fails with ErrStaleFence.
+// Writes which are not done under a lock pass a nil fence.
+type Fence struct {
+	Key   string
+	Token int64
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	. "github.com/craftizmv/rewards/internal/domain/entities"
+	"time"
+)
+
+// FraudRepository defines the interface for the data the fraud detectors work on
+type FraudRepository interface {
+	// RecordFingerprint stores the fingerprint of an allocation, recording the same order twice is a no-op
+	RecordFingerprint(fingerprint *RewardFingerprint) error
+	// CountOrders counts the orders with the given fingerprint value since the given time, leaving out an order
+	CountOrders(kind FingerprintKind, value string, since time.Time, excludeOrderID int64) (int, error)
+	// CountOtherUsersAtAddress counts the users other than the given one that got rewards shipped to the address
+	CountOtherUsersAtAddress(addressHash string, userID string) (int, error)
+	// FindBlocklistEntries returns the entries matching any of the given values
+	FindBlocklistEntries(values map[FingerprintKind]string) ([]*BlocklistEntry, error)
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"errors"
+	. "github.com/craftizmv/rewards/internal/domain/entities"
+	"time"
+)
+
+var ErrGiftChoiceNotFound = errors.New("gift choice not found")
+
+// GiftChoiceRepository defines the interface for the choose-one rewards waiting for the user
+type GiftChoiceRepository interface {
+	// CreateGiftChoice records the pending choice and sets its id, offering the same reward group for the order again
+	// returns the choice recorded before
+	CreateGiftChoice(choice *GiftChoice, fence *Fence) error
+	GetGiftChoice(id int64) (*GiftChoice, error)
+	// ListDueGiftChoices returns up to limit pending choices whose deadline is at or before the given time
+	ListDueGiftChoices(at time.Time, limit int) ([]*GiftChoice, error)
+	// ClaimGiftChoice moves a pending choice to settling before its product ships,
+	// it fails with ErrGiftChoiceClosed if the choice is not pending
+	ClaimGiftChoice(id int64, fence *Fence) error
+	// ReleaseGiftChoice puts a settling choice back to pending, used when the product could not be shipped
+	ReleaseGiftChoice(id int64, fence *Fence) error
+	// SettleGiftChoice records the shipped product, it fails with ErrGiftChoiceClosed if the choice is not settling
+	SettleGiftChoice(id int64, status GiftChoiceStatus, productID int64, at time.Time, fence *Fence) error
+	// CancelGiftChoice cancels a pending choice, it fails with ErrGiftChoiceClosed if the choice is not pending
+	CancelGiftChoice(id int64, at time.Time, fence *Fence) error
+	// CancelGiftChoices cancels the pending choices of the order and returns how many there were
+	CancelGiftChoices(orderID int64, at time.Time, fence *Fence) (int, error)
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"errors"
+	. "github.com/craftizmv/rewards/internal/domain/entities"
+	"time"
+)
+
+var ErrPointsAccrualNotFound = errors.New("points accrual not found")
+
+// PointsLedgerRepository defines the interface for the double-entry points ledger
+type PointsLedgerRepository interface {
+	// PostTransaction records the transaction with its entries, a redemption fails with ErrInsufficientPoints when the
+	// balance does not cover it. A transaction whose reference was recorded before is not posted again.
+	PostTransaction(transaction *PointsTransaction, fence *Fence) error
+	// ReverseAccrual takes back what is left of the accrual, it returns nil when nothing is left
+	ReverseAccrual(accrualID int64, at time.Time) (*PointsTransaction, error)
+	// ExpireAccrual expires what is left of the accrual, capped at the balance of the user as spent points cannot expire.
+	// It returns nil when nothing was left to expire, the accrual is not due again either way.
+	ExpireAccrual(accrualID int64, at time.Time) (*PointsTransaction, error)
+	GetBalance(userID string) (*PointsBalance, error)
+	// ListTransactions returns the transactions of the user, newest first
+	ListTransactions(userID string, limit, offset int) ([]*PointsTransaction, error)
+	// GetOrderAccruals returns the accruals of the order with what is left of them
+	GetOrderAccruals(orderID int64) ([]*PointsTransaction, error)
+	// ListAccrualsDueForExpiry returns up to limit accruals which expire at or before the given time
+	ListAccrualsDueForExpiry(at time.Time, limit int) ([]*PointsTransaction, error)
+}
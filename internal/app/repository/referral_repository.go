@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"errors"
+	. "github.com/craftizmv/rewards/internal/domain/entities"
+	"time"
+)
+
+var ErrReferralStatusChanged = errors.New("referral is no longer in the expected status")
+
+// ReferralRepository defines the interface for the attribution of referee orders to their referrers
+type ReferralRepository interface {
+	// AttributeReferral records the referral and sets its id. Attributing the same order again returns the recorded
+	// referral, any other order of a referee who was already referred in the campaign fails with ErrRefereeAlreadyReferred.
+	AttributeReferral(referral *Referral) error
+	// ListReferralsByOrderID returns the referrals the order was attributed to, one per referral campaign
+	ListReferralsByOrderID(orderID int64) ([]*Referral, error)
+	// UpdateReferralStatus moves the referral on, it fails with ErrReferralStatusChanged if it is not in the from status
+	UpdateReferralStatus(id int64, from ReferralStatus, to ReferralStatus, at time.Time) error
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"errors"
+	. "github.com/craftizmv/rewards/internal/domain/entities"
+)
+
+var (
+	ErrRewardGroupNotFound    = errors.New("reward group not found")
+	ErrRewardItemNotFound     = errors.New("reward item not found")
+	ErrCatalogMappingNotFound = errors.New("reward group mapping not found")
+)
+
+// RewardCatalogRepository defines the interface for administering reward groups, reward items and their mappings
+type RewardCatalogRepository interface {
+	// CreateRewardGroup inserts the group and sets its ID
+	CreateRewardGroup(group *RewardGroup) error
+	UpdateRewardGroup(group *RewardGroup) error
+	GetRewardGroup(id int64) (*RewardGroup, error)
+	ListRewardGroups() ([]*RewardGroup, error)
+
+	// CreateRewardItem inserts the item and sets its ID
+	CreateRewardItem(item *RewardItem) error
+	UpdateRewardItem(item *RewardItem) error
+	GetRewardItem(id int64) (*RewardItem, error)
+	ListRewardGroupItems(rewardGroupID int64) ([]*RewardItem, error)
+
+	// AttachRewardItem and AddRewardGroupProduct are idempotent, mapping twice has no effect
+	AttachRewardItem(rewardGroupID, rewardItemID int64) error
+	DetachRewardItem(rewardGroupID, rewardItemID int64) error
+	AddRewardGroupProduct(rewardGroupID, productID int64) error
+	RemoveRewardGroupProduct(rewardGroupID, productID int64) error
+	ListRewardGroupProductIDs(rewardGroupID int64) ([]int64, error)
+}
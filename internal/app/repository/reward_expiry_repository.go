@@ -0,0 +1,17 @@
+package repository
+
+import (
+	. "github.com/craftizmv/rewards/internal/domain/entities"
+	"time"
+)
+
+// RewardExpiryRepository defines the interface used by the expiration job
+type RewardExpiryRepository interface {
+	// ListRewardsDueForExpiry returns up to limit unredeemed rewards which are due at the given time, the oldest first
+	ListRewardsDueForExpiry(at time.Time, limit int) ([]*ExpiringReward, error)
+	// ExpireReward marks the reward as expired, it returns false when the reward was redeemed or expired in the meantime
+	ExpireReward(id int64, at time.Time) (bool, error)
+	// ListRewardsDueForReminder returns up to limit unredeemed rewards due between now and remindBefore whose owner was not reminded yet
+	ListRewardsDueForReminder(now time.Time, remindBefore time.Time, limit int) ([]*ExpiringReward, error)
+	MarkReminded(id int64, at time.Time) error
+}
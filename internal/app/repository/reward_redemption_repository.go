@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"errors"
+	. "github.com/craftizmv/rewards/internal/domain/entities"
+)
+
+var ErrRewardNotFound = errors.New("reward not found")
+
+// RewardRedemptionRepository defines the interface for redeeming allocated reward items
+type RewardRedemptionRepository interface {
+	GetOrderRewardItem(id int64) (*OrderRewardItem, error)
+	// RedeemReward applies the redemption to the reward and records it, concurrent redemptions of the same reward are serialised.
+	// A redemption whose reference was recorded before for the same reward and user is not applied again, the reward is
+	// returned as it is. A reference recorded for another reward or user fails with ErrRedemptionReferenceReused.
+	RedeemReward(redemption *RewardRedemption) (*OrderRewardItem, error)
+	// ListRedemptions returns the redemptions of the reward, oldest first
+	ListRedemptions(orderRewardItemID int64) ([]*RewardRedemption, error)
+}
@@ -9,11 +9,16 @@ type RewardRepository interface {
 	GetRewardGroupByID(id int64) (*RewardGroup, error)
 	GetRewardItemIDsFromRewardGroup(rewardGroupID int64) ([]int64, error)
 	GetProductIDsFromRewardGroup(rewardGroupID int64) ([]int64, error)
+	GetRewardGroupCost(rewardGroupID int64) (float64, error)
+	GetRewardItemsFromRewardGroup(rewardGroupID int64) ([]*RewardItem, error)
 	InsertRewardGroupRewardItem(rewardGroupID, rewardItemID int64) error
 	InsertRewardGroupRewardItemsBatch(rewardGroupID int64, rewardItemIDs []int64, batchSize int) error
-	UpdateOrderRewardItemsBatch(orderRewardItems []*OrderRewardItem, batchSize int) error
+	UpdateOrderRewardItemsBatch(orderRewardItems []*OrderRewardItem, batchSize int, fence *Fence) error
+	InsertOrderRewardItems(orderRewardItems []*OrderRewardItem, fence *Fence) error
+	GetOrderRewardItems(orderID int64) ([]*OrderRewardItem, error)
+	InsertOrderRewardGroup(orderID, rewardGroupID int64, fence *Fence) error
 	GetRewardGroupIDByOrderID(orderID int64) ([]int64, error)
-	DeleteRewardGroupByOrderID(orderID int64, rewardGroupID int64) error
-	DeleteRewardItemsByOrderID(orderID int64) error
+	DeleteRewardGroupByOrderID(orderID int64, rewardGroupID int64, fence *Fence) error
+	DeleteRewardItemsByOrderID(orderID int64, fence *Fence) error
 	DeleteRewardItemsByRewardGroupID(rewardGroupID int64) error
 }
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"errors"
+	. "github.com/craftizmv/rewards/internal/domain/entities"
+)
+
+var (
+	ErrReviewNotFound   = errors.New("reward review not found")
+	ErrReviewNotPending = errors.New("reward review is no longer pending")
+)
+
+// RewardReviewRepository defines the interface for the manual review queue of allocations
+type RewardReviewRepository interface {
+	// EnqueueReview adds a pending review, an order that is already pending review is not queued again
+	EnqueueReview(review *RewardReview) error
+	GetReview(id int64) (*RewardReview, error)
+	// ListReviews returns the reviews with the given status, oldest first, all reviews when the status is empty
+	ListReviews(status ReviewStatus) ([]*RewardReview, error)
+	// DecideReview approves or rejects a pending review, it fails with ErrReviewNotPending if it was decided already
+	DecideReview(id int64, status ReviewStatus, reviewer string, note string) error
+	// ReopenReview puts an approved review back to pending, used when the approved allocation fails
+	ReopenReview(id int64, note string) error
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"github.com/craftizmv/rewards/internal/data/dtos"
+	. "github.com/craftizmv/rewards/internal/domain/entities"
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+// UserRewardRepository defines the interface for the user reward ledger
+type UserRewardRepository interface {
+	GetUserRewardUsage(userID string, campaignID uuid.UUID, windowStart time.Time) (*UserRewardUsage, error)
+	// RecordUserReward checks the limits and records the reward in one step,
+	// it fails with ErrUserRewardLimitReached when the user already got enough rewards
+	// and with ErrTierCapReached when the reward group gave out tierCap rewards, a zero tierCap is no cap
+	RecordUserReward(reward *UserReward, limits dtos.UserRewardLimits, tierCap int, fence *Fence) error
+	// ReverseUserReward marks the reward of the order as reversed so that it stops counting towards the limits
+	ReverseUserReward(userID string, campaignID uuid.UUID, orderID int64) error
+	// HasUserReward tells whether the user holds a reward of the campaign for the order which was not reversed
+	HasUserReward(userID string, campaignID uuid.UUID, orderID int64) (bool, error)
+	// CountTierRewards counts the rewards of the reward group the campaign gave out, reversed rewards excluded
+	CountTierRewards(campaignID uuid.UUID, rewardGroupID int64) (int, error)
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"errors"
+	. "github.com/craftizmv/rewards/internal/domain/entities"
+	"time"
+)
+
+var (
+	ErrVoucherPoolNotFound  = errors.New("voucher pool not found")
+	ErrVoucherPoolExhausted = errors.New("voucher pool has no codes left")
+	ErrVoucherCodeNotFound  = errors.New("voucher code not found")
+	ErrVoucherCodeNotActive = errors.New("voucher code is not assigned")
+	ErrVoucherCodeNotOwned  = errors.New("voucher code is assigned to another user")
+)
+
+// VoucherRepository defines the interface for voucher pools and their codes
+type VoucherRepository interface {
+	CreatePool(pool *VoucherPool) error
+	// GetPool and ListPools fill in the stock of the pools
+	GetPool(id int64) (*VoucherPool, error)
+	ListPools() ([]*VoucherPool, error)
+	ListPoolsByRewardGroup(rewardGroupID int64) ([]*VoucherPool, error)
+	// AddCodes stores new available codes, codes that exist already are skipped. It returns how many were added.
+	AddCodes(poolID int64, codes []string) (int, error)
+	// AssignCode hands out one available code of the pool to the order, atomically. Assigning again for the same
+	// order returns the code it already got. It fails with ErrVoucherPoolExhausted when no code is left.
+	AssignCode(poolID int64, orderID int64, userID string, fence *Fence) (*VoucherCode, error)
+	// ReleaseCodes makes the codes assigned to the order available again, for allocations that did not go through
+	ReleaseCodes(orderID int64) error
+	// ReclaimExpiredCodes takes back up to limit assigned codes whose reward group or pool expired before they were used.
+	// Codes of a pool which did not expire are made available again, the others are voided. They are returned with their
+	// new status and with the order and the user they had been handed to.
+	ReclaimExpiredCodes(at time.Time, limit int) ([]*VoucherCode, error)
+	// ReclaimCodes takes back the unused codes assigned to the order, for cancelled rewards whose codes were already
+	// handed out. Codes of a pool which did not expire are made available again, the others are voided.
+	ReclaimCodes(orderID int64, at time.Time) error
+	// ConsumeCode marks a code assigned to the user as used. It fails with ErrVoucherCodeNotOwned for a code of
+	// another user and with ErrVoucherCodeNotActive for any other status.
+	ConsumeCode(code string, userID string) (*VoucherCode, error)
+}
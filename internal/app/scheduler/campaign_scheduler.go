@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"context"
+	"github.com/craftizmv/rewards/internal/app/usecase"
+	"github.com/craftizmv/rewards/pkg/logger"
+	"time"
+)
+
+const defaultInterval = time.Minute
+
+// Config struct for the campaign scheduler
+type Config struct {
+	Interval time.Duration `mapstructure:"interval"` // how often due campaigns are looked up
+}
+
+// CampaignScheduler periodically activates and ends campaigns when their start and end dates are reached.
+// The dates are instants, they are compared as such whatever the timezone of the replica.
+type CampaignScheduler struct {
+	useCase  usecase.CampaignUseCase
+	interval time.Duration
+	log      logger.ILogger
+}
+
+// NewCampaignScheduler creates a CampaignScheduler
+func NewCampaignScheduler(cfg *Config, useCase usecase.CampaignUseCase, log logger.ILogger) *CampaignScheduler {
+	interval := defaultInterval
+	if cfg != nil && cfg.Interval > 0 {
+		interval = cfg.Interval
+	}
+
+	return &CampaignScheduler{
+		useCase:  useCase,
+		interval: interval,
+		log:      log,
+	}
+}
+
+// Start runs the scheduler until the context is cancelled
+func (s *CampaignScheduler) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.log.Infof("campaign scheduler started, interval %s", s.interval)
+	s.tick()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.log.Info("campaign scheduler stopped")
+			return
+		case <-ticker.C:
+			s.tick()
+		}
+	}
+}
+
+func (s *CampaignScheduler) tick() {
+	now := time.Now()
+	if err := s.useCase.RunScheduledTransitions(now); err != nil {
+		s.log.Errorf("scheduled campaign transitions at %s failed: %v", now.Format(time.RFC3339), err)
+	}
+}
@@ -0,0 +1,124 @@
+package scheduler
+
+import (
+	"context"
+	"github.com/craftizmv/rewards/internal/app/usecase"
+	"github.com/craftizmv/rewards/pkg/logger"
+	"time"
+)
+
+const (
+	defaultSweepInterval  = 15 * time.Minute
+	defaultSweepBatchSize = 500
+	defaultReminderDays   = 3
+)
+
+// SweeperConfig struct for the reward expiry sweeper
+type SweeperConfig struct {
+	Interval     time.Duration `mapstructure:"interval"`     // how often due rewards are looked up
+	BatchSize    int           `mapstructure:"batchSize"`    // rewards handled per run, the rest waits for the next run
+	ReminderDays int           `mapstructure:"reminderDays"` // how many days ahead of the expiry the owner is reminded, negative disables reminders
+}
+
+// RewardExpirySweeper periodically expires the rewards and points which were never redeemed and reminds their owners ahead of it.
+// Gift choices the user let lapse get their default product.
+type RewardExpirySweeper struct {
+	useCase      usecase.RewardExpiryUseCase
+	points       usecase.PointsUseCase
+	rewards      usecase.RewardUseCase
+	interval     time.Duration
+	batchSize    int
+	reminderDays int
+	log          logger.ILogger
+}
+
+// NewRewardExpirySweeper creates a RewardExpirySweeper
+func NewRewardExpirySweeper(cfg *SweeperConfig, useCase usecase.RewardExpiryUseCase, points usecase.PointsUseCase, rewards usecase.RewardUseCase, log logger.ILogger) *RewardExpirySweeper {
+	sweeper := &RewardExpirySweeper{
+		useCase:      useCase,
+		points:       points,
+		rewards:      rewards,
+		interval:     defaultSweepInterval,
+		batchSize:    defaultSweepBatchSize,
+		reminderDays: defaultReminderDays,
+		log:          log,
+	}
+	if cfg != nil {
+		if cfg.Interval > 0 {
+			sweeper.interval = cfg.Interval
+		}
+		if cfg.BatchSize > 0 {
+			sweeper.batchSize = cfg.BatchSize
+		}
+		if cfg.ReminderDays != 0 {
+			sweeper.reminderDays = cfg.ReminderDays
+		}
+	}
+	return sweeper
+}
+
+// Start runs the sweeper until the context is cancelled
+func (s *RewardExpirySweeper) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.log.Infof("reward expiry sweeper started, interval %s, reminders %d days ahead", s.interval, s.reminderDays)
+	s.tick()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.log.Info("reward expiry sweeper stopped")
+			return
+		case <-ticker.C:
+			s.tick()
+		}
+	}
+}
+
+func (s *RewardExpirySweeper) tick() {
+	now := time.Now()
+
+	expired, err := s.useCase.ExpireDueRewards(now, s.batchSize)
+	if err != nil {
+		s.log.Errorf("expiring rewards at %s failed: %v", now.Format(time.RFC3339), err)
+	}
+	if expired > 0 {
+		s.log.Infof("expired %d rewards", expired)
+	}
+
+	accruals, err := s.points.ExpireDuePoints(now, s.batchSize)
+	if err != nil {
+		s.log.Errorf("expiring points at %s failed: %v", now.Format(time.RFC3339), err)
+	}
+	if accruals > 0 {
+		s.log.Infof("expired the points of %d accruals", accruals)
+	}
+
+	gifts, err := s.rewards.DefaultDueGiftChoices(now, s.batchSize)
+	if err != nil {
+		s.log.Errorf("defaulting gift choices at %s failed: %v", now.Format(time.RFC3339), err)
+	}
+	if gifts > 0 {
+		s.log.Infof("shipped the default gift of %d choices", gifts)
+	}
+
+	debits, err := s.rewards.BookQueuedDebits(s.batchSize)
+	if err != nil {
+		s.log.Errorf("booking queued budget debits failed: %v", err)
+	}
+	if debits > 0 {
+		s.log.Infof("booked %d queued budget debits", debits)
+	}
+
+	if s.reminderDays < 0 {
+		return
+	}
+	reminded, err := s.useCase.SendExpiryReminders(now, now.AddDate(0, 0, s.reminderDays), s.batchSize)
+	if err != nil {
+		s.log.Errorf("sending expiry reminders at %s failed: %v", now.Format(time.RFC3339), err)
+	}
+	if reminded > 0 {
+		s.log.Infof("sent %d expiry reminders", reminded)
+	}
+}
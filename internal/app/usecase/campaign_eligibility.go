@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"github.com/craftizmv/rewards/internal/data/dtos"
+	"github.com/craftizmv/rewards/internal/domain/conditions"
+	"github.com/craftizmv/rewards/internal/domain/entities"
+	"time"
+)
+
+// resolveCombinability checks the campaign against the promotions applied to the order. On a conflict it
+// returns an *IneligibleError, or a copy of the campaign giving the downgrade reward group instead.
+func resolveCombinability(campaign *dtos.CampaignDTO, orderDTO *dtos.OrderDTO) (*dtos.CampaignDTO, bool, error) {
+	rules := campaign.EligibilityCriteria.Combinability
+	err := entities.CheckCombinability(rules, orderDTO.AppliedPromotions)
+	if err == nil {
+		return campaign, false, nil
+	}
+	if !entities.DowngradesOnConflict(rules) {
+		return nil, false, ineligible(dtos.ReasonPromotionConflict, "%v", err)
+	}
+
+	downgraded := *campaign
+	downgraded.RewardGroupID = rules.DowngradeRewardGroupID
+	downgraded.RewardValue = rules.DowngradeRewardValue
+	return &downgraded, true, nil
+}
+
+// resolveTier picks the highest value tier the order reaches which still has stock, falling back to lower tiers.
+// It returns a copy of the campaign giving the reward group of the tier, or the campaign itself for the base reward.
+func (rewardUseCase *RewardUseCaseImpl) resolveTier(campaign *dtos.CampaignDTO, orderDTO *dtos.OrderDTO) (*dtos.CampaignDTO, string, error) {
+	for _, tier := range entities.QualifyingTiers(campaign.EligibilityCriteria, orderDTO.OrderValue) {
+		inStock, err := rewardUseCase.tierInStock(campaign, tier)
+		if err != nil {
+			return nil, "", err
+		}
+		if inStock {
+			return entities.ApplyTier(campaign, tier), tier.Name, nil
+		}
+		rewardUseCase.log.Debug("reward tier out of stock", "campaignID", campaign.ID, "tier", tier.Name, "rewardGroupID", tier.RewardGroupID)
+	}
+	return campaign, "", nil
+}
+
+// tierInStock tells whether the tier is below its cap and its reward group has voucher codes and inventory left
+func (rewardUseCase *RewardUseCaseImpl) tierInStock(campaign *dtos.CampaignDTO, tier dtos.RewardTier) (bool, error) {
+	if tier.MaxRewards > 0 {
+		allocated, err := rewardUseCase.userRewardRepo.CountTierRewards(campaign.ID, tier.RewardGroupID)
+		if err != nil {
+			return false, err
+		}
+		if entities.CheckTierCap(tier, allocated+1) != nil {
+			return false, nil
+		}
+	}
+
+	pools, err := rewardUseCase.voucherRepo.ListPoolsByRewardGroup(tier.RewardGroupID)
+	if err != nil {
+		return false, err
+	}
+	for _, pool := range pools {
+		if pool.Stock.Available == 0 {
+			return false, nil
+		}
+	}
+
+	productIDs, err := rewardUseCase.rewardRepo.GetProductIDsFromRewardGroup(tier.RewardGroupID)
+	if err != nil {
+		return false, err
+	}
+	if len(productIDs) > 0 {
+		ok, _, err := rewardUseCase.proxies.InventoryProxy.BulkVerifyInventoryAvailability(productIDs)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// evaluateCampaign checks the order against the rules of a single campaign.
+// It returns an *IneligibleError when the order does not qualify.
+func (rewardUseCase *RewardUseCaseImpl) evaluateCampaign(campaign *dtos.CampaignDTO, orderDTO *dtos.OrderDTO) error {
+	if campaign.Status != dtos.Active {
+		return ineligible(dtos.ReasonCampaignInactive, "campaign %s is %s", campaign.ID, campaign.Status)
+	}
+
+	if campaign.AllocatedRewards >= campaign.TotalEligibleRewards {
+		return ineligible(dtos.ReasonAllocationLimitReached, "campaign %s allocated all of its %d rewards", campaign.ID, campaign.TotalEligibleRewards)
+	}
+
+	if err := entities.CheckTimeWindows(campaign, time.Now()); err != nil {
+		if errors.Is(err, entities.ErrOutsideTimeWindow) {
+			return ineligible(dtos.ReasonOutsideTimeWindow, "%v", err)
+		}
+		return err
+	}
+
+	if orderDTO.OrderValue < campaign.EligibilityCriteria.MinimumPurchaseAmount {
+		return ineligible(dtos.ReasonOrderValueTooLow, "order value %.2f is below %.2f", orderDTO.OrderValue, campaign.EligibilityCriteria.MinimumPurchaseAmount)
+	}
+
+	if err := entities.CheckPaymentRules(campaign.EligibilityCriteria.PaymentRules, orderDTO.Payment); err != nil {
+		return ineligible(dtos.ReasonPaymentNotAccepted, "%v", err)
+	}
+	if err := entities.CheckChannelRules(campaign.EligibilityCriteria.ChannelRules, orderDTO.Channel); err != nil {
+		return ineligible(dtos.ReasonChannelNotAccepted, "%v", err)
+	}
+
+	if err := rewardUseCase.checkOrderRules(campaign, orderDTO, time.Now()); err != nil {
+		return err
+	}
+
+	// per-user limits can only be checked when the order carries the user
+	if orderDTO.UserID != "" {
+		limits := campaign.EligibilityCriteria.UserLimits
+		usage, err := rewardUseCase.userRewardRepo.GetUserRewardUsage(orderDTO.UserID, campaign.ID, entities.WindowStart(limits, time.Now()))
+		if err != nil {
+			return err
+		}
+		if err := entities.CheckUserRewardLimits(limits, usage); err != nil {
+			return ineligible(dtos.ReasonUserRewardLimitReached, "user %s: %v", orderDTO.UserID, err)
+		}
+	}
+
+	remainingBudget, err := rewardUseCase.budgetRepo.GetRemainingBudget(campaign.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get remaining budget: %w", err)
+	}
+	rewardCost, err := rewardUseCase.rewardRepo.GetRewardGroupCost(campaign.RewardGroupID)
+	if err != nil {
+		return err
+	}
+	if remainingBudget < rewardCost {
+		return ineligible(dtos.ReasonBudgetExhausted, "campaign %s has %.2f left, the next reward costs %.2f", campaign.ID, remainingBudget, rewardCost)
+	}
+
+	pools, err := rewardUseCase.voucherRepo.ListPoolsByRewardGroup(campaign.RewardGroupID)
+	if err != nil {
+		return err
+	}
+	for _, pool := range pools {
+		if pool.Stock.Available == 0 {
+			return ineligible(dtos.ReasonVoucherStockExhausted, "voucher pool %s of campaign %s has no codes left", pool.Name, campaign.ID)
+		}
+	}
+
+	// check inventory of the rewardGroup from the inventory table.
+	// Check the availability of productID obtained from RewardGroup data.
+	rewardGroup, err := rewardUseCase.rewardRepo.GetRewardGroupByID(campaign.RewardGroupID)
+	if err != nil {
+		return fmt.Errorf("failed to get rewardGroup from repository: %w", err)
+	}
+	if rewardGroup == nil {
+		return fmt.Errorf("no rewardGroup found for rewardGroup ID %d", campaign.RewardGroupID)
+	}
+	// TODO Get productList from RewardGroup, then using the product list check inventory (as done in AllocateReward func)
+
+	// physical rewards are shipped, so the delivery location must be covered by the carrier
+	productIDs, err := rewardUseCase.rewardRepo.GetProductIDsFromRewardGroup(campaign.RewardGroupID)
+	if err != nil {
+		return err
+	}
+	if len(productIDs) > 0 && orderDTO.Location != nil {
+		shippable, err := rewardUseCase.proxies.ShippingProxy.IsShippable(orderDTO.Location)
+		if err != nil {
+			return fmt.Errorf("failed to check shipping coverage: %w", err)
+		}
+		if !shippable {
+			return ineligible(dtos.ReasonNotShippable, "carrier does not deliver to %s, %s", orderDTO.Location.PostalCode, orderDTO.Location.Country)
+		}
+	}
+
+	return nil
+}
+
+// checkOrderRules checks the order against the product, geo, segment and condition rules of the campaign, the time
+// based conditions as of the given time. The allocation checks them again as of when the order was placed, it returns
+// an *IneligibleError when the order does not qualify.
+func (rewardUseCase *RewardUseCaseImpl) checkOrderRules(campaign *dtos.CampaignDTO, orderDTO *dtos.OrderDTO, at time.Time) error {
+	if err := entities.CheckProductRules(campaign.EligibilityCriteria.ProductRules, orderDTO.Items); err != nil {
+		return ineligible(dtos.ReasonProductRulesNotMet, "%v", err)
+	}
+
+	if err := entities.CheckGeoTargeting(campaign.EligibilityCriteria.GeoTargeting, orderDTO.Location); err != nil {
+		return ineligible(dtos.ReasonOutsideTargetArea, "%v", err)
+	}
+
+	if segmentIDs := campaign.EligibilityCriteria.SegmentIDs; len(segmentIDs) > 0 {
+		if orderDTO.UserID == "" {
+			return ineligible(dtos.ReasonNotInSegment, "campaign %s targets segments %v and the order has no user", campaign.ID, segmentIDs)
+		}
+		inSegment, err := rewardUseCase.proxies.SegmentProxy.IsInAnySegment(orderDTO.UserID, segmentIDs)
+		if err != nil {
+			return fmt.Errorf("failed to look up segments of user %s: %w", orderDTO.UserID, err)
+		}
+		if !inSegment {
+			return ineligible(dtos.ReasonNotInSegment, "user %s is in none of the segments %v", orderDTO.UserID, segmentIDs)
+		}
+	}
+
+	return rewardUseCase.checkConditions(campaign, orderDTO, at)
+}
+
+// checkConditions evaluates the condition expressions of the campaign and of the reward items it gives out, now.* as
+// of the given time. Every expression has to hold, as the whole reward group is allocated.
+func (rewardUseCase *RewardUseCaseImpl) checkConditions(campaign *dtos.CampaignDTO, orderDTO *dtos.OrderDTO, at time.Time) error {
+	expressions := []string{campaign.EligibilityCriteria.Conditions}
+	rewardItems, err := rewardUseCase.rewardRepo.GetRewardItemsFromRewardGroup(campaign.RewardGroupID)
+	if err != nil {
+		return err
+	}
+	for _, item := range rewardItems {
+		expression, err := item.ConditionExpression()
+		if err != nil {
+			return fmt.Errorf("reward item %s: %w", item.RewardItemID, err)
+		}
+		expressions = append(expressions, expression)
+	}
+
+	var ctx *conditions.Context
+	for _, expression := range expressions {
+		if expression == "" {
+			continue
+		}
+		// the user is only looked up once an expression needs to be evaluated
+		if ctx == nil {
+			var user *dtos.UserDetail
+			if orderDTO.UserID != "" {
+				user = rewardUseCase.proxies.UserProxy.GetUserDetails(orderDTO.UserID)
+			}
+			if ctx, err = entities.NewConditionContext(campaign, orderDTO, user, at); err != nil {
+				return err
+			}
+		}
+		if err := entities.CheckConditions(expression, ctx); err != nil {
+			if errors.Is(err, entities.ErrConditionsNotMet) {
+				return ineligible(dtos.ReasonConditionsNotMet, "campaign %s: %v", campaign.ID, err)
+			}
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package usecase
+
+import (
+	"github.com/craftizmv/rewards/internal/data/dtos"
+	"github.com/craftizmv/rewards/internal/domain/entities"
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+type CampaignUseCase interface {
+	CreateCampaign(campaign *dtos.CampaignDTO) (*dtos.CampaignDTO, error)
+	UpdateCampaign(id uuid.UUID, campaign *dtos.CampaignDTO) (*dtos.CampaignDTO, error)
+	GetCampaign(id uuid.UUID) (*dtos.CampaignDTO, error)
+	ListCampaigns(status dtos.Status) ([]*dtos.CampaignDTO, error)
+	ListCampaignStatusChanges(id uuid.UUID) ([]*entities.CampaignStatusChange, error)
+	ChangeCampaignStatus(id uuid.UUID, target dtos.Status, actor string, reason string) (*dtos.CampaignDTO, error)
+	// RunScheduledTransitions starts and ends the campaigns whose dates have been reached
+	RunScheduledTransitions(now time.Time) error
+}
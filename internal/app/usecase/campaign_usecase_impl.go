@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	. "github.com/craftizmv/rewards/internal/app/repository"
+	"github.com/craftizmv/rewards/internal/data/dtos"
+	"github.com/craftizmv/rewards/internal/domain/entities"
+	"github.com/craftizmv/rewards/pkg/logger"
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+// CampaignUseCaseImpl implements the campaign administration use cases
+type CampaignUseCaseImpl struct {
+	campaignRepo CampaignRepository
+	budgetRepo   CampaignBudgetRepository
+	validator    entities.CampaignValidator
+	log          logger.ILogger
+}
+
+// NewCampaignUseCaseImpl injects dependencies into the CampaignUseCaseImpl
+func NewCampaignUseCaseImpl(campaignRepo CampaignRepository, budgetRepo CampaignBudgetRepository, validator entities.CampaignValidator, log logger.ILogger) *CampaignUseCaseImpl {
+	return &CampaignUseCaseImpl{
+		campaignRepo: campaignRepo,
+		budgetRepo:   budgetRepo,
+		validator:    validator,
+		log:          log,
+	}
+}
+
+// schedulerActor is recorded in the audit trail for the transitions done by the campaign scheduler
+const schedulerActor = "scheduler"
+
+// CreateCampaign validates and stores a new campaign as a draft, it goes live through the lifecycle transitions
+func (campaignUseCase *CampaignUseCaseImpl) CreateCampaign(campaign *dtos.CampaignDTO) (*dtos.CampaignDTO, error) {
+	if uuid.Equal(campaign.ID, uuid.Nil) {
+		campaign.ID = uuid.NewV4()
+	}
+	campaign.Status = dtos.Draft
+	// allocations are tracked by the service, they can not be seeded by the caller
+	campaign.AllocatedRewards = 0
+
+	if err := campaignUseCase.validator.Validate(campaign, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := campaignUseCase.campaignRepo.CreateCampaign(campaign); err != nil {
+		campaignUseCase.log.Error("failed to create campaign", "error", err)
+		return nil, err
+	}
+
+	campaignUseCase.log.Info("campaign created", "campaignID", campaign.ID)
+	return campaign, nil
+}
+
+// UpdateCampaign validates and stores the editable fields of an existing campaign.
+// Status and allocation counters are left untouched, they change through their own flows.
+func (campaignUseCase *CampaignUseCaseImpl) UpdateCampaign(id uuid.UUID, campaign *dtos.CampaignDTO) (*dtos.CampaignDTO, error) {
+	existing, err := campaignUseCase.campaignRepo.GetCampaignByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing.Status == dtos.Ended || existing.Status == dtos.Archived {
+		return nil, fmt.Errorf("%w: campaign %s is %s", entities.ErrCampaignReadOnly, id, existing.Status)
+	}
+
+	campaign.ID = existing.ID
+	campaign.Status = existing.Status
+	campaign.AllocatedRewards = existing.AllocatedRewards
+
+	if err := campaignUseCase.validator.Validate(campaign, time.Now()); err != nil {
+		return nil, err
+	}
+
+	spent, err := campaignUseCase.budgetRepo.GetSpentBudget(id)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Budget < spent {
+		return nil, fmt.Errorf("%w: budget (%f) is lower than spent (%f)", entities.ErrBudgetBelowSpent, campaign.Budget, spent)
+	}
+
+	if err := campaignUseCase.campaignRepo.UpdateCampaign(campaign); err != nil {
+		campaignUseCase.log.Error("failed to update campaign", "campaignID", id, "error", err)
+		return nil, err
+	}
+
+	return campaign, nil
+}
+
+func (campaignUseCase *CampaignUseCaseImpl) GetCampaign(id uuid.UUID) (*dtos.CampaignDTO, error) {
+	return campaignUseCase.campaignRepo.GetCampaignByID(id)
+}
+
+func (campaignUseCase *CampaignUseCaseImpl) ListCampaigns(status dtos.Status) ([]*dtos.CampaignDTO, error) {
+	return campaignUseCase.campaignRepo.ListCampaigns(status)
+}
+
+func (campaignUseCase *CampaignUseCaseImpl) ListCampaignStatusChanges(id uuid.UUID) ([]*entities.CampaignStatusChange, error) {
+	if _, err := campaignUseCase.campaignRepo.GetCampaignByID(id); err != nil {
+		return nil, err
+	}
+	return campaignUseCase.campaignRepo.ListCampaignStatusChanges(id)
+}
+
+// ChangeCampaignStatus moves the campaign through the lifecycle state machine and records who did it
+func (campaignUseCase *CampaignUseCaseImpl) ChangeCampaignStatus(id uuid.UUID, target dtos.Status, actor string, reason string) (*dtos.CampaignDTO, error) {
+	campaign, err := campaignUseCase.campaignRepo.GetCampaignByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := campaignUseCase.transition(campaign, target, actor, reason, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return campaign, nil
+}
+
+// RunScheduledTransitions activates the scheduled campaigns which reached their start date and ends
+// the running ones which passed their end date. A failing campaign does not stop the others.
+func (campaignUseCase *CampaignUseCaseImpl) RunScheduledTransitions(now time.Time) error {
+	campaigns, err := campaignUseCase.campaignRepo.GetCampaignsDueForTransition(now)
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for _, campaign := range campaigns {
+		target, due := entities.ScheduledTransition(campaign, now)
+		if !due {
+			continue
+		}
+
+		err := campaignUseCase.transition(campaign, target, schedulerActor, "scheduled at "+now.Format(time.RFC3339), now)
+		if errors.Is(err, ErrCampaignStatusChanged) {
+			// another replica or an admin got there first
+			continue
+		}
+		if err != nil {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d scheduled campaign transitions failed", failed, len(campaigns))
+	}
+	return nil
+}
+
+// transition applies the state machine to the campaign and persists the change with its audit record
+func (campaignUseCase *CampaignUseCaseImpl) transition(campaign *dtos.CampaignDTO, target dtos.Status, actor string, reason string, now time.Time) error {
+	change, err := entities.TransitionCampaign(campaign, target, actor, reason, now)
+	if err != nil {
+		return err
+	}
+
+	if err := campaignUseCase.validator.Validate(campaign, now); err != nil {
+		return err
+	}
+
+	if err := campaignUseCase.campaignRepo.TransitionCampaignStatus(change); err != nil {
+		campaignUseCase.log.Error("failed to update campaign status", "campaignID", campaign.ID, "error", err)
+		return err
+	}
+
+	campaignUseCase.log.Info("campaign status changed", "campaignID", campaign.ID, "from", change.From, "to", change.To, "actor", actor)
+	return nil
+}
@@ -1,7 +1,11 @@
 package helper
 
 import (
+	"fmt"
+	"github.com/craftizmv/rewards/internal/data/dtos"
 	. "github.com/craftizmv/rewards/internal/domain/entities"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -28,3 +32,54 @@ func CreateOrderRewardItems(orderID int64, rewardItemIDs []int64) []*OrderReward
 	// Return the slice of OrderRewardItem pointers
 	return orderRewardItems
 }
+
+// CreateOrderItemDTOs converts the line items of an order into the DTOs used by the eligibility checks
+func CreateOrderItemDTOs(items []OrderItem) []dtos.OrderItemDTO {
+	itemDTOs := make([]dtos.OrderItemDTO, len(items))
+	for i, item := range items {
+		itemDTOs[i] = dtos.OrderItemDTO{
+			ProductID: item.ProductID,
+			SKU:       item.SKU,
+			Category:  item.Category,
+			Quantity:  item.Quantity,
+			Price:     item.Price,
+		}
+	}
+	return itemDTOs
+}
+
+// RewardEmailBody builds the message sent when a reward is allocated, listing the voucher codes handed out with it
+func RewardEmailBody(voucherCodes []*VoucherCode) string {
+	body := "Hi, XZY"
+	if len(voucherCodes) == 0 {
+		return body
+	}
+
+	codes := make([]string, len(voucherCodes))
+	for i, code := range voucherCodes {
+		codes[i] = code.Code
+	}
+	return body + "\nYour voucher codes: " + strings.Join(codes, ", ")
+}
+
+// GiftChoiceEmailBody lists the gifts the user can choose from and until when
+func GiftChoiceEmailBody(choice *GiftChoice) string {
+	options := make([]string, len(choice.Options))
+	for i, productID := range choice.Options {
+		options[i] = strconv.FormatInt(productID, 10)
+	}
+	return fmt.Sprintf("\nChoose your gift out of products %s by %s, otherwise product %d is sent to you",
+		strings.Join(options, ", "), choice.Deadline.Format("2 Jan 2006 15:04"), choice.DefaultProductID)
+}
+
+// RewardExpiryReminderBody builds the message reminding the user of a reward which expires soon
+func RewardExpiryReminderBody(reward *ExpiringReward, now time.Time) string {
+	body := fmt.Sprintf("Hi, the reward of your order %d expires on %s", reward.Reward.OrderID, reward.DueAt.Format("2 Jan 2006"))
+	if days := reward.DaysLeft(now); days > 0 {
+		body += fmt.Sprintf(", %d days from now", days)
+	}
+	if balance := reward.Reward.Balance(); balance > 0 {
+		body += fmt.Sprintf(". %.2f of it is left to use", balance)
+	}
+	return body + "."
+}
@@ -10,6 +10,25 @@ func GetOrderKey(orderID int64) string {
 	return "order:" + strconv.FormatInt(orderID, 10)
 }
 
+func GetOrderLockKey(orderID int64) string {
+	return "lock:order:" + strconv.FormatInt(orderID, 10)
+}
+
+// GetDiscountIssueKey is the idempotency key of the discount issued for a reward item of an order
+func GetDiscountIssueKey(orderID int64, rewardItemID int64) string {
+	return "discount:" + strconv.FormatInt(orderID, 10) + ":" + strconv.FormatInt(rewardItemID, 10)
+}
+
+// GetPointsAccrualKey is the reference of the accrual of a reward item of an order. An accrual which was reversed
+// does not block a later allocation of the order, the attempt counts the accruals posted for the item before.
+func GetPointsAccrualKey(orderID int64, rewardItemID int64, attempt int) string {
+	key := "points:accrue:" + strconv.FormatInt(orderID, 10) + ":" + strconv.FormatInt(rewardItemID, 10)
+	if attempt > 0 {
+		key += ":" + strconv.Itoa(attempt)
+	}
+	return key
+}
+
 func GenerateRandomInt64() int64 {
 	n, _ := rand.Int(rand.Reader, big.NewInt(1<<63)) // Generate a random int64 value
 	id := n.Int64()
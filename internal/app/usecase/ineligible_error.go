@@ -0,0 +1,24 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/craftizmv/rewards/internal/data/dtos"
+)
+
+// IneligibleError is returned by the eligibility checks when an order does not qualify,
+// as opposed to the check itself failing.
+type IneligibleError struct {
+	Reason dtos.IneligibilityReason
+	Detail string
+}
+
+func (e *IneligibleError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Reason, e.Detail)
+}
+
+func ineligible(reason dtos.IneligibilityReason, format string, args ...interface{}) *IneligibleError {
+	return &IneligibleError{
+		Reason: reason,
+		Detail: fmt.Sprintf(format, args...),
+	}
+}
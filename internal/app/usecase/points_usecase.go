@@ -0,0 +1,17 @@
+package usecase
+
+import (
+	"github.com/craftizmv/rewards/internal/domain/entities"
+	"time"
+)
+
+type PointsUseCase interface {
+	GetPointsBalance(userID string) (*entities.PointsBalance, error)
+	// GetPointsHistory returns a page of the transactions of the user with their ledger entries, newest first
+	GetPointsHistory(userID string, limit, offset int) ([]*entities.PointsTransaction, error)
+	// RedeemPoints spends points of the user, a redemption whose reference was posted before is not applied again
+	RedeemPoints(userID string, points int64, reference string) (*entities.PointsTransaction, error)
+	// ExpireDuePoints expires what is left of up to batchSize accruals which are due at the given time.
+	// It returns how many accruals were handled.
+	ExpireDuePoints(at time.Time, batchSize int) (int, error)
+}
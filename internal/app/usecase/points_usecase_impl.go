@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"fmt"
+	. "github.com/craftizmv/rewards/internal/app/repository"
+	"github.com/craftizmv/rewards/internal/domain/entities"
+	"github.com/craftizmv/rewards/pkg/logger"
+	"time"
+)
+
+const (
+	defaultPointsHistoryLimit = 50
+	maxPointsHistoryLimit     = 500
+)
+
+// PointsUseCaseImpl implements the balance, history, redemption and expiry of loyalty points
+type PointsUseCaseImpl struct {
+	pointsRepo PointsLedgerRepository
+	log        logger.ILogger
+}
+
+// NewPointsUseCaseImpl injects dependencies into the PointsUseCaseImpl
+func NewPointsUseCaseImpl(pointsRepo PointsLedgerRepository, log logger.ILogger) *PointsUseCaseImpl {
+	return &PointsUseCaseImpl{
+		pointsRepo: pointsRepo,
+		log:        log,
+	}
+}
+
+// GetPointsBalance returns what the user holds along with the totals per transaction type
+func (pointsUseCase *PointsUseCaseImpl) GetPointsBalance(userID string) (*entities.PointsBalance, error) {
+	return pointsUseCase.pointsRepo.GetBalance(userID)
+}
+
+// GetPointsHistory returns a page of the transactions of the user, the page size is bounded
+func (pointsUseCase *PointsUseCaseImpl) GetPointsHistory(userID string, limit, offset int) ([]*entities.PointsTransaction, error) {
+	if limit <= 0 {
+		limit = defaultPointsHistoryLimit
+	}
+	if limit > maxPointsHistoryLimit {
+		limit = maxPointsHistoryLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return pointsUseCase.pointsRepo.ListTransactions(userID, limit, offset)
+}
+
+// RedeemPoints posts a redemption, it fails with ErrInsufficientPoints when the balance does not cover it
+func (pointsUseCase *PointsUseCaseImpl) RedeemPoints(userID string, points int64, reference string) (*entities.PointsTransaction, error) {
+	if reference == "" {
+		return nil, fmt.Errorf("%w: a reference is required", entities.ErrInvalidPoints)
+	}
+
+	redemption, err := entities.NewPointsTransaction(userID, entities.PointsRedeem, points, "points:redeem:"+reference)
+	if err != nil {
+		return nil, err
+	}
+	if err := pointsUseCase.pointsRepo.PostTransaction(redemption, nil); err != nil {
+		return nil, err
+	}
+
+	pointsUseCase.log.Info("redeemed points", "userID", userID, "points", points, "reference", reference)
+	return redemption, nil
+}
+
+// ExpireDuePoints expires the due accruals one by one, each in its own transaction
+func (pointsUseCase *PointsUseCaseImpl) ExpireDuePoints(at time.Time, batchSize int) (int, error) {
+	accruals, err := pointsUseCase.pointsRepo.ListAccrualsDueForExpiry(at, batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, accrual := range accruals {
+		expired, err := pointsUseCase.pointsRepo.ExpireAccrual(accrual.ID, at)
+		if err != nil {
+			return i, err
+		}
+		if expired != nil {
+			pointsUseCase.log.Info("expired points", "userID", accrual.UserID, "accrualID", accrual.ID, "points", expired.Points)
+		}
+	}
+	return len(accruals), nil
+}
@@ -0,0 +1,21 @@
+package usecase
+
+import "github.com/craftizmv/rewards/internal/domain/entities"
+
+type RewardCatalogUseCase interface {
+	CreateRewardGroup(group *entities.RewardGroup) (*entities.RewardGroup, error)
+	UpdateRewardGroup(id int64, group *entities.RewardGroup) (*entities.RewardGroup, error)
+	// GetRewardGroup returns the group with its reward items and products
+	GetRewardGroup(id int64) (*entities.RewardGroupCatalog, error)
+	ListRewardGroups() ([]*entities.RewardGroup, error)
+
+	// AddRewardItem creates a reward item and attaches it to the group
+	AddRewardItem(rewardGroupID int64, item *entities.RewardItem) (*entities.RewardItem, error)
+	UpdateRewardItem(id int64, item *entities.RewardItem) (*entities.RewardItem, error)
+	GetRewardItem(id int64) (*entities.RewardItem, error)
+	AttachRewardItem(rewardGroupID, rewardItemID int64) (*entities.RewardGroupCatalog, error)
+	DetachRewardItem(rewardGroupID, rewardItemID int64) (*entities.RewardGroupCatalog, error)
+
+	AddRewardGroupProduct(rewardGroupID, productID int64) (*entities.RewardGroupCatalog, error)
+	RemoveRewardGroupProduct(rewardGroupID, productID int64) (*entities.RewardGroupCatalog, error)
+}
@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"fmt"
+	. "github.com/craftizmv/rewards/internal/app/repository"
+	"github.com/craftizmv/rewards/internal/domain/entities"
+	"github.com/craftizmv/rewards/pkg/logger"
+	"strconv"
+)
+
+// RewardCatalogUseCaseImpl implements the administration of reward groups, reward items and their mappings
+type RewardCatalogUseCaseImpl struct {
+	catalogRepo RewardCatalogRepository
+	log         logger.ILogger
+}
+
+// NewRewardCatalogUseCaseImpl injects dependencies into the RewardCatalogUseCaseImpl
+func NewRewardCatalogUseCaseImpl(catalogRepo RewardCatalogRepository, log logger.ILogger) *RewardCatalogUseCaseImpl {
+	return &RewardCatalogUseCaseImpl{
+		catalogRepo: catalogRepo,
+		log:         log,
+	}
+}
+
+// CreateRewardGroup validates and stores a new reward group
+func (catalogUseCase *RewardCatalogUseCaseImpl) CreateRewardGroup(group *entities.RewardGroup) (*entities.RewardGroup, error) {
+	if err := group.Validate(); err != nil {
+		return nil, invalidCatalogEntry(err)
+	}
+	if err := catalogUseCase.catalogRepo.CreateRewardGroup(group); err != nil {
+		return nil, err
+	}
+
+	catalogUseCase.log.Info("reward group created", "rewardGroupID", group.ID, "name", group.Name)
+	return group, nil
+}
+
+// UpdateRewardGroup validates and overwrites an existing reward group
+func (catalogUseCase *RewardCatalogUseCaseImpl) UpdateRewardGroup(id int64, group *entities.RewardGroup) (*entities.RewardGroup, error) {
+	group.ID = id
+	if err := group.Validate(); err != nil {
+		return nil, invalidCatalogEntry(err)
+	}
+	if err := catalogUseCase.catalogRepo.UpdateRewardGroup(group); err != nil {
+		return nil, err
+	}
+
+	catalogUseCase.log.Info("reward group updated", "rewardGroupID", id)
+	return catalogUseCase.catalogRepo.GetRewardGroup(id)
+}
+
+func (catalogUseCase *RewardCatalogUseCaseImpl) GetRewardGroup(id int64) (*entities.RewardGroupCatalog, error) {
+	group, err := catalogUseCase.catalogRepo.GetRewardGroup(id)
+	if err != nil {
+		return nil, err
+	}
+	items, err := catalogUseCase.catalogRepo.ListRewardGroupItems(id)
+	if err != nil {
+		return nil, err
+	}
+	productIDs, err := catalogUseCase.catalogRepo.ListRewardGroupProductIDs(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.RewardGroupCatalog{
+		RewardGroup: group,
+		Items:       items,
+		ProductIDs:  productIDs,
+	}, nil
+}
+
+func (catalogUseCase *RewardCatalogUseCaseImpl) ListRewardGroups() ([]*entities.RewardGroup, error) {
+	return catalogUseCase.catalogRepo.ListRewardGroups()
+}
+
+// AddRewardItem validates and stores a new reward item and attaches it to the group
+func (catalogUseCase *RewardCatalogUseCaseImpl) AddRewardItem(rewardGroupID int64, item *entities.RewardItem) (*entities.RewardItem, error) {
+	if _, err := catalogUseCase.catalogRepo.GetRewardGroup(rewardGroupID); err != nil {
+		return nil, err
+	}
+	if err := item.NormalizeConditions(); err != nil {
+		return nil, invalidCatalogEntry(err)
+	}
+	if err := item.Validate(); err != nil {
+		return nil, invalidCatalogEntry(err)
+	}
+
+	if err := catalogUseCase.catalogRepo.CreateRewardItem(item); err != nil {
+		return nil, err
+	}
+	rewardItemID, err := strconv.ParseInt(item.RewardItemID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id %q of created reward item: %v", item.RewardItemID, err)
+	}
+	if err := catalogUseCase.catalogRepo.AttachRewardItem(rewardGroupID, rewardItemID); err != nil {
+		return nil, err
+	}
+
+	catalogUseCase.log.Info("reward item created", "rewardGroupID", rewardGroupID, "rewardItemID", item.RewardItemID, "type", item.Type)
+	return item, nil
+}
+
+// UpdateRewardItem validates and overwrites an existing reward item
+func (catalogUseCase *RewardCatalogUseCaseImpl) UpdateRewardItem(id int64, item *entities.RewardItem) (*entities.RewardItem, error) {
+	item.RewardItemID = strconv.FormatInt(id, 10)
+	if err := item.NormalizeConditions(); err != nil {
+		return nil, invalidCatalogEntry(err)
+	}
+	if err := item.Validate(); err != nil {
+		return nil, invalidCatalogEntry(err)
+	}
+	if err := catalogUseCase.catalogRepo.UpdateRewardItem(item); err != nil {
+		return nil, err
+	}
+
+	catalogUseCase.log.Info("reward item updated", "rewardItemID", id)
+	return catalogUseCase.catalogRepo.GetRewardItem(id)
+}
+
+func (catalogUseCase *RewardCatalogUseCaseImpl) GetRewardItem(id int64) (*entities.RewardItem, error) {
+	return catalogUseCase.catalogRepo.GetRewardItem(id)
+}
+
+// AttachRewardItem maps an existing reward item to the group
+func (catalogUseCase *RewardCatalogUseCaseImpl) AttachRewardItem(rewardGroupID, rewardItemID int64) (*entities.RewardGroupCatalog, error) {
+	mapping := entities.NewRewardGroupRewardItem(strconv.FormatInt(rewardGroupID, 10), strconv.FormatInt(rewardItemID, 10))
+	if err := mapping.Validate(); err != nil {
+		return nil, invalidCatalogEntry(err)
+	}
+	if _, err := catalogUseCase.catalogRepo.GetRewardGroup(rewardGroupID); err != nil {
+		return nil, err
+	}
+	if _, err := catalogUseCase.catalogRepo.GetRewardItem(rewardItemID); err != nil {
+		return nil, err
+	}
+
+	if err := catalogUseCase.catalogRepo.AttachRewardItem(rewardGroupID, rewardItemID); err != nil {
+		return nil, err
+	}
+	return catalogUseCase.GetRewardGroup(rewardGroupID)
+}
+
+func (catalogUseCase *RewardCatalogUseCaseImpl) DetachRewardItem(rewardGroupID, rewardItemID int64) (*entities.RewardGroupCatalog, error) {
+	if err := catalogUseCase.catalogRepo.DetachRewardItem(rewardGroupID, rewardItemID); err != nil {
+		return nil, err
+	}
+	return catalogUseCase.GetRewardGroup(rewardGroupID)
+}
+
+// AddRewardGroupProduct maps a product to the group, the product is then taken from the inventory and shipped on allocation
+func (catalogUseCase *RewardCatalogUseCaseImpl) AddRewardGroupProduct(rewardGroupID, productID int64) (*entities.RewardGroupCatalog, error) {
+	mapping := entities.NewRewardGroupRewardProduct(strconv.FormatInt(rewardGroupID, 10), strconv.FormatInt(productID, 10))
+	if err := mapping.Validate(); err != nil {
+		return nil, invalidCatalogEntry(err)
+	}
+	if _, err := catalogUseCase.catalogRepo.GetRewardGroup(rewardGroupID); err != nil {
+		return nil, err
+	}
+
+	if err := catalogUseCase.catalogRepo.AddRewardGroupProduct(rewardGroupID, productID); err != nil {
+		return nil, err
+	}
+	return catalogUseCase.GetRewardGroup(rewardGroupID)
+}
+
+func (catalogUseCase *RewardCatalogUseCaseImpl) RemoveRewardGroupProduct(rewardGroupID, productID int64) (*entities.RewardGroupCatalog, error) {
+	if err := catalogUseCase.catalogRepo.RemoveRewardGroupProduct(rewardGroupID, productID); err != nil {
+		return nil, err
+	}
+	return catalogUseCase.GetRewardGroup(rewardGroupID)
+}
+
+func invalidCatalogEntry(err error) error {
+	return fmt.Errorf("%w: %v", entities.ErrInvalidRewardCatalog, err)
+}
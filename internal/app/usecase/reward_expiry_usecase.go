@@ -0,0 +1,11 @@
+package usecase
+
+import "time"
+
+type RewardExpiryUseCase interface {
+	// ExpireDueRewards expires up to batchSize unredeemed rewards and voucher codes which are due at the given time.
+	// It returns how many were expired.
+	ExpireDueRewards(at time.Time, batchSize int) (int, error)
+	// SendExpiryReminders reminds the owners of up to batchSize rewards which expire before remindBefore
+	SendExpiryReminders(now time.Time, remindBefore time.Time, batchSize int) (int, error)
+}
@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/craftizmv/rewards/internal/app/contracts"
+	. "github.com/craftizmv/rewards/internal/app/repository"
+	"github.com/craftizmv/rewards/internal/app/usecase/helper"
+	"github.com/craftizmv/rewards/internal/data/infrastructure/queue/events"
+	"github.com/craftizmv/rewards/internal/domain/entities"
+	"github.com/craftizmv/rewards/pkg/logger"
+	"time"
+)
+
+// RewardExpiryUseCaseImpl expires the rewards which were never redeemed and gives back what they held
+type RewardExpiryUseCaseImpl struct {
+	expiryRepo  RewardExpiryRepository
+	voucherRepo VoucherRepository
+	publisher   contracts.EventPublisher
+	log         logger.ILogger
+	proxies     *RewardProxies
+}
+
+// NewRewardExpiryUseCaseImpl injects dependencies into the RewardExpiryUseCaseImpl
+func NewRewardExpiryUseCaseImpl(expiryRepo RewardExpiryRepository, voucherRepo VoucherRepository, publisher contracts.EventPublisher, log logger.ILogger, proxies *RewardProxies) *RewardExpiryUseCaseImpl {
+	return &RewardExpiryUseCaseImpl{
+		expiryRepo:  expiryRepo,
+		voucherRepo: voucherRepo,
+		publisher:   publisher,
+		log:         log,
+		proxies:     proxies,
+	}
+}
+
+// ExpireDueRewards expires the due rewards first and then puts the unused voucher codes of expired reward groups back
+// in their pools, codes of expired pools are voided instead
+func (expiryUseCase *RewardExpiryUseCaseImpl) ExpireDueRewards(at time.Time, batchSize int) (int, error) {
+	rewards, err := expiryUseCase.expiryRepo.ListRewardsDueForExpiry(at, batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, reward := range rewards {
+		ok, err := expiryUseCase.expireReward(reward, at)
+		if err != nil {
+			return expired, err
+		}
+		if ok {
+			expired++
+		}
+	}
+
+	codes, err := expiryUseCase.voucherRepo.ReclaimExpiredCodes(at, batchSize)
+	if err != nil {
+		return expired, err
+	}
+	for _, code := range codes {
+		event := &events.RewardExpired{
+			VoucherCode: code.Code,
+			UserID:      code.UserID,
+			ExpiredAt:   at,
+		}
+		if code.OrderID != nil {
+			event.OrderID = *code.OrderID
+		}
+		expiryUseCase.publishExpired(event)
+	}
+	expired += len(codes)
+
+	return expired, nil
+}
+
+// expireReward marks the reward as expired and gives back the inventory or the discount it held.
+// It returns false when the reward was redeemed or expired by another run in the meantime.
+func (expiryUseCase *RewardExpiryUseCaseImpl) expireReward(reward *entities.ExpiringReward, at time.Time) (bool, error) {
+	item := reward.Reward
+
+	// marking it first keeps a concurrent redemption or sweeper run from using it
+	ok, err := expiryUseCase.expiryRepo.ExpireReward(item.ID, at)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	if reward.HoldsInventory() {
+		if !expiryUseCase.proxies.InventoryProxy.ReleaseInventoryItems(item.InventoryItemIDs) {
+			expiryUseCase.log.Error("failed to release inventory of expired reward", "rewardID", item.ID, "itemIDs", item.InventoryItemIDs)
+		}
+	}
+	if item.IssuedRef != nil {
+		if err := expiryUseCase.proxies.DiscountProxy.RevokeDiscount(*item.IssuedRef); err != nil {
+			expiryUseCase.log.Error("failed to revoke discount of expired reward", "rewardID", item.ID, "reference", *item.IssuedRef, "error", err)
+		}
+	}
+
+	rewardID := item.ID
+	expiryUseCase.publishExpired(&events.RewardExpired{
+		RewardID:     &rewardID,
+		RewardItemID: item.RewardItemID,
+		UserID:       item.UserID,
+		OrderID:      item.OrderID,
+		ExpiredAt:    at,
+	})
+
+	expiryUseCase.log.Info("reward expired", "rewardID", item.ID, "orderID", item.OrderID, "dueAt", reward.DueAt)
+	return true, nil
+}
+
+// publishExpired failures are only logged, the expiry itself is already recorded
+func (expiryUseCase *RewardExpiryUseCaseImpl) publishExpired(event *events.RewardExpired) {
+	if err := expiryUseCase.publisher.PublishMessage(event); err != nil {
+		expiryUseCase.log.Error("failed to publish reward expired event", "orderID", event.OrderID, "error", err)
+	}
+}
+
+// SendExpiryReminders emails the owners of the rewards which expire soon, once per reward
+func (expiryUseCase *RewardExpiryUseCaseImpl) SendExpiryReminders(now time.Time, remindBefore time.Time, batchSize int) (int, error) {
+	rewards, err := expiryUseCase.expiryRepo.ListRewardsDueForReminder(now, remindBefore, batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	reminded := 0
+	for _, reward := range rewards {
+		item := reward.Reward
+		userDetail := expiryUseCase.proxies.UserProxy.GetUserDetails(item.UserID)
+		if userDetail == nil {
+			expiryUseCase.log.Warn("no user to remind of expiring reward", "rewardID", item.ID, "userID", item.UserID)
+			continue
+		}
+
+		err := expiryUseCase.proxies.EmailProxy.SendEmail(userDetail.UserName, userDetail.Email, helper.RewardExpiryReminderBody(reward, now))
+		if err != nil {
+			// not marked, so the next run tries again
+			expiryUseCase.log.Error("failed to send expiry reminder", "rewardID", item.ID, "error", err)
+			continue
+		}
+
+		if err := expiryUseCase.expiryRepo.MarkReminded(item.ID, now); err != nil {
+			return reminded, fmt.Errorf("reminder for reward %d was sent but not recorded: %w", item.ID, err)
+		}
+		reminded++
+	}
+
+	return reminded, nil
+}
@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/craftizmv/rewards/internal/data/dtos"
+	"github.com/craftizmv/rewards/internal/data/infrastructure/lock"
+	"github.com/craftizmv/rewards/internal/data/infrastructure/queue/events"
+	"github.com/craftizmv/rewards/internal/domain/entities"
+	"strconv"
+	"time"
+)
+
+// availableGiftOptions returns the products of a choose-one group which are in stock, only those can be offered.
+// It fails when the inventory could not be asked, rather than taking the products for out of stock.
+func (rewardUseCase *RewardUseCaseImpl) availableGiftOptions(productIDs []int64) ([]int64, error) {
+	var options []int64
+	for _, productID := range productIDs {
+		ok, _, err := rewardUseCase.proxies.InventoryProxy.BulkVerifyInventoryAvailability([]int64{productID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to check the stock of gift option %d: %w", productID, err)
+		}
+		if ok {
+			options = append(options, productID)
+		}
+	}
+	return options, nil
+}
+
+// offerGiftChoice records a pending choice between the options instead of shipping the products of the group.
+// Nothing is blocked in the inventory until the user picked a product.
+func (rewardUseCase *RewardUseCaseImpl) offerGiftChoice(event events.AllocateReward, orderLock lock.Lock, rewardGroup *entities.RewardGroup, options []int64) (*entities.GiftChoice, error) {
+	choice, err := entities.NewGiftChoice(rewardGroup, event.OrderID, event.UserID, event.CampaignID, options, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rewardUseCase.ensureLockHeld(orderLock); err != nil {
+		return nil, err
+	}
+	if err := rewardUseCase.giftChoiceRepo.CreateGiftChoice(choice, orderFence(orderLock)); err != nil {
+		rewardUseCase.log.Error("failed to offer gift choice", "orderID", event.OrderID, "rewardGroupID", rewardGroup.ID, "error", err)
+		return nil, err
+	}
+
+	rewardUseCase.log.Info("offered gift choice", "choiceID", choice.ID, "orderID", event.OrderID, "options", choice.Options, "deadline", choice.Deadline)
+	return choice, nil
+}
+
+// withdrawGiftChoices cancels the choices of the order the user did not make yet, so nothing ships afterwards.
+// Failures are only logged, as for the budget credit.
+func (rewardUseCase *RewardUseCaseImpl) withdrawGiftChoices(orderID int64, orderLock lock.Lock) {
+	cancelled, err := rewardUseCase.giftChoiceRepo.CancelGiftChoices(orderID, time.Now(), orderFence(orderLock))
+	if err != nil {
+		rewardUseCase.log.Error("failed to cancel gift choices", "orderID", orderID, "error", err)
+		return
+	}
+	if cancelled > 0 {
+		rewardUseCase.log.Info("cancelled gift choices", "orderID", orderID, "count", cancelled)
+	}
+}
+
+// GetGiftChoice returns a gift choice with its options
+func (rewardUseCase *RewardUseCaseImpl) GetGiftChoice(id int64) (*entities.GiftChoice, error) {
+	return rewardUseCase.giftChoiceRepo.GetGiftChoice(id)
+}
+
+// ChooseGift ships the product the user picked out of a pending gift choice
+func (rewardUseCase *RewardUseCaseImpl) ChooseGift(id int64, request *dtos.ChooseGiftRequest) (*entities.GiftChoice, error) {
+	choice, err := rewardUseCase.giftChoiceRepo.GetGiftChoice(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = rewardUseCase.withOrderLock(choice.OrderID, func(orderLock lock.Lock) error {
+		// read it again under the lock, a concurrent choice or the deadline may have settled it meanwhile
+		choice, err := rewardUseCase.giftChoiceRepo.GetGiftChoice(id)
+		if err != nil {
+			return err
+		}
+		if err := choice.CheckChoice(request.UserID, request.ProductID, time.Now()); err != nil {
+			return err
+		}
+		return rewardUseCase.settleGiftChoice(choice, request.ProductID, entities.GiftChoiceChosen, orderLock)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rewardUseCase.giftChoiceRepo.GetGiftChoice(id)
+}
+
+// DefaultDueGiftChoices ships the default product of up to limit choices whose deadline passed.
+// An out of stock default falls back to another option which is in stock. A choice none of whose options are in
+// stock is cancelled and the cost of its products credited back to the campaign budget, a choice which failed to ship
+// otherwise stays pending and is tried again on the next run.
+func (rewardUseCase *RewardUseCaseImpl) DefaultDueGiftChoices(at time.Time, limit int) (int, error) {
+	choices, err := rewardUseCase.giftChoiceRepo.ListDueGiftChoices(at, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	defaulted := 0
+	for _, due := range choices {
+		err := rewardUseCase.withOrderLock(due.OrderID, func(orderLock lock.Lock) error {
+			choice, err := rewardUseCase.giftChoiceRepo.GetGiftChoice(due.ID)
+			if err != nil {
+				return err
+			}
+			if choice.Status != entities.GiftChoicePending {
+				return nil
+			}
+			// the choice stays pending for the next run when the stock could not be checked
+			productIDs, err := rewardUseCase.availableGiftOptions(defaultFirst(choice))
+			if err != nil {
+				return err
+			}
+			if len(productIDs) == 0 {
+				rewardUseCase.log.Info("no gift option is in stock, cancelling the choice", "choiceID", choice.ID, "orderID", choice.OrderID)
+				return rewardUseCase.cancelGiftChoice(choice, orderLock)
+			}
+
+			for _, productID := range productIDs {
+				if err = rewardUseCase.settleGiftChoice(choice, productID, entities.GiftChoiceDefaulted, orderLock); err == nil {
+					defaulted++
+					return nil
+				}
+			}
+			return err
+		})
+		if err != nil {
+			rewardUseCase.log.Error("failed to ship default gift", "choiceID", due.ID, "orderID", due.OrderID, "error", err)
+		}
+	}
+
+	return defaulted, nil
+}
+
+// cancelGiftChoice cancels a choice of which nothing can ship and credits the cost of its products back to the campaign
+// budget. The rest of the reward group was handed out, so the allocation and the rest of its cost stay booked.
+func (rewardUseCase *RewardUseCaseImpl) cancelGiftChoice(choice *entities.GiftChoice, orderLock lock.Lock) error {
+	rewardItems, err := rewardUseCase.rewardRepo.GetRewardItemsFromRewardGroup(choice.RewardGroupID)
+	if err != nil {
+		return err
+	}
+	productCost := 0.0
+	for _, item := range rewardItems {
+		if item.Type == entities.RewardTypeProduct {
+			productCost += item.Cost
+		}
+	}
+
+	if err := rewardUseCase.ensureLockHeld(orderLock); err != nil {
+		return err
+	}
+	if err := rewardUseCase.giftChoiceRepo.CancelGiftChoice(choice.ID, time.Now(), orderFence(orderLock)); err != nil {
+		return err
+	}
+	rewardUseCase.log.Info("cancelled gift choice", "choiceID", choice.ID, "orderID", choice.OrderID)
+
+	if productCost == 0 {
+		return nil
+	}
+	// failures are only logged, as for the budget credit
+	err = rewardUseCase.budgetRepo.Refund(&entities.BudgetEntry{
+		CampaignID: choice.CampaignID,
+		OrderID:    choice.OrderID,
+		Type:       entities.BudgetEntryRefund,
+		Amount:     -productCost,
+	}, orderFence(orderLock))
+	if err != nil {
+		rewardUseCase.log.Error("failed to refund gift choice", "choiceID", choice.ID, "campaignID", choice.CampaignID, "amount", productCost, "error", err)
+		return nil
+	}
+	rewardUseCase.log.Info("refunded gift choice", "choiceID", choice.ID, "campaignID", choice.CampaignID, "amount", productCost)
+	return nil
+}
+
+// defaultFirst returns the options of the choice with the default product first
+func defaultFirst(choice *entities.GiftChoice) []int64 {
+	productIDs := []int64{choice.DefaultProductID}
+	for _, productID := range choice.Options {
+		if productID != choice.DefaultProductID {
+			productIDs = append(productIDs, productID)
+		}
+	}
+	return productIDs
+}
+
+// settleGiftChoice ships the product and closes the choice, the choice goes back to pending if the product could not
+// be shipped. Claiming the choice first makes sure a concurrent choice or the deadline does not ship a second product.
+func (rewardUseCase *RewardUseCaseImpl) settleGiftChoice(choice *entities.GiftChoice, productID int64, status entities.GiftChoiceStatus, orderLock lock.Lock) error {
+	userDetail := rewardUseCase.proxies.UserProxy.GetUserDetails(choice.UserID)
+	if userDetail == nil {
+		return fmt.Errorf("user %s of gift choice %d not found", choice.UserID, choice.ID)
+	}
+
+	if err := rewardUseCase.ensureLockHeld(orderLock); err != nil {
+		return err
+	}
+	if err := rewardUseCase.giftChoiceRepo.ClaimGiftChoice(choice.ID, orderFence(orderLock)); err != nil {
+		return err
+	}
+
+	event := events.AllocateReward{
+		UserID:       choice.UserID,
+		OrderID:      choice.OrderID,
+		CampaignID:   choice.CampaignID,
+		RewardTypeID: choice.RewardGroupID,
+	}
+	shipment, err := rewardUseCase.shipRewardProducts(event, orderLock, []int64{productID}, userDetail)
+	if err != nil {
+		rewardUseCase.log.Error("failed to ship chosen gift", "choiceID", choice.ID, "productID", productID, "error", err)
+		if releaseErr := rewardUseCase.giftChoiceRepo.ReleaseGiftChoice(choice.ID, orderFence(orderLock)); releaseErr != nil {
+			rewardUseCase.log.Error("failed to release gift choice", "choiceID", choice.ID, "error", releaseErr)
+		}
+		return err
+	}
+
+	// the product shipped, so the choice is settled even if the owned row could not be recorded
+	if err := rewardUseCase.recordChosenGift(event, orderLock, productID, shipment); err != nil {
+		rewardUseCase.log.Error("failed to record chosen gift", "choiceID", choice.ID, "productID", productID, "error", err)
+	}
+
+	if err := rewardUseCase.ensureLockHeld(orderLock); err != nil {
+		return err
+	}
+	if err := rewardUseCase.giftChoiceRepo.SettleGiftChoice(choice.ID, status, productID, time.Now(), orderFence(orderLock)); err != nil {
+		rewardUseCase.log.Error("failed to settle gift choice", "choiceID", choice.ID, "productID", productID, "error", err)
+		return err
+	}
+
+	rewardUseCase.log.Info("shipped gift choice", "choiceID", choice.ID, "orderID", choice.OrderID, "productID", productID, "status", status)
+	return nil
+}
+
+// recordChosenGift records the product item of the group which shipped as owned by the user, with its shipment
+func (rewardUseCase *RewardUseCaseImpl) recordChosenGift(event events.AllocateReward, orderLock lock.Lock, productID int64, shipment *rewardShipment) error {
+	rewardItems, err := rewardUseCase.rewardRepo.GetRewardItemsFromRewardGroup(event.RewardTypeID)
+	if err != nil {
+		return err
+	}
+
+	chosen := strconv.FormatInt(productID, 10)
+	for _, item := range rewardItems {
+		if item.Type != entities.RewardTypeProduct || item.ProductID == nil || *item.ProductID != chosen {
+			continue
+		}
+		orderRewardItem, err := ownedRewardItem(event, item)
+		if err != nil {
+			return err
+		}
+		shipment.record(orderRewardItem, item)
+		if err := rewardUseCase.ensureLockHeld(orderLock); err != nil {
+			return err
+		}
+		if err := rewardUseCase.rewardRepo.InsertOrderRewardItems([]*entities.OrderRewardItem{orderRewardItem}, orderFence(orderLock)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
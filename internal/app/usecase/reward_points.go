@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/craftizmv/rewards/internal/app/usecase/helper"
+	"github.com/craftizmv/rewards/internal/data/infrastructure/lock"
+	"github.com/craftizmv/rewards/internal/data/infrastructure/queue/events"
+	"github.com/craftizmv/rewards/internal/domain/entities"
+	"strconv"
+	"time"
+)
+
+// accruePoints credits the points items of the reward group to the user, one accrual per item.
+// An item whose accrual of the order still stands is not credited twice. An accrual reversed by the
+// compensation of a failed attempt does not count, the item is credited again under a new reference.
+func (rewardUseCase *RewardUseCaseImpl) accruePoints(event events.AllocateReward, orderLock lock.Lock, rewardItems []*entities.RewardItem) error {
+	var prior []*entities.PointsTransaction
+	loaded := false
+	for _, item := range rewardItems {
+		if item.Type != entities.RewardTypePoints {
+			continue
+		}
+		if !loaded {
+			accruals, err := rewardUseCase.pointsRepo.GetOrderAccruals(event.OrderID)
+			if err != nil {
+				rewardUseCase.log.Error("failed to fetch points accruals", "orderID", event.OrderID, "error", err)
+				return err
+			}
+			prior, loaded = accruals, true
+		}
+		rewardItemID, err := strconv.ParseInt(item.RewardItemID, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid reward item id %q: %v", item.RewardItemID, err)
+		}
+
+		attempt, credited := 0, false
+		for _, accrual := range prior {
+			if accrual.RewardItemID == nil || *accrual.RewardItemID != rewardItemID {
+				continue
+			}
+			attempt++
+			credited = credited || accrual.Remaining > 0
+		}
+		if credited {
+			rewardUseCase.log.Info("points already accrued", "orderID", event.OrderID, "rewardItemID", item.RewardItemID)
+			continue
+		}
+
+		accrual, err := entities.NewPointsTransaction(event.UserID, entities.PointsAccrue, *item.Points, helper.GetPointsAccrualKey(event.OrderID, rewardItemID, attempt))
+		if err != nil {
+			return err
+		}
+		orderID, campaignID := event.OrderID, event.CampaignID
+		accrual.OrderID = &orderID
+		accrual.CampaignID = &campaignID
+		accrual.RewardItemID = &rewardItemID
+		accrual.ExpiresAt = item.ExpirationDate
+
+		if err := rewardUseCase.pointsRepo.PostTransaction(accrual, orderFence(orderLock)); err != nil {
+			rewardUseCase.log.Error("failed to accrue points", "orderID", event.OrderID, "rewardItemID", item.RewardItemID, "error", err)
+			return err
+		}
+		rewardUseCase.log.Info("accrued points", "orderID", event.OrderID, "userID", event.UserID, "points", accrual.Points)
+	}
+	return nil
+}
+
+// reversePoints claws back what is left of every accrual of the order, points which expired meanwhile are not taken twice.
+// Failures are only logged, as for the budget credit, a reversal can be repeated safely.
+func (rewardUseCase *RewardUseCaseImpl) reversePoints(orderID int64) {
+	accruals, err := rewardUseCase.pointsRepo.GetOrderAccruals(orderID)
+	if err != nil {
+		rewardUseCase.log.Error("failed to fetch points accruals", "orderID", orderID, "error", err)
+		return
+	}
+
+	for _, accrual := range accruals {
+		reversal, err := rewardUseCase.pointsRepo.ReverseAccrual(accrual.ID, time.Now())
+		if err != nil {
+			rewardUseCase.log.Error("failed to reverse points", "orderID", orderID, "accrualID", accrual.ID, "error", err)
+			continue
+		}
+		if reversal != nil {
+			rewardUseCase.log.Info("reversed points", "orderID", orderID, "userID", reversal.UserID, "points", reversal.Points)
+		}
+	}
+}
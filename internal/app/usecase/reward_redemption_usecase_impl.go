@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"github.com/craftizmv/rewards/internal/data/dtos"
+	"github.com/craftizmv/rewards/internal/data/infrastructure/queue/events"
+	"github.com/craftizmv/rewards/internal/domain/entities"
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+// RedeemReward records a redemption of an allocated reward and returns what is left of it
+func (rewardUseCase *RewardUseCaseImpl) RedeemReward(id int64, request *dtos.RedeemRewardRequest) (*entities.RewardBalance, error) {
+	// without a reference from the caller every request is a new redemption
+	reference := request.Reference
+	if reference == "" {
+		reference = uuid.NewV4().String()
+	}
+
+	return rewardUseCase.redeem(&entities.RewardRedemption{
+		OrderRewardItemID: id,
+		UserID:            request.UserID,
+		OrderID:           request.OrderID,
+		Reference:         reference,
+		Amount:            request.Amount,
+		RedeemedAt:        time.Now(),
+	})
+}
+
+// RedeemRewardFromCheckout records a redemption reported by checkout
+func (rewardUseCase *RewardUseCaseImpl) RedeemRewardFromCheckout(event events.RedeemReward) error {
+	redeemedAt := event.RedeemedAt
+	if redeemedAt.IsZero() {
+		redeemedAt = time.Now()
+	}
+	orderID := event.OrderID
+
+	_, err := rewardUseCase.redeem(&entities.RewardRedemption{
+		OrderRewardItemID: event.RewardID,
+		UserID:            event.UserID,
+		OrderID:           &orderID,
+		Reference:         event.Reference,
+		Amount:            event.Amount,
+		RedeemedAt:        redeemedAt,
+	})
+	return err
+}
+
+func (rewardUseCase *RewardUseCaseImpl) redeem(redemption *entities.RewardRedemption) (*entities.RewardBalance, error) {
+	reward, err := rewardUseCase.redemptionRepo.RedeemReward(redemption)
+	if err != nil {
+		rewardUseCase.log.Error("failed to redeem reward", "rewardID", redemption.OrderRewardItemID, "userID", redemption.UserID, "reference", redemption.Reference, "error", err)
+		return nil, err
+	}
+
+	rewardUseCase.log.Info("reward redeemed", "rewardID", reward.ID, "userID", redemption.UserID, "amount", redemption.Amount, "balance", reward.Balance())
+	return rewardUseCase.rewardBalance(reward)
+}
+
+// GetRewardBalance returns what is left of an allocated reward along with its redemptions
+func (rewardUseCase *RewardUseCaseImpl) GetRewardBalance(id int64) (*entities.RewardBalance, error) {
+	reward, err := rewardUseCase.redemptionRepo.GetOrderRewardItem(id)
+	if err != nil {
+		return nil, err
+	}
+	return rewardUseCase.rewardBalance(reward)
+}
+
+func (rewardUseCase *RewardUseCaseImpl) rewardBalance(reward *entities.OrderRewardItem) (*entities.RewardBalance, error) {
+	redemptions, err := rewardUseCase.redemptionRepo.ListRedemptions(reward.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.RewardBalance{
+		Reward:      reward,
+		Balance:     reward.Balance(),
+		Redemptions: redemptions,
+	}, nil
+}
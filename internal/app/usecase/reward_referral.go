@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"github.com/craftizmv/rewards/internal/app/usecase/helper"
+	"github.com/craftizmv/rewards/internal/data/dtos"
+	"github.com/craftizmv/rewards/internal/data/infrastructure/lock"
+	"github.com/craftizmv/rewards/internal/data/infrastructure/queue/events"
+	"github.com/craftizmv/rewards/internal/domain/entities"
+	"time"
+)
+
+// ConfirmReferral attributes the first order of a referee to the referrer and rewards both sides through the
+// allocation flow. An order which does not qualify for the referral campaign is not attributed.
+// Redelivering the event only allocates the sides which are not rewarded yet, and the referral is only marked
+// rewarded once both sides got their reward. A side held for review completes the referral when it is approved.
+func (rewardUseCase *RewardUseCaseImpl) ConfirmReferral(event events.ReferralConfirmed) error {
+	referral, err := entities.NewReferral(event.CampaignID, event.ReferrerID, event.RefereeID, event.OrderID, time.Now())
+	if err != nil {
+		rewardUseCase.log.Error("invalid referral", "orderID", event.OrderID, "referrerID", event.ReferrerID, "refereeID", event.RefereeID, "error", err)
+		return err
+	}
+
+	campaign, err := rewardUseCase.proxies.CampaignProxy.FetchCampaign(event.CampaignID)
+	if err != nil {
+		return err
+	}
+	if !entities.IsReferralCampaign(campaign) {
+		return fmt.Errorf("%w: %s", entities.ErrNotReferralCampaign, campaign.ID)
+	}
+
+	// the order of the referee has to qualify for the campaign like any rewarded order
+	if err := rewardUseCase.qualifyReferral(campaign, event); err != nil {
+		var rejection *IneligibleError
+		if !errors.As(err, &rejection) {
+			return err
+		}
+		rewardUseCase.log.Info("referee order does not qualify for the referral", "orderID", event.OrderID, "campaignID", campaign.ID, "reason", rejection)
+		return nil
+	}
+
+	if err := rewardUseCase.referralRepo.AttributeReferral(referral); err != nil {
+		rewardUseCase.log.Error("failed to attribute referral", "orderID", event.OrderID, "campaignID", campaign.ID, "refereeID", event.RefereeID, "error", err)
+		return err
+	}
+	if referral.Status != entities.ReferralPending {
+		rewardUseCase.log.Info("referral was already processed", "referralID", referral.ID, "status", referral.Status)
+		return nil
+	}
+
+	allocation := events.AllocateReward{
+		CampaignID:      campaign.ID,
+		OrderValue:      event.OrderValue,
+		PlacedAt:        event.PlacedAt,
+		DeviceID:        event.DeviceID,
+		PaymentType:     event.PaymentType,
+		PaymentIssuer:   event.PaymentIssuer,
+		Channel:         event.Channel,
+		ReferredOrderID: event.OrderID,
+	}
+	for _, side := range referralSides(campaign, referral) {
+		sideAllocation := allocation
+		sideAllocation.UserID = side.userID
+		sideAllocation.OrderID = side.orderID
+		sideAllocation.RewardTypeID = side.rewardGroupID
+		if err := rewardUseCase.allocateReferralSide(sideAllocation); err != nil {
+			rewardUseCase.log.Error("failed to reward referral side", "referralID", referral.ID, "userID", side.userID, "error", err)
+			return err
+		}
+	}
+
+	return rewardUseCase.markReferralRewarded(campaign, referral)
+}
+
+// referralSide is one of the users a referral rewards, with the order id its reward is allocated against
+type referralSide struct {
+	userID        string
+	orderID       int64
+	rewardGroupID int64
+}
+
+// referralSides returns the sides of the referral the campaign rewards, the referee first
+func referralSides(campaign *dtos.CampaignDTO, referral *entities.Referral) []referralSide {
+	var sides []referralSide
+	rewards := campaign.EligibilityCriteria.Referral
+	if rewards.RefereeRewardGroupID > 0 {
+		sides = append(sides, referralSide{userID: referral.RefereeID, orderID: referral.OrderID, rewardGroupID: rewards.RefereeRewardGroupID})
+	}
+	if rewards.ReferrerRewardGroupID > 0 {
+		sides = append(sides, referralSide{userID: referral.ReferrerID, orderID: referral.ReferrerOrderID(), rewardGroupID: rewards.ReferrerRewardGroupID})
+	}
+	return sides
+}
+
+// allocateReferralSide allocates the reward of one side, a side which already holds its reward is skipped
+func (rewardUseCase *RewardUseCaseImpl) allocateReferralSide(event events.AllocateReward) error {
+	recorded, err := rewardUseCase.userRewardRepo.HasUserReward(event.UserID, event.CampaignID, event.OrderID)
+	if err != nil {
+		return err
+	}
+	if recorded {
+		rewardUseCase.log.Info("referral side was already rewarded", "userID", event.UserID, "orderID", event.OrderID)
+		return nil
+	}
+	return rewardUseCase.AllocateReward(event)
+}
+
+// markReferralRewarded moves the referral to rewarded once every side holds its reward.
+// A side held for review has no reward yet and leaves the referral pending.
+func (rewardUseCase *RewardUseCaseImpl) markReferralRewarded(campaign *dtos.CampaignDTO, referral *entities.Referral) error {
+	for _, side := range referralSides(campaign, referral) {
+		recorded, err := rewardUseCase.userRewardRepo.HasUserReward(side.userID, referral.CampaignID, side.orderID)
+		if err != nil {
+			return err
+		}
+		if !recorded {
+			rewardUseCase.log.Info("referral waits for a side to be rewarded", "referralID", referral.ID, "userID", side.userID)
+			return nil
+		}
+	}
+
+	if err := rewardUseCase.referralRepo.UpdateReferralStatus(referral.ID, entities.ReferralPending, entities.ReferralRewarded, time.Now()); err != nil {
+		rewardUseCase.log.Error("failed to mark referral rewarded", "referralID", referral.ID, "error", err)
+		return err
+	}
+	rewardUseCase.log.Info("rewarded referral", "referralID", referral.ID, "orderID", referral.OrderID, "referrerID", referral.ReferrerID, "refereeID", referral.RefereeID)
+
+	return nil
+}
+
+// completeReferral marks the referral of an approved referral allocation rewarded if its other side is rewarded too.
+// Failures are only logged, the approved reward itself is allocated.
+func (rewardUseCase *RewardUseCaseImpl) completeReferral(event events.AllocateReward) {
+	campaign, err := rewardUseCase.proxies.CampaignProxy.FetchCampaign(event.CampaignID)
+	if err != nil {
+		rewardUseCase.log.Error("failed to fetch referral campaign", "campaignID", event.CampaignID, "error", err)
+		return
+	}
+	referrals, err := rewardUseCase.referralRepo.ListReferralsByOrderID(event.ReferredOrderID)
+	if err != nil {
+		rewardUseCase.log.Error("failed to fetch referrals of order", "orderID", event.ReferredOrderID, "error", err)
+		return
+	}
+	for _, referral := range referrals {
+		if referral.CampaignID != event.CampaignID || referral.Status != entities.ReferralPending {
+			continue
+		}
+		if err := rewardUseCase.markReferralRewarded(campaign, referral); err != nil {
+			rewardUseCase.log.Error("failed to complete referral", "referralID", referral.ID, "error", err)
+		}
+	}
+}
+
+// qualifyReferral evaluates the order of the referee against the campaign, with the reward group one of the sides gets
+func (rewardUseCase *RewardUseCaseImpl) qualifyReferral(campaign *dtos.CampaignDTO, event events.ReferralConfirmed) error {
+	orderDTO := &dtos.OrderDTO{
+		OrderID:    event.OrderID,
+		UserID:     event.RefereeID,
+		OrderValue: float64(event.OrderValue),
+		Channel:    event.Channel,
+	}
+	if event.PaymentType != "" || event.PaymentIssuer != "" {
+		orderDTO.Payment = &dtos.PaymentMethod{Type: event.PaymentType, Issuer: event.PaymentIssuer}
+	}
+	if order, found := rewardUseCase.cache.Get(helper.GetOrderKey(event.OrderID)); found {
+		for _, item := range order.Items {
+			orderDTO.Quantity += item.Quantity
+		}
+		orderDTO.Items = helper.CreateOrderItemDTOs(order.Items)
+	}
+	if userDetail := rewardUseCase.proxies.UserProxy.GetUserDetails(event.RefereeID); userDetail != nil {
+		orderDTO.Location = &userDetail.Location
+	}
+
+	candidate := *campaign
+	if rewards := campaign.EligibilityCriteria.Referral; rewards.RefereeRewardGroupID > 0 {
+		candidate.RewardGroupID = rewards.RefereeRewardGroupID
+	} else {
+		candidate.RewardGroupID = rewards.ReferrerRewardGroupID
+	}
+	return rewardUseCase.evaluateCampaign(&candidate, orderDTO)
+}
+
+// reverseReferrals takes back the rewards of both sides of the referrals the order was attributed to,
+// as long as the order is cancelled or returned within its return window.
+func (rewardUseCase *RewardUseCaseImpl) reverseReferrals(orderID int64, orderLock lock.Lock) error {
+	referrals, err := rewardUseCase.referralRepo.ListReferralsByOrderID(orderID)
+	if err != nil {
+		rewardUseCase.log.Error("failed to fetch referrals of order", "orderID", orderID, "error", err)
+		return err
+	}
+	if len(referrals) == 0 {
+		return nil
+	}
+
+	// the return window only opens once the order is delivered
+	var returnWindowEnd time.Time
+	if order, found := rewardUseCase.cache.Get(helper.GetOrderKey(orderID)); found && order.Status == entities.OrderStatusDelivered {
+		returnWindowEnd = order.ReturnWindowTime
+	}
+
+	now := time.Now()
+	for _, referral := range referrals {
+		if !referral.Reversible(returnWindowEnd, now) {
+			rewardUseCase.log.Info("referral is not reversed", "referralID", referral.ID, "status", referral.Status, "returnWindowEnd", returnWindowEnd)
+			continue
+		}
+		if err := rewardUseCase.ensureLockHeld(orderLock); err != nil {
+			return err
+		}
+
+		rewardUseCase.reverseAllocation(referral.RefereeID, referral, referral.OrderID, orderLock)
+		rewardUseCase.reverseAllocation(referral.ReferrerID, referral, referral.ReferrerOrderID(), orderLock)
+
+		if err := rewardUseCase.referralRepo.UpdateReferralStatus(referral.ID, referral.Status, entities.ReferralReversed, now); err != nil {
+			rewardUseCase.log.Error("failed to mark referral reversed", "referralID", referral.ID, "error", err)
+			return err
+		}
+		rewardUseCase.log.Info("reversed referral", "referralID", referral.ID, "orderID", orderID)
+	}
+
+	return nil
+}
+
+// reverseAllocation takes back what one side of the referral was allocated against the order id.
+// Failures are only logged, as for the compensation of a failed allocation.
+func (rewardUseCase *RewardUseCaseImpl) reverseAllocation(userID string, referral *entities.Referral, orderID int64, orderLock lock.Lock) {
+	rewardUseCase.reverseUserReward(userID, referral.CampaignID, orderID)
+	rewardUseCase.creditBudget(referral.CampaignID, orderID)
+	if err := rewardUseCase.voucherRepo.ReclaimCodes(orderID, time.Now()); err != nil {
+		rewardUseCase.log.Error("failed to reclaim voucher codes", "orderID", orderID, "error", err)
+	}
+	orderRewardItems, err := rewardUseCase.rewardRepo.GetOrderRewardItems(orderID)
+	if err != nil {
+		rewardUseCase.log.Error("failed to fetch reward items", "orderID", orderID, "error", err)
+	}
+	rewardUseCase.revokeDiscounts(orderID, orderRewardItems)
+	rewardUseCase.reversePoints(orderID)
+	rewardUseCase.withdrawGiftChoices(orderID, orderLock)
+}
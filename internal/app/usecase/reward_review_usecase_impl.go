@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/craftizmv/rewards/internal/app/repository"
+	"github.com/craftizmv/rewards/internal/data/infrastructure/lock"
+	"github.com/craftizmv/rewards/internal/data/infrastructure/queue/events"
+	"github.com/craftizmv/rewards/internal/domain/entities"
+)
+
+// ListReviews returns the held allocations with the given status
+func (rewardUseCase *RewardUseCaseImpl) ListReviews(status entities.ReviewStatus) ([]*entities.RewardReview, error) {
+	return rewardUseCase.reviewRepo.ListReviews(status)
+}
+
+// ApproveReview approves a held allocation and resumes the allocation flow where it was held back
+func (rewardUseCase *RewardUseCaseImpl) ApproveReview(id int64, reviewer string, note string) (*entities.RewardReview, error) {
+	review, err := rewardUseCase.reviewRepo.GetReview(id)
+	if err != nil {
+		return nil, err
+	}
+	if review.Status != entities.ReviewPending {
+		return nil, fmt.Errorf("%w: review %d is %s", repository.ErrReviewNotPending, id, review.Status)
+	}
+
+	var event events.AllocateReward
+	if err := json.Unmarshal(review.Event, &event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal allocation of review %d: %v", id, err)
+	}
+
+	// claiming the review first makes sure a concurrent approval does not allocate twice
+	if err := rewardUseCase.reviewRepo.DecideReview(id, entities.ReviewApproved, reviewer, note); err != nil {
+		return nil, err
+	}
+
+	err = rewardUseCase.withOrderLock(event.OrderID, func(orderLock lock.Lock) error {
+		return rewardUseCase.allocateReward(event, orderLock, true)
+	})
+	if err != nil {
+		rewardUseCase.log.Error("approved allocation failed", "reviewID", id, "orderID", event.OrderID, "error", err)
+		if reopenErr := rewardUseCase.reviewRepo.ReopenReview(id, fmt.Sprintf("approval by %s failed: %v", reviewer, err)); reopenErr != nil {
+			rewardUseCase.log.Error("failed to reopen review", "reviewID", id, "error", reopenErr)
+		}
+		return nil, fmt.Errorf("approved allocation of order %d failed: %w", event.OrderID, err)
+	}
+
+	// a referral is only rewarded once both of its sides got their reward
+	if event.ReferredOrderID != 0 {
+		rewardUseCase.completeReferral(event)
+	}
+
+	return rewardUseCase.reviewRepo.GetReview(id)
+}
+
+// RejectReview rejects a held allocation, nothing was reserved for it so there is nothing to give back
+func (rewardUseCase *RewardUseCaseImpl) RejectReview(id int64, reviewer string, note string) (*entities.RewardReview, error) {
+	if err := rewardUseCase.reviewRepo.DecideReview(id, entities.ReviewRejected, reviewer, note); err != nil {
+		return nil, err
+	}
+
+	rewardUseCase.log.Info("held allocation rejected", "reviewID", id, "reviewer", reviewer)
+	return rewardUseCase.reviewRepo.GetReview(id)
+}
@@ -3,11 +3,33 @@ package usecase
 import (
 	"github.com/craftizmv/rewards/internal/data/dtos"
 	"github.com/craftizmv/rewards/internal/data/infrastructure/queue/events"
+	"github.com/craftizmv/rewards/internal/domain/entities"
+	"time"
 )
 
 type RewardUseCase interface {
 	AllocateReward(allocateReward events.AllocateReward) error
 	CancelReward(orderCancelledEvent events.RevokeReward) error
 	ReAllocateReward(orderEvent events.ReAllocateReward) error
-	CheckRewardEligibility(dto *dtos.OrderDTO) (bool, error)
+	CheckRewardEligibility(dto *dtos.OrderDTO) (*dtos.RewardEligibilityResponse, error)
+	// ConfirmReferral rewards the referrer and the referee when the first order of the referee qualifies
+	ConfirmReferral(referralConfirmed events.ReferralConfirmed) error
+
+	ListReviews(status entities.ReviewStatus) ([]*entities.RewardReview, error)
+	// ApproveReview approves a held allocation and resumes it, the review is pending again if the allocation fails
+	ApproveReview(id int64, reviewer string, note string) (*entities.RewardReview, error)
+	RejectReview(id int64, reviewer string, note string) (*entities.RewardReview, error)
+
+	// RedeemReward redeems an allocated reward, or a part of its value, for the user it was allocated to
+	RedeemReward(id int64, request *dtos.RedeemRewardRequest) (*entities.RewardBalance, error)
+	RedeemRewardFromCheckout(redeemReward events.RedeemReward) error
+	GetRewardBalance(id int64) (*entities.RewardBalance, error)
+
+	GetGiftChoice(id int64) (*entities.GiftChoice, error)
+	// ChooseGift ships the product the user picked out of a choose-one reward
+	ChooseGift(id int64, request *dtos.ChooseGiftRequest) (*entities.GiftChoice, error)
+	// DefaultDueGiftChoices ships the default product of up to limit choices past their deadline and returns how many shipped
+	DefaultDueGiftChoices(at time.Time, limit int) (int, error)
+	// BookQueuedDebits books up to limit costs whose debit failed when they were incurred and returns how many were booked
+	BookQueuedDebits(limit int) (int, error)
 }
@@ -1,24 +1,65 @@
 package usecase
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/craftizmv/rewards/internal/app/fraud"
 	. "github.com/craftizmv/rewards/internal/app/repository"
 	"github.com/craftizmv/rewards/internal/app/usecase/helper"
 	"github.com/craftizmv/rewards/internal/data/dtos"
 	. "github.com/craftizmv/rewards/internal/data/infrastructure/cache"
 	"github.com/craftizmv/rewards/internal/data/infrastructure/external/proxies"
+	"github.com/craftizmv/rewards/internal/data/infrastructure/lock"
 	"github.com/craftizmv/rewards/internal/data/infrastructure/queue/events"
 	"github.com/craftizmv/rewards/internal/domain/entities"
+	"github.com/craftizmv/rewards/internal/domain/services"
 	"github.com/craftizmv/rewards/pkg/logger"
+	uuid "github.com/satori/go.uuid"
+	"strconv"
+	"time"
 )
 
 // RewardUseCaseImpl implements the gift-related use cases
 type RewardUseCaseImpl struct {
-	cache      ICache[entities.Order]
-	rewardRepo RewardRepository
-	log        logger.ILogger
-	proxies    *RewardProxies
+	cache          ICache[entities.Order]
+	rewardRepo     RewardRepository
+	budgetRepo     CampaignBudgetRepository
+	userRewardRepo UserRewardRepository
+	reviewRepo     RewardReviewRepository
+	voucherRepo    VoucherRepository
+	redemptionRepo RewardRedemptionRepository
+	pointsRepo     PointsLedgerRepository
+	referralRepo   ReferralRepository
+	giftChoiceRepo GiftChoiceRepository
+	locker         lock.ILocker
+	selector       *services.CampaignSelector
+	fraudScreener  *fraud.Screener
+	reviewCfg      *ReviewConfig
+	log            logger.ILogger
+	proxies        *RewardProxies
+}
+
+// defaultShippingCostThreshold is the shipping cost above which allocations are reviewed when nothing is configured
+const defaultShippingCostThreshold = 100000
+
+// ReviewConfig holds the thresholds above which allocations wait for a manual review, zero disables a threshold
+type ReviewConfig struct {
+	HighValueThreshold    float64 `mapstructure:"highValueThreshold"`    // cost of the reward items
+	ShippingCostThreshold float64 `mapstructure:"shippingCostThreshold"` // quoted shipping cost
+}
+
+type RewardRepositories struct {
+	RewardRepo     RewardRepository
+	BudgetRepo     CampaignBudgetRepository
+	UserRewardRepo UserRewardRepository
+	ReviewRepo     RewardReviewRepository
+	VoucherRepo    VoucherRepository
+	RedemptionRepo RewardRedemptionRepository
+	PointsRepo     PointsLedgerRepository
+	ReferralRepo   ReferralRepository
+	GiftChoiceRepo GiftChoiceRepository
 }
 
 type RewardProxies struct {
@@ -26,32 +67,466 @@ type RewardProxies struct {
 	InventoryProxy *proxies.InventoryProxy
 	EmailProxy     *proxies.EmailProxy
 	ShippingProxy  *proxies.ShippingProxy
+	DiscountProxy  *proxies.DiscountProxy
 	UserProxy      *proxies.UserProxy
+	SegmentProxy   *proxies.SegmentProxy
 	OrderProxy     *proxies.OrderProxy
 }
 
 // NewRewardUseCaseImpl injects dependencies into the RewardUseCaseImpl
-func NewRewardUseCaseImpl(cache ICache[entities.Order], rewardRepo RewardRepository, log logger.ILogger, proxies *RewardProxies) *RewardUseCaseImpl {
+func NewRewardUseCaseImpl(cache ICache[entities.Order], repos *RewardRepositories, locker lock.ILocker, selector *services.CampaignSelector, fraudScreener *fraud.Screener, reviewCfg *ReviewConfig, log logger.ILogger, proxies *RewardProxies) *RewardUseCaseImpl {
+	if reviewCfg == nil {
+		reviewCfg = &ReviewConfig{ShippingCostThreshold: defaultShippingCostThreshold}
+	}
 	return &RewardUseCaseImpl{
-		cache:      cache,
-		rewardRepo: rewardRepo,
-		log:        log,
-		proxies:    proxies,
+		cache:          cache,
+		rewardRepo:     repos.RewardRepo,
+		budgetRepo:     repos.BudgetRepo,
+		userRewardRepo: repos.UserRewardRepo,
+		reviewRepo:     repos.ReviewRepo,
+		voucherRepo:    repos.VoucherRepo,
+		redemptionRepo: repos.RedemptionRepo,
+		pointsRepo:     repos.PointsRepo,
+		referralRepo:   repos.ReferralRepo,
+		giftChoiceRepo: repos.GiftChoiceRepo,
+		locker:         locker,
+		selector:       selector,
+		fraudScreener:  fraudScreener,
+		reviewCfg:      reviewCfg,
+		log:            log,
+		proxies:        proxies,
+	}
+}
+
+// withOrderLock runs fn while holding the distributed lock of the order, so that the order level
+// flows of different replicas never interleave their writes.
+func (rewardUseCase *RewardUseCaseImpl) withOrderLock(orderID int64, fn func(orderLock lock.Lock) error) error {
+	ctx := context.Background()
+	orderLock, err := rewardUseCase.locker.Acquire(ctx, helper.GetOrderLockKey(orderID))
+	if err != nil {
+		rewardUseCase.log.Error("failed to acquire order lock", "orderID", orderID, "error", err)
+		return err
+	}
+
+	defer func() {
+		if err := orderLock.Release(ctx); err != nil {
+			rewardUseCase.log.Error("failed to release order lock", "orderID", orderID, "token", orderLock.Token(), "error", err)
+		}
+	}()
+
+	return fn(orderLock)
+}
+
+// ensureLockHeld must be called before every write, a lock whose lease expired may already be owned by another replica.
+// It only spares the work of a lost lock, the writes which must never be done by a stale owner carry the orderFence.
+func (rewardUseCase *RewardUseCaseImpl) ensureLockHeld(orderLock lock.Lock) error {
+	if !orderLock.Held() {
+		rewardUseCase.log.Error("order lock lost, aborting", "key", orderLock.Key(), "token", orderLock.Token())
+		return lock.ErrLockLost
+	}
+	return nil
+}
+
+// orderFence carries the fencing token of the order lock into a write, the repository rejects it once a newer owner wrote
+func orderFence(orderLock lock.Lock) *Fence {
+	return &Fence{Key: orderLock.Key(), Token: orderLock.Token()}
+}
+
+// creditBudget gives everything booked for the order back to the campaign budget.
+// Failures are only logged, a missing credit must not block the order level flows.
+func (rewardUseCase *RewardUseCaseImpl) creditBudget(campaignID uuid.UUID, orderID int64) {
+	credited, err := rewardUseCase.budgetRepo.CreditOrder(campaignID, orderID)
+	if err != nil {
+		rewardUseCase.log.Error("failed to credit campaign budget", "campaignID", campaignID, "orderID", orderID, "error", err)
+		return
+	}
+	rewardUseCase.log.Info("credited campaign budget", "campaignID", campaignID, "orderID", orderID, "amount", credited)
+}
+
+// assignVoucherCodes hands out a code of each voucher pool of the reward group to the order
+func (rewardUseCase *RewardUseCaseImpl) assignVoucherCodes(event events.AllocateReward, orderLock lock.Lock) ([]*entities.VoucherCode, error) {
+	pools, err := rewardUseCase.voucherRepo.ListPoolsByRewardGroup(event.RewardTypeID)
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make([]*entities.VoucherCode, 0, len(pools))
+	for _, pool := range pools {
+		code, err := rewardUseCase.voucherRepo.AssignCode(pool.ID, event.OrderID, event.UserID, orderFence(orderLock))
+		if err != nil {
+			rewardUseCase.log.Error("failed to assign voucher code", "poolID", pool.ID, "orderID", event.OrderID, "error", err)
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// releaseVoucherCodes puts the codes of an allocation that did not go through back in their pools.
+// Failures are only logged, as for the budget credit.
+func (rewardUseCase *RewardUseCaseImpl) releaseVoucherCodes(orderID int64) {
+	if err := rewardUseCase.voucherRepo.ReleaseCodes(orderID); err != nil {
+		rewardUseCase.log.Error("failed to release voucher codes", "orderID", orderID, "error", err)
+	}
+}
+
+// issueDiscounts creates a coupon or a wallet credit for every discount item of the reward group.
+// The discounts issued before a failure are returned too, so that the caller can revoke them.
+func (rewardUseCase *RewardUseCaseImpl) issueDiscounts(event events.AllocateReward, rewardItems []*entities.RewardItem) ([]*entities.OrderRewardItem, error) {
+	var issued []*entities.OrderRewardItem
+	for _, item := range rewardItems {
+		if item.Type != entities.RewardTypeDiscount {
+			continue
+		}
+		rewardItemID, err := strconv.ParseInt(item.RewardItemID, 10, 64)
+		if err != nil {
+			return issued, fmt.Errorf("invalid reward item id %q: %v", item.RewardItemID, err)
+		}
+
+		discount, err := rewardUseCase.proxies.DiscountProxy.IssueDiscount(&dtos.DiscountIssueRequest{
+			IdempotencyKey: helper.GetDiscountIssueKey(event.OrderID, rewardItemID),
+			UserID:         event.UserID,
+			OrderID:        event.OrderID,
+			RewardItemID:   item.RewardItemID,
+			Kind:           item.IssuedDiscountKind(),
+			Amount:         *item.DiscountAmount,
+			ExpiresAt:      item.ExpirationDate,
+		})
+		if err != nil {
+			rewardUseCase.log.Error("failed to issue discount", "orderID", event.OrderID, "rewardItemID", item.RewardItemID, "error", err)
+			return issued, err
+		}
+
+		orderRewardItem := helper.CreateOrderRewardItems(event.OrderID, []int64{rewardItemID})[0]
+		orderRewardItem.IssuedRef = &discount.Reference
+		orderRewardItem.UserID = event.UserID
+		orderRewardItem.Value = *item.DiscountAmount
+		orderRewardItem.ExpiresAt = item.ExpirationDate
+		issued = append(issued, orderRewardItem)
+		rewardUseCase.log.Info("issued discount", "orderID", event.OrderID, "rewardItemID", item.RewardItemID, "kind", discount.Kind, "reference", discount.Reference)
+	}
+	return issued, nil
+}
+
+// ownedRewardItems records the voucher and product items of the reward group as owned by the user, so that they can be
+// redeemed like the issued discounts. They carry no value and are redeemed at once. The products are only recorded
+// with the shipment they went out with, those of a choose-one group once the user picked one of them.
+func ownedRewardItems(event events.AllocateReward, rewardItems []*entities.RewardItem, shipment *rewardShipment) ([]*entities.OrderRewardItem, error) {
+	var owned []*entities.OrderRewardItem
+	for _, item := range rewardItems {
+		if item.Type != entities.RewardTypeVoucher && (item.Type != entities.RewardTypeProduct || shipment == nil) {
+			continue
+		}
+		orderRewardItem, err := ownedRewardItem(event, item)
+		if err != nil {
+			return nil, err
+		}
+		if item.Type == entities.RewardTypeProduct {
+			shipment.record(orderRewardItem, item)
+		}
+		owned = append(owned, orderRewardItem)
+	}
+	return owned, nil
+}
+
+// ownedRewardItem is the allocated reward item of the order, owned by the user it was allocated to
+func ownedRewardItem(event events.AllocateReward, item *entities.RewardItem) (*entities.OrderRewardItem, error) {
+	rewardItemID, err := strconv.ParseInt(item.RewardItemID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reward item id %q: %v", item.RewardItemID, err)
+	}
+	orderRewardItem := helper.CreateOrderRewardItems(event.OrderID, []int64{rewardItemID})[0]
+	orderRewardItem.UserID = event.UserID
+	orderRewardItem.ExpiresAt = item.ExpirationDate
+	return orderRewardItem, nil
+}
+
+// revokeDiscounts withdraws the coupons and wallet credits issued for the order.
+// Failures are only logged, as for the budget credit.
+func (rewardUseCase *RewardUseCaseImpl) revokeDiscounts(orderID int64, orderRewardItems []*entities.OrderRewardItem) {
+	for _, item := range orderRewardItems {
+		if item.IssuedRef == nil {
+			continue
+		}
+		if err := rewardUseCase.proxies.DiscountProxy.RevokeDiscount(*item.IssuedRef); err != nil {
+			rewardUseCase.log.Error("failed to revoke discount", "orderID", orderID, "reference", *item.IssuedRef, "error", err)
+		}
+	}
+}
+
+// rewardShipment is what shipRewardProducts blocked in the inventory and shipped, it is recorded on the owned rows
+// of the products so that the expiry knows which inventory they held
+type rewardShipment struct {
+	itemIDs   map[int64][]int64 // inventory items blocked for each product
+	reference *string
+	shippedAt time.Time
+}
+
+// newRewardShipment maps the blocked inventory items to the products. The inventory blocks one item per product in
+// the order of the products, items which can not be told apart are not recorded on any product.
+func newRewardShipment(productIDList []int64, itemIDList []int64) *rewardShipment {
+	shipment := &rewardShipment{itemIDs: make(map[int64][]int64)}
+	if len(itemIDList) == len(productIDList) {
+		for i, productID := range productIDList {
+			shipment.itemIDs[productID] = append(shipment.itemIDs[productID], itemIDList[i])
+		}
+	}
+	return shipment
+}
+
+// record puts the inventory items and the shipment of the product item on the row owned by the user
+func (s *rewardShipment) record(orderRewardItem *entities.OrderRewardItem, item *entities.RewardItem) {
+	if item.ProductID != nil {
+		if productID, err := strconv.ParseInt(*item.ProductID, 10, 64); err == nil {
+			orderRewardItem.InventoryItemIDs = s.itemIDs[productID]
+		}
+	}
+	orderRewardItem.ShipmentRef = s.reference
+	shippedAt := s.shippedAt
+	orderRewardItem.ShippedAt = &shippedAt
+}
+
+// shipRewardProducts takes the products of the reward group from the inventory and ships them to the user.
+// The blocked inventory is released again when the items could not be shipped, once shipped they are gone.
+func (rewardUseCase *RewardUseCaseImpl) shipRewardProducts(event events.AllocateReward, orderLock lock.Lock, productIDList []int64, userDetail *dtos.UserDetail) (shipment *rewardShipment, err error) {
+	// 2. Block inventory and update the order cache.
+	if err := rewardUseCase.ensureLockHeld(orderLock); err != nil {
+		return nil, err
+	}
+	allOK, itemIDList := rewardUseCase.proxies.InventoryProxy.BlockInventoryForProducts(productIDList)
+	shipped := false
+	defer func() {
+		if err != nil && !shipped && len(itemIDList) > 0 {
+			rewardUseCase.releaseInventory(event.OrderID, itemIDList)
+		}
+	}()
+	if !allOK {
+		return nil, errors.New("could not block inventory")
+	}
+
+	// congrats : all check passed, create mappings for the rewardGroup.
+	// insert to reward group reward item mapping
+	rewardGroupID := helper.GenerateRandomInt64() // TODO : This needs to be updated in the order table.
+	if err := rewardUseCase.ensureLockHeld(orderLock); err != nil {
+		return nil, err
+	}
+	err = rewardUseCase.rewardRepo.InsertRewardGroupRewardItemsBatch(rewardGroupID, itemIDList, 5)
+	if err != nil {
+		rewardUseCase.log.Error("failed to insert in reward group reward item table", "error", err)
+		return nil, err
+	}
+
+	// NOTE : Update the shipment async when the reward is allocated - we can retry and keep retrying until it is success. (also, issue alert)
+	shipmentResponse, err := rewardUseCase.proxies.ShippingProxy.ShipItems(itemIDList, userDetail)
+	if err != nil {
+		// TODO: Handle various kinds of error
+		rewardUseCase.log.Error("failed to ship items", "error", err)
+		return nil, err
+	}
+	shipped = true
+	shipment = newRewardShipment(productIDList, itemIDList)
+	shipment.reference = shipmentResponse.ConfirmationID
+	shipment.shippedAt = time.Now()
+
+	// The items are on their way, so nothing after this point fails the allocation, a rolled back allocation would
+	// ship again when the event is redelivered. The shipping cost is booked even if it overdraws the budget.
+	shippingCost := &entities.BudgetEntry{
+		CampaignID: event.CampaignID,
+		OrderID:    event.OrderID,
+		Type:       entities.BudgetEntryShipping,
+		Amount:     shipmentResponse.Cost,
+	}
+	if err := rewardUseCase.budgetRepo.Debit(shippingCost, true, orderFence(orderLock)); err != nil {
+		rewardUseCase.log.Error("failed to debit shipping cost, queued for retry", "campaignID", event.CampaignID, "orderID", event.OrderID, "error", err)
+		if err := rewardUseCase.budgetRepo.QueueDebit(shippingCost); err != nil {
+			rewardUseCase.log.Error("failed to queue shipping cost", "campaignID", event.CampaignID, "orderID", event.OrderID, "amount", shippingCost.Amount, "error", err)
+		}
+	}
+
+	// the shipment is recorded on the rows the user owns for the products
+	return shipment, nil
+}
+
+// BookQueuedDebits books up to limit costs whose debit failed when they were incurred
+func (rewardUseCase *RewardUseCaseImpl) BookQueuedDebits(limit int) (int, error) {
+	return rewardUseCase.budgetRepo.BookQueuedDebits(limit)
+}
+
+// releaseInventory gives the inventory blocked for items which did not ship back.
+// Failures are only logged, as for the budget credit.
+func (rewardUseCase *RewardUseCaseImpl) releaseInventory(orderID int64, itemIDs []int64) {
+	if !rewardUseCase.proxies.InventoryProxy.ReleaseInventoryItems(itemIDs) {
+		rewardUseCase.log.Error("failed to release blocked inventory", "orderID", orderID, "itemIDs", itemIDs)
+	}
+}
+
+// reverseUserReward stops the reward of the order from counting towards the user limits.
+// Failures are only logged, as for the budget credit.
+func (rewardUseCase *RewardUseCaseImpl) reverseUserReward(userID string, campaignID uuid.UUID, orderID int64) {
+	if err := rewardUseCase.userRewardRepo.ReverseUserReward(userID, campaignID, orderID); err != nil {
+		rewardUseCase.log.Error("failed to reverse user reward", "userID", userID, "campaignID", campaignID, "orderID", orderID, "error", err)
+	}
+}
+
+// holdForReview screens the allocation for abuse and checks it against the review thresholds.
+// It queues the allocation for a manual review and returns true when it has to wait for an approval.
+func (rewardUseCase *RewardUseCaseImpl) holdForReview(event events.AllocateReward, userDetail *dtos.UserDetail, productIDs []int64, rewardCost float64) (bool, error) {
+	var reasons []entities.ReviewReason
+
+	screening, err := rewardUseCase.fraudScreener.Screen(&entities.FraudSubject{
+		UserID:      event.UserID,
+		Email:       userDetail.Email,
+		OrderID:     event.OrderID,
+		CampaignID:  event.CampaignID,
+		DeviceID:    event.DeviceID,
+		AddressHash: fraud.AddressFingerprint(&userDetail.Location),
+	})
+	if err != nil {
+		rewardUseCase.log.Error("failed to screen allocation", "orderID", event.OrderID, "error", err)
+		return false, err
+	}
+	switch screening.Verdict {
+	case entities.FraudDeny:
+		return false, fmt.Errorf("%w: order %d", entities.ErrAllocationDenied, event.OrderID)
+	case entities.FraudReview:
+		reasons = append(reasons, entities.ReviewReasonFraud)
 	}
+
+	if threshold := rewardUseCase.reviewCfg.HighValueThreshold; threshold > 0 && rewardCost >= threshold {
+		reasons = append(reasons, entities.ReviewReasonHighValue)
+	}
+
+	// only physical rewards are shipped
+	if threshold := rewardUseCase.reviewCfg.ShippingCostThreshold; threshold > 0 && len(productIDs) > 0 {
+		quote, err := rewardUseCase.proxies.ShippingProxy.QuoteShipment(productIDs, userDetail)
+		if err != nil {
+			rewardUseCase.log.Error("failed to get a shipping quote", "orderID", event.OrderID, "error", err)
+			return false, err
+		}
+		if quote.Cost > threshold {
+			reasons = append(reasons, entities.ReviewReasonShippingCost)
+		}
+	}
+
+	if len(reasons) == 0 {
+		return false, nil
+	}
+	return true, rewardUseCase.enqueueReview(event, reasons, screening.Signals)
+}
+
+// enqueueReview holds the allocation back until a person approves it
+func (rewardUseCase *RewardUseCaseImpl) enqueueReview(event events.AllocateReward, reasons []entities.ReviewReason, signals []entities.FraudSignal) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal allocation of order %d: %v", event.OrderID, err)
+	}
+
+	review := &entities.RewardReview{
+		OrderID:    event.OrderID,
+		UserID:     event.UserID,
+		CampaignID: event.CampaignID,
+		Event:      payload,
+		Reasons:    reasons,
+		Signals:    signals,
+		CreatedAt:  time.Now(),
+	}
+	if err := rewardUseCase.reviewRepo.EnqueueReview(review); err != nil {
+		rewardUseCase.log.Error("failed to enqueue allocation for review", "orderID", event.OrderID, "error", err)
+		return err
+	}
+
+	rewardUseCase.log.Info("allocation sent to manual review", "orderID", event.OrderID, "campaignID", event.CampaignID, "reasons", reasons)
+	return nil
+}
+
+// allocationOrderDTO rebuilds the order the allocation is for out of the event and the cached order
+func allocationOrderDTO(event events.AllocateReward, order entities.Order, userDetail *dtos.UserDetail) *dtos.OrderDTO {
+	orderDTO := &dtos.OrderDTO{
+		OrderID:           event.OrderID,
+		UserID:            event.UserID,
+		OrderValue:        float64(event.OrderValue),
+		Items:             helper.CreateOrderItemDTOs(order.Items),
+		Payment:           event.PaymentMethod(),
+		Channel:           event.Channel,
+		AppliedPromotions: event.AppliedPromotions,
+	}
+	for _, item := range order.Items {
+		orderDTO.Quantity += item.Quantity
+	}
+	if userDetail != nil {
+		orderDTO.Location = &userDetail.Location
+	}
+	return orderDTO
 }
 
 // AllocateReward allocateGift allocates a gift based on the order ID
 func (rewardUseCase *RewardUseCaseImpl) AllocateReward(event events.AllocateReward) error {
-	// retrieve order info from the shared cache.
-	order, found := rewardUseCase.cache.Get(helper.GetOrderKey(event.OrderID))
+	return rewardUseCase.withOrderLock(event.OrderID, func(orderLock lock.Lock) error {
+		return rewardUseCase.allocateReward(event, orderLock, false)
+	})
+}
+
+// allocateReward runs the allocation flow, an approved allocation was already reviewed and is not held back again.
+// A tier which gave out all of its rewards meanwhile falls back to the next lower tier the order reaches.
+func (rewardUseCase *RewardUseCaseImpl) allocateReward(event events.AllocateReward, orderLock lock.Lock, approved bool) error {
+	for {
+		err := rewardUseCase.allocateRewardGroup(event, orderLock, approved)
+		if !errors.Is(err, entities.ErrTierCapReached) {
+			return err
+		}
+
+		fallback, found, ferr := rewardUseCase.fallbackRewardGroup(event)
+		if ferr != nil {
+			return ferr
+		}
+		if !found {
+			return err
+		}
+		rewardUseCase.log.Info("reward tier exhausted, falling back", "orderID", event.OrderID, "campaignID", event.CampaignID, "rewardGroupID", event.RewardTypeID, "fallbackRewardGroupID", fallback)
+		event.RewardTypeID = fallback
+	}
+}
+
+// fallbackRewardGroup picks the next tier below the exhausted one which the order reaches and which is in stock,
+// or the base reward group of the campaign when no lower tier is left
+func (rewardUseCase *RewardUseCaseImpl) fallbackRewardGroup(event events.AllocateReward) (int64, bool, error) {
+	campaign, err := rewardUseCase.proxies.CampaignProxy.FetchCampaign(event.CampaignID)
+	if err != nil {
+		return 0, false, err
+	}
+	exhausted := entities.FindTier(campaign.EligibilityCriteria, event.RewardTypeID)
+	if exhausted == nil {
+		return 0, false, nil
+	}
+
+	for _, tier := range entities.QualifyingTiers(campaign.EligibilityCriteria, float64(event.OrderValue)) {
+		if tier.MinimumPurchaseAmount >= exhausted.MinimumPurchaseAmount {
+			continue
+		}
+		inStock, err := rewardUseCase.tierInStock(campaign, tier)
+		if err != nil {
+			return 0, false, err
+		}
+		if inStock {
+			return tier.RewardGroupID, true, nil
+		}
+	}
+	return campaign.RewardGroupID, campaign.RewardGroupID > 0, nil
+}
+
+// allocateRewardGroup allocates the reward group of the event
+func (rewardUseCase *RewardUseCaseImpl) allocateRewardGroup(event events.AllocateReward, orderLock lock.Lock, approved bool) error {
+	// retrieve order info from the shared cache, a referral reward follows the order of the referee.
+	order, found := rewardUseCase.cache.Get(helper.GetOrderKey(event.StatusOrderID()))
 	if !found {
 		// TODO : If not found in cache .. may be we can check in DB or simply reject allocating, trigger a background update if cache.
-		rewardUseCase.log.Error("order not found", "orderID", event.OrderID)
+		rewardUseCase.log.Error("order not found", "orderID", event.StatusOrderID())
 		return errors.New("order not found")
 	}
 
-	// checking from the order object if the reward is already issued
-	if order.RewardStatus != entities.RewardStatusNone {
+	// checking from the order object if the reward is already issued. The order of a referee can carry an order
+	// reward of its own, the ledger keeps each side of a referral to a single reward instead.
+	if event.ReferredOrderID == 0 && order.RewardStatus != entities.RewardStatusNone {
 		return errors.New("reward is already processed")
 	}
 
@@ -65,59 +540,250 @@ func (rewardUseCase *RewardUseCaseImpl) AllocateReward(event events.AllocateRewa
 		return err
 	}
 
+	// discounts are issued rather than shipped, a reward group without products never touches inventory or shipping
+	rewardItems, err := rewardUseCase.rewardRepo.GetRewardItemsFromRewardGroup(event.RewardTypeID)
+	if err != nil {
+		return err
+	}
+	physical := len(productIDList) > 0
+
+	// a choose-one group lets the user pick the product which ships, it only needs one of them in stock
+	var rewardGroup *entities.RewardGroup
+	if physical {
+		rewardGroup, err = rewardUseCase.rewardRepo.GetRewardGroupByID(event.RewardTypeID)
+		if err != nil {
+			return err
+		}
+	}
+	offersChoice := physical && rewardGroup.OffersChoice(productIDList)
+
 	// Assumption : Inventory Proxy provides an API to check the inventory
 	// availability of items needed to be allocated as part of the reward.
-	ok, _ := rewardUseCase.proxies.InventoryProxy.BulkVerifyInventoryAvailability(productIDList)
-	if !ok {
-		return errors.New("can not allocate reward, inventory unavailable")
+	if offersChoice {
+		productIDList, err = rewardUseCase.availableGiftOptions(productIDList)
+		if err != nil {
+			return err
+		}
+		if len(productIDList) == 0 {
+			return errors.New("can not allocate reward, inventory unavailable")
+		}
+	} else if physical {
+		ok, _, err := rewardUseCase.proxies.InventoryProxy.BulkVerifyInventoryAvailability(productIDList)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("can not allocate reward, inventory unavailable")
+		}
 	}
 
-	// 2. Block inventory and update the order cache.
-	allOK, itemIDList := rewardUseCase.proxies.InventoryProxy.BlockInventoryForProducts(productIDList)
-	if !allOK {
-		return errors.New("could not block inventory")
+	campaign, err := rewardUseCase.proxies.CampaignProxy.FetchCampaign(event.CampaignID)
+	if err != nil {
+		return err
 	}
 
-	// congrats : all check passed, create mappings for the rewardGroup.
-	// insert to reward group reward item mapping
-	rewardGroupID := helper.GenerateRandomInt64() // TODO : This needs to be updated in the order table.
-	err = rewardUseCase.rewardRepo.InsertRewardGroupRewardItemsBatch(rewardGroupID, itemIDList, 5)
-	if err != nil {
-		rewardUseCase.log.Error("failed to insert in reward group reward item table", "error", err)
+	// referral campaigns only pay out their referral reward groups, and only for referrals
+	if err := entities.CheckReferralAllocation(campaign, event.RewardTypeID, event.ReferredOrderID != 0); err != nil {
+		rewardUseCase.log.Error("reward group is not offered for the allocation", "orderID", event.OrderID, "campaignID", event.CampaignID, "rewardGroupID", event.RewardTypeID, "error", err)
 		return err
 	}
 
-	//insert to order_reward_item mapping.
-	// NOTE : Update the shipment async when the reward is allocated - we can retry and keep retrying until it is success. (also, issue alert)
+	// allocation can lag behind the order, a flash reward is due when the order was placed within the window
+	placedAt := event.PlacedAt
+	if placedAt.IsZero() {
+		placedAt = time.Now()
+	}
+	// a paused or ended campaign stops giving out rewards, even for orders placed while it was running
+	if err := entities.CheckCampaignRunning(campaign, placedAt); err != nil {
+		rewardUseCase.log.Error("campaign is not running", "orderID", event.OrderID, "campaignID", event.CampaignID, "error", err)
+		return err
+	}
+	if err := entities.CheckTimeWindows(campaign, placedAt); err != nil {
+		rewardUseCase.log.Error("order placed outside of the campaign time windows", "orderID", event.OrderID, "campaignID", event.CampaignID, "error", err)
+		return err
+	}
+	if err := entities.CheckPaymentRules(campaign.EligibilityCriteria.PaymentRules, event.PaymentMethod()); err != nil {
+		rewardUseCase.log.Error("payment method not accepted", "orderID", event.OrderID, "campaignID", event.CampaignID, "error", err)
+		return err
+	}
+	if err := entities.CheckChannelRules(campaign.EligibilityCriteria.ChannelRules, event.Channel); err != nil {
+		rewardUseCase.log.Error("sales channel not accepted", "orderID", event.OrderID, "campaignID", event.CampaignID, "error", err)
+		return err
+	}
+	// a conflicting promotion either blocks the reward or only lets the downgrade reward group through
+	combinability := campaign.EligibilityCriteria.Combinability
+	if err := entities.CheckCombinability(combinability, event.AppliedPromotions); err != nil {
+		if !entities.DowngradesOnConflict(combinability) || event.RewardTypeID != combinability.DowngradeRewardGroupID {
+			rewardUseCase.log.Error("reward conflicts with applied promotions", "orderID", event.OrderID, "campaignID", event.CampaignID, "rewardGroupID", event.RewardTypeID, "error", err)
+			return err
+		}
+	}
+	// a tier reward group needs an order value within the tier
+	tier := entities.FindTier(campaign.EligibilityCriteria, event.RewardTypeID)
+	if tier != nil {
+		if err := entities.CheckTier(*tier, float64(event.OrderValue)); err != nil {
+			rewardUseCase.log.Error("order does not reach the reward tier", "orderID", event.OrderID, "campaignID", event.CampaignID, "rewardGroupID", event.RewardTypeID, "error", err)
+			return err
+		}
+	} else if len(campaign.EligibilityCriteria.Tiers) > 0 && event.RewardTypeID != campaign.RewardGroupID && event.RewardTypeID != combinability.DowngradeRewardGroupID {
+		rewardUseCase.log.Error("reward group is not offered by the campaign", "orderID", event.OrderID, "campaignID", event.CampaignID, "rewardGroupID", event.RewardTypeID)
+		return fmt.Errorf("%w: reward group %d, campaign %s", entities.ErrUnknownTier, event.RewardTypeID, event.CampaignID)
+	}
+
 	userDetail := rewardUseCase.proxies.UserProxy.GetUserDetails(event.UserID)
-	shipmentResponse, err := rewardUseCase.proxies.ShippingProxy.ShipItems(itemIDList, userDetail)
+
+	// the eligibility check may be stale or was never made, so the rules on the order are evaluated again.
+	// The referrer has no order of its own, the order of the referee was checked when the referral was confirmed.
+	if event.ReferredOrderID == 0 || event.OrderID == event.ReferredOrderID {
+		candidate := *campaign
+		candidate.RewardGroupID = event.RewardTypeID
+		if err := rewardUseCase.checkOrderRules(&candidate, allocationOrderDTO(event, order, userDetail), placedAt); err != nil {
+			rewardUseCase.log.Error("order does not meet the campaign rules", "orderID", event.OrderID, "campaignID", event.CampaignID, "error", err)
+			return err
+		}
+	}
+
+	rewardCost, err := rewardUseCase.rewardRepo.GetRewardGroupCost(event.RewardTypeID)
 	if err != nil {
-		// TODO: Handle various kinds of error
-		rewardUseCase.log.Error("failed to ship items", "error", err)
 		return err
 	}
 
-	// using some random num.
-	if shipmentResponse.Cost > 100000 {
-		rewardUseCase.log.Error("failed to ship items, cost too high", "cost", shipmentResponse.Cost)
-		return errors.New("failed to ship items, cost too high")
+	// Hold the allocation back before anything is reserved if it needs a person to approve it.
+	if !approved {
+		if err := rewardUseCase.ensureLockHeld(orderLock); err != nil {
+			return err
+		}
+		held, err := rewardUseCase.holdForReview(event, userDetail, productIDList, rewardCost)
+		if err != nil || held {
+			return err
+		}
+	}
+
+	// Count the reward towards the per-user limits and the tier cap first, a user who reached a limit gets nothing.
+	// The cap is checked in the same step, so concurrent allocations can never overshoot it.
+	tierCap := 0
+	if tier != nil {
+		tierCap = tier.MaxRewards
+	}
+	if err := rewardUseCase.ensureLockHeld(orderLock); err != nil {
+		return err
+	}
+	err = rewardUseCase.userRewardRepo.RecordUserReward(&entities.UserReward{
+		UserID:        event.UserID,
+		CampaignID:    event.CampaignID,
+		OrderID:       event.OrderID,
+		RewardGroupID: event.RewardTypeID,
+		AllocatedAt:   time.Now(),
+	}, campaign.EligibilityCriteria.UserLimits, tierCap, orderFence(orderLock))
+	if err != nil {
+		rewardUseCase.log.Error("failed to record user reward", "userID", event.UserID, "campaignID", event.CampaignID, "error", err)
+		return err
+	}
+
+	// give the reward, the budget, the voucher codes and the discounts back if the allocation does not go through
+	allocated := false
+	var issuedDiscounts []*entities.OrderRewardItem
+	defer func() {
+		if !allocated {
+			rewardUseCase.reverseUserReward(event.UserID, event.CampaignID, event.OrderID)
+			rewardUseCase.creditBudget(event.CampaignID, event.OrderID)
+			rewardUseCase.releaseVoucherCodes(event.OrderID)
+			rewardUseCase.revokeDiscounts(event.OrderID, issuedDiscounts)
+			rewardUseCase.reversePoints(event.OrderID)
+			rewardUseCase.withdrawGiftChoices(event.OrderID, orderLock)
+		}
+	}()
+
+	// Reserve the cost of the reward items on the campaign budget, an exhausted budget blocks the allocation.
+	err = rewardUseCase.budgetRepo.Debit(&entities.BudgetEntry{
+		CampaignID: event.CampaignID,
+		OrderID:    event.OrderID,
+		Type:       entities.BudgetEntryReward,
+		Amount:     rewardCost,
+	}, false, orderFence(orderLock))
+	if err != nil {
+		rewardUseCase.log.Error("failed to debit campaign budget", "campaignID", event.CampaignID, "orderID", event.OrderID, "error", err)
+		return err
 	}
 
-	err = rewardUseCase.rewardRepo.UpdateOrderRewardItemsBatch(helper.CreateOrderRewardItems(event.OrderID, itemIDList), 5)
+	// Hand out one code of every voucher pool of the reward group.
+	if err := rewardUseCase.ensureLockHeld(orderLock); err != nil {
+		return err
+	}
+	voucherCodes, err := rewardUseCase.assignVoucherCodes(event, orderLock)
 	if err != nil {
-		rewardUseCase.log.Error("failed to update order reward item table", "error", err)
+		return err
+	}
+
+	// the products of a choose-one group ship once the user picked one, or the default after the deadline
+	var giftChoice *entities.GiftChoice
+	var shipment *rewardShipment
+	if offersChoice {
+		giftChoice, err = rewardUseCase.offerGiftChoice(event, orderLock, rewardGroup, productIDList)
+		if err != nil {
+			return err
+		}
+	} else if physical {
+		shipment, err = rewardUseCase.shipRewardProducts(event, orderLock, productIDList, userDetail)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Issue the coupons and wallet credits of the reward group.
+	if err := rewardUseCase.ensureLockHeld(orderLock); err != nil {
+		return err
+	}
+	issuedDiscounts, err = rewardUseCase.issueDiscounts(event, rewardItems)
+	if err != nil {
+		return err
+	}
+	// every allocated item gets a row owned by the user, the redemption endpoints look the reward up through it
+	ownedItems, err := ownedRewardItems(event, rewardItems, shipment)
+	if err != nil {
+		return err
+	}
+	if ownedItems = append(ownedItems, issuedDiscounts...); len(ownedItems) > 0 {
+		if err := rewardUseCase.ensureLockHeld(orderLock); err != nil {
+			return err
+		}
+		if err := rewardUseCase.rewardRepo.InsertOrderRewardItems(ownedItems, orderFence(orderLock)); err != nil {
+			rewardUseCase.log.Error("failed to record allocated reward items", "orderID", event.OrderID, "error", err)
+			return err
+		}
+	}
+
+	// points are credited to the ledger of the user rather than issued
+	if err := rewardUseCase.ensureLockHeld(orderLock); err != nil {
+		return err
+	}
+	if err := rewardUseCase.accruePoints(event, orderLock, rewardItems); err != nil {
+		return err
+	}
+
+	// the cancellation finds the reward group of the order through this mapping
+	if err := rewardUseCase.ensureLockHeld(orderLock); err != nil {
+		return err
+	}
+	if err := rewardUseCase.rewardRepo.InsertOrderRewardGroup(event.OrderID, event.RewardTypeID, orderFence(orderLock)); err != nil {
+		rewardUseCase.log.Error("failed to record reward group of order", "orderID", event.OrderID, "rewardGroupID", event.RewardTypeID, "error", err)
 		return err
 	}
 
 	// TODO : Order cache . - use order proxy to do that.
 
 	// TODO: Send email
-	err = rewardUseCase.proxies.EmailProxy.SendEmail(userDetail.UserName, userDetail.Email, "Hi, XZY")
+	emailBody := helper.RewardEmailBody(voucherCodes)
+	if giftChoice != nil {
+		emailBody += helper.GiftChoiceEmailBody(giftChoice)
+	}
+	err = rewardUseCase.proxies.EmailProxy.SendEmail(userDetail.UserName, userDetail.Email, emailBody)
 	if err != nil {
 		rewardUseCase.log.Error("failed to send email", "error", err)
 		// see if we handle retry or communicate via whatsapp etc.
 	}
 
+	allocated = true
 	rewardUseCase.log.Info("successfully allocated reward", "orderID", event.OrderID)
 
 	return nil
@@ -125,40 +791,83 @@ func (rewardUseCase *RewardUseCaseImpl) AllocateReward(event events.AllocateRewa
 
 // CancelReward cancels the reward associated with the order ID
 func (rewardUseCase *RewardUseCaseImpl) CancelReward(revokeReward events.RevokeReward) error {
+	return rewardUseCase.withOrderLock(revokeReward.OrderID, func(orderLock lock.Lock) error {
+		// a referral order cancelled or returned within its return window takes back the rewards of both sides
+		if err := rewardUseCase.reverseReferrals(revokeReward.OrderID, orderLock); err != nil {
+			return err
+		}
+		// a gift the user did not choose yet must not ship after the cancellation
+		rewardUseCase.withdrawGiftChoices(revokeReward.OrderID, orderLock)
+		return rewardUseCase.cancelReward(revokeReward, orderLock)
+	})
+}
+
+func (rewardUseCase *RewardUseCaseImpl) cancelReward(revokeReward events.RevokeReward, orderLock lock.Lock) error {
 
 	// TODO : Check the shipping status of the Reward, If valid then proceed further.
 
-	// TODO: Make all below steps transaction to avoid data inconsistency.
-	// Get RewardGroupID
-	rewardGroupIDs, err := rewardUseCase.rewardRepo.GetRewardGroupIDByOrderID(revokeReward.OrderID)
+	orderRewardItems, err := rewardUseCase.rewardRepo.GetOrderRewardItems(revokeReward.OrderID)
 	if err != nil {
-		rewardUseCase.log.Error("failed to find reward group", "error", err)
+		rewardUseCase.log.Error("failed to fetch reward items", "error", err)
 		return err
 	}
 
-	if len(rewardGroupIDs) <= 0 {
-		rewardUseCase.log.Error("failed to find reward group", "error", err)
-		return errors.New("failed to find reward group")
+	// Take back everything the allocation booked first, these are keyed on the order and
+	// must not depend on the reward group of the order being recorded.
+	if err := rewardUseCase.ensureLockHeld(orderLock); err != nil {
+		return err
 	}
+	rewardUseCase.creditBudget(revokeReward.CampaignID, revokeReward.OrderID)
+	rewardUseCase.reverseUserReward(revokeReward.UserID, revokeReward.CampaignID, revokeReward.OrderID)
 
-	// update order cache
-	_, err = rewardUseCase.proxies.OrderProxy.UpdateOrderRewardStatus(revokeReward.OrderID, rewardGroupIDs[0], string(entities.RewardStatusCancelled))
-	if err != nil {
-		rewardUseCase.log.Error("failed to update order reward status", "error", err)
-		return err
+	if err := rewardUseCase.voucherRepo.ReclaimCodes(revokeReward.OrderID, time.Now()); err != nil {
+		rewardUseCase.log.Error("failed to reclaim voucher codes", "orderID", revokeReward.OrderID, "error", err)
 	}
+	rewardUseCase.revokeDiscounts(revokeReward.OrderID, orderRewardItems)
+	rewardUseCase.reversePoints(revokeReward.OrderID)
 
-	// removing the relationship of reward with order
-	err = rewardUseCase.rewardRepo.DeleteRewardGroupByOrderID(revokeReward.OrderID, rewardGroupIDs[0])
+	// TODO: Make all below steps transaction to avoid data inconsistency.
+	// Get RewardGroupID
+	rewardGroupIDs, err := rewardUseCase.rewardRepo.GetRewardGroupIDByOrderID(revokeReward.OrderID)
 	if err != nil {
-		rewardUseCase.log.Error("failed to delete reward group", "error", err)
+		rewardUseCase.log.Error("failed to find reward group", "error", err)
 		return err
 	}
 
-	err = rewardUseCase.rewardRepo.DeleteRewardItemsByOrderID(revokeReward.OrderID)
-	if err != nil {
-		rewardUseCase.log.Error("failed to delete reward items", "error", err)
-		return err
+	for _, rewardGroupID := range rewardGroupIDs {
+		// update order cache
+		if err := rewardUseCase.ensureLockHeld(orderLock); err != nil {
+			return err
+		}
+		_, err = rewardUseCase.proxies.OrderProxy.UpdateOrderRewardStatus(revokeReward.OrderID, rewardGroupID, string(entities.RewardStatusCancelled))
+		if err != nil {
+			rewardUseCase.log.Error("failed to update order reward status", "error", err)
+			return err
+		}
+
+		// removing the relationship of reward with order
+		if err := rewardUseCase.ensureLockHeld(orderLock); err != nil {
+			return err
+		}
+		err = rewardUseCase.rewardRepo.DeleteRewardGroupByOrderID(revokeReward.OrderID, rewardGroupID, orderFence(orderLock))
+		if err != nil {
+			rewardUseCase.log.Error("failed to delete reward group", "error", err)
+			return err
+		}
+	}
+	if len(rewardGroupIDs) == 0 {
+		rewardUseCase.log.Info("no reward group recorded for order", "orderID", revokeReward.OrderID)
+	}
+
+	if len(orderRewardItems) > 0 {
+		if err := rewardUseCase.ensureLockHeld(orderLock); err != nil {
+			return err
+		}
+		err = rewardUseCase.rewardRepo.DeleteRewardItemsByOrderID(revokeReward.OrderID, orderFence(orderLock))
+		if err != nil {
+			rewardUseCase.log.Error("failed to delete reward items", "error", err)
+			return err
+		}
 	}
 
 	//NOTE : Don't delete the generated reward, as this can be used for re-allocation. Can be cleanup later by a JOB.
@@ -173,52 +882,110 @@ func (rewardUseCase *RewardUseCaseImpl) ReAllocateReward(reAllocateEvent events.
 	return nil
 }
 
-func (rewardUseCase *RewardUseCaseImpl) CheckRewardEligibility(orderDTO *dtos.OrderDTO) (bool, error) {
-	// Here we are checking below 4 conditions:
-	// 1. campaign is active
-	// 2. order val satisfies the eligibility criteria
-	// 3. RewardItem inventory availability
-	// 4. correct order status - cache.
+// CheckRewardEligibility evaluates the order against every running campaign and picks
+// the winning ones through the configured selection strategy.
+func (rewardUseCase *RewardUseCaseImpl) CheckRewardEligibility(orderDTO *dtos.OrderDTO) (*dtos.RewardEligibilityResponse, error) {
+	// Here we are checking below conditions:
+	// 1. correct order status - cache.
+	// 2. running campaigns the order satisfies the eligibility criteria of
+	// 3. campaigns the order wins according to the selection strategy and stacking rules
 
-	// 1. campaign is active
-	// TODO : check for proxies null condition if needed.
-	campaign := rewardUseCase.proxies.CampaignProxy.FetchMostEligibleCampaign()
-	if campaign == nil {
-		return false, errors.New("failed to fetch campaign")
+	// 1. correct order status
+	if orderCacheObj, ok := rewardUseCase.cache.Get(helper.GetOrderKey(orderDTO.OrderID)); !ok {
+		return nil, fmt.Errorf("failed to get order from cach")
+	} else {
+		if orderCacheObj.IsComplete() {
+			return ineligibleResponse(ineligible(dtos.ReasonOrderCompleted, "order %d is already completed", orderDTO.OrderID)), nil
+		}
+		// the product rules need the line items, fall back to the ones of the cached order
+		if len(orderDTO.Items) == 0 {
+			orderDTO.Items = helper.CreateOrderItemDTOs(orderCacheObj.Items)
+		}
 	}
 
-	if campaign.Status != dtos.Active {
-		return false, errors.New("campaign is not active")
+	// geo targeting and the shipping coverage of physical rewards need the delivery location
+	if orderDTO.Location == nil && orderDTO.UserID != "" {
+		if userDetail := rewardUseCase.proxies.UserProxy.GetUserDetails(orderDTO.UserID); userDetail != nil {
+			orderDTO.Location = &userDetail.Location
+		}
 	}
 
-	if campaign.AllocatedRewards == campaign.TotalEligibleRewards {
-		return false, errors.New("rewardGroup allocation limit exhausted")
+	// 2. campaigns the order is eligible for
+	// TODO : check for proxies null condition if needed.
+	campaigns, err := rewardUseCase.proxies.CampaignProxy.FetchActiveCampaigns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch campaigns: %w", err)
 	}
 
-	if orderDTO.OrderValue < campaign.EligibilityCriteria.MinimumPurchaseAmount {
-		return false, errors.New("order value is too small")
+	if len(campaigns) == 0 {
+		return ineligibleResponse(ineligible(dtos.ReasonNoRunningCampaign, "no campaign is running")), nil
 	}
 
-	// check inventory of the rewardGroup from the inventory table.
-	// Check the availability of productID obtained from RewardGroup data.
-	if rewardGroup, err := rewardUseCase.rewardRepo.GetRewardGroupByID(campaign.RewardGroupID); err != nil {
-		return false, fmt.Errorf("failed to get rewardGroup from repository: %w", err)
-	} else {
-		if rewardGroup == nil {
-			return false, fmt.Errorf("no rewardGroup found for rewardGroup ID %d", campaign.RewardGroupID)
-		} else {
-			// loop over rewardItems in rewardGroup and check the inventory availability
-			// TODO Get productList from RewardGroup, then using the product list check inventory (as done in AllocateReward func)
+	var eligibleCampaigns []*dtos.CampaignDTO
+	var firstRejection *IneligibleError
+	downgradedCampaigns := make(map[uuid.UUID]bool)
+	campaignTiers := make(map[uuid.UUID]string)
+	for _, campaign := range campaigns {
+		var tier string
+		candidate, downgraded, err := resolveCombinability(campaign, orderDTO)
+		// referral campaigns are only paid out through ReferralConfirmed events
+		if err == nil && entities.IsReferralCampaign(campaign) {
+			err = ineligible(dtos.ReasonReferralCampaign, "campaign %s only rewards referrals", campaign.ID)
+		}
+		// a downgraded reward replaces the whole tier ladder
+		if err == nil && !downgraded {
+			candidate, tier, err = rewardUseCase.resolveTier(candidate, orderDTO)
+		}
+		if err == nil {
+			err = rewardUseCase.evaluateCampaign(candidate, orderDTO)
+		}
+		if err == nil {
+			eligibleCampaigns = append(eligibleCampaigns, candidate)
+			downgradedCampaigns[candidate.ID] = downgraded
+			campaignTiers[candidate.ID] = tier
+			continue
+		}
+
+		var rejection *IneligibleError
+		if !errors.As(err, &rejection) {
+			return nil, err
+		}
+		rewardUseCase.log.Debug("order not eligible for campaign", "orderID", orderDTO.OrderID, "campaignID", campaign.ID, "reason", rejection)
+		if firstRejection == nil {
+			firstRejection = rejection
 		}
 	}
 
-	if orderCacheObj, ok := rewardUseCase.cache.Get(helper.GetOrderKey(orderDTO.OrderID)); !ok {
-		return false, fmt.Errorf("failed to get order from cach")
-	} else {
-		if orderCacheObj.IsComplete() {
-			return false, errors.New("order is already completed")
+	// 3. pick the winners
+	selected := rewardUseCase.selector.Select(eligibleCampaigns)
+	if len(selected) == 0 {
+		return ineligibleResponse(firstRejection), nil
+	}
+
+	campaignIDs := make([]uuid.UUID, len(selected))
+	rewards := make([]dtos.EligibleReward, len(selected))
+	for i, campaign := range selected {
+		campaignIDs[i] = campaign.ID
+		rewards[i] = dtos.EligibleReward{
+			CampaignID:    campaign.ID,
+			RewardGroupID: campaign.RewardGroupID,
+			Downgraded:    downgradedCampaigns[campaign.ID],
+			Tier:          campaignTiers[campaign.ID],
 		}
 	}
 
-	return true, nil
+	return &dtos.RewardEligibilityResponse{
+		Eligible:    true,
+		Message:     "reward is eligible",
+		CampaignIDs: campaignIDs,
+		Rewards:     rewards,
+	}, nil
+}
+
+func ineligibleResponse(rejection *IneligibleError) *dtos.RewardEligibilityResponse {
+	return &dtos.RewardEligibilityResponse{
+		Eligible: false,
+		Message:  rejection.Detail,
+		Reason:   string(rejection.Reason),
+	}
 }
@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"github.com/craftizmv/rewards/internal/domain/entities"
+	"io"
+)
+
+type VoucherUseCase interface {
+	CreatePool(pool *entities.VoucherPool) (*entities.VoucherPool, error)
+	GetPool(id int64) (*entities.VoucherPool, error)
+	ListPools() ([]*entities.VoucherPool, error)
+	// GenerateCodes adds count new codes in the format of the pool
+	GenerateCodes(poolID int64, count int) (*entities.VoucherPool, error)
+	// ImportCodes adds the codes of the first column of a CSV, codes that exist already are skipped
+	ImportCodes(poolID int64, csv io.Reader) (*entities.VoucherPool, error)
+	// ConsumeCode marks the code as used by the user it was assigned to
+	ConsumeCode(code string, userID string) (*entities.VoucherCode, error)
+}
@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	. "github.com/craftizmv/rewards/internal/app/repository"
+	"github.com/craftizmv/rewards/internal/domain/entities"
+	"github.com/craftizmv/rewards/internal/domain/services"
+	"github.com/craftizmv/rewards/pkg/logger"
+	"io"
+	"strings"
+)
+
+const (
+	// maxVoucherBatch bounds the codes generated or imported by a single request
+	maxVoucherBatch = 100000
+	// maxVoucherCodeLength bounds the length of imported codes
+	maxVoucherCodeLength = 64
+	// voucherGenerationRounds bounds the retries for the rare generated code that exists already
+	voucherGenerationRounds = 3
+)
+
+// VoucherUseCaseImpl implements the voucher pool administration use cases
+type VoucherUseCaseImpl struct {
+	voucherRepo VoucherRepository
+	log         logger.ILogger
+}
+
+// NewVoucherUseCaseImpl injects dependencies into the VoucherUseCaseImpl
+func NewVoucherUseCaseImpl(voucherRepo VoucherRepository, log logger.ILogger) *VoucherUseCaseImpl {
+	return &VoucherUseCaseImpl{
+		voucherRepo: voucherRepo,
+		log:         log,
+	}
+}
+
+// CreatePool validates the code format and stores a new, empty pool
+func (voucherUseCase *VoucherUseCaseImpl) CreatePool(pool *entities.VoucherPool) (*entities.VoucherPool, error) {
+	if pool.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", entities.ErrInvalidVoucherPool)
+	}
+	if pool.RewardGroupID <= 0 {
+		return nil, fmt.Errorf("%w: reward group is required", entities.ErrInvalidVoucherPool)
+	}
+
+	generator, err := services.NewVoucherCodeGenerator(pool.Format)
+	if err != nil {
+		return nil, err
+	}
+	pool.Format = generator.Format()
+
+	if err := voucherUseCase.voucherRepo.CreatePool(pool); err != nil {
+		return nil, err
+	}
+
+	voucherUseCase.log.Info("voucher pool created", "poolID", pool.ID, "rewardGroupID", pool.RewardGroupID)
+	return voucherUseCase.voucherRepo.GetPool(pool.ID)
+}
+
+func (voucherUseCase *VoucherUseCaseImpl) GetPool(id int64) (*entities.VoucherPool, error) {
+	return voucherUseCase.voucherRepo.GetPool(id)
+}
+
+func (voucherUseCase *VoucherUseCaseImpl) ListPools() ([]*entities.VoucherPool, error) {
+	return voucherUseCase.voucherRepo.ListPools()
+}
+
+// GenerateCodes generates count unique codes, a code that collides with an existing one is generated again
+func (voucherUseCase *VoucherUseCaseImpl) GenerateCodes(poolID int64, count int) (*entities.VoucherPool, error) {
+	if count <= 0 || count > maxVoucherBatch {
+		return nil, fmt.Errorf("%w: count must be between 1 and %d", entities.ErrInvalidVoucherBatch, maxVoucherBatch)
+	}
+
+	pool, err := voucherUseCase.voucherRepo.GetPool(poolID)
+	if err != nil {
+		return nil, err
+	}
+	generator, err := services.NewVoucherCodeGenerator(pool.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := count
+	for round := 0; round < voucherGenerationRounds && remaining > 0; round++ {
+		codes := make([]string, remaining)
+		for i := range codes {
+			if codes[i], err = generator.Generate(); err != nil {
+				return nil, err
+			}
+		}
+
+		added, err := voucherUseCase.voucherRepo.AddCodes(poolID, codes)
+		if err != nil {
+			return nil, err
+		}
+		remaining -= added
+	}
+	if remaining > 0 {
+		return nil, fmt.Errorf("generated %d of %d codes for voucher pool %d, the format has too few combinations left", count-remaining, count, poolID)
+	}
+
+	voucherUseCase.log.Info("voucher codes generated", "poolID", poolID, "count", count)
+	return voucherUseCase.voucherRepo.GetPool(poolID)
+}
+
+// ImportCodes reads the codes from the first column of the CSV, a header row named "code" is skipped
+func (voucherUseCase *VoucherUseCaseImpl) ImportCodes(poolID int64, input io.Reader) (*entities.VoucherPool, error) {
+	if _, err := voucherUseCase.voucherRepo.GetPool(poolID); err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(input)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var codes []string
+	seen := make(map[string]bool)
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", entities.ErrInvalidVoucherBatch, err)
+		}
+
+		code := strings.TrimSpace(record[0])
+		if code == "" || (line == 1 && strings.EqualFold(code, "code")) || seen[code] {
+			continue
+		}
+		if len(code) > maxVoucherCodeLength {
+			return nil, fmt.Errorf("%w: code on line %d is longer than %d characters", entities.ErrInvalidVoucherBatch, line, maxVoucherCodeLength)
+		}
+		seen[code] = true
+		codes = append(codes, code)
+	}
+	if len(codes) == 0 || len(codes) > maxVoucherBatch {
+		return nil, fmt.Errorf("%w: a file must contain between 1 and %d codes", entities.ErrInvalidVoucherBatch, maxVoucherBatch)
+	}
+
+	added, err := voucherUseCase.voucherRepo.AddCodes(poolID, codes)
+	if err != nil {
+		return nil, err
+	}
+
+	voucherUseCase.log.Info("voucher codes imported", "poolID", poolID, "added", added, "skipped", len(codes)-added)
+	return voucherUseCase.voucherRepo.GetPool(poolID)
+}
+
+// ConsumeCode marks a handed out code as used by its owner
+func (voucherUseCase *VoucherUseCaseImpl) ConsumeCode(code string, userID string) (*entities.VoucherCode, error) {
+	return voucherUseCase.voucherRepo.ConsumeCode(strings.TrimSpace(code), userID)
+}
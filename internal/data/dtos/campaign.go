@@ -9,9 +9,12 @@ import (
 type Status string
 
 const (
-	Active Status = "active"
-	Paused Status = "paused"
-	Ended  Status = "ended"
+	Draft     Status = "draft"
+	Scheduled Status = "scheduled"
+	Active    Status = "active"
+	Paused    Status = "paused"
+	Ended     Status = "ended"
+	Archived  Status = "archived"
 )
 
 // CampaignDTO represents the data structure of a Campaign received from the Campaign Service.
@@ -25,12 +28,142 @@ type CampaignDTO struct {
 	Budget               float64             `json:"budget"`
 	AllocatedRewards     int                 `json:"allocated_rewards"`
 	TotalEligibleRewards int                 `json:"total_eligible_rewards"`
-	TargetAudience       string              `json:"target_audience"`
-	EligibilityCriteria  EligibilityCriteria // Criteria that must be met to redeem the reward
+	TargetAudience       string              `json:"target_audience"`      // Display label, targeting is done by EligibilityCriteria.SegmentIDs
+	EligibilityCriteria  EligibilityCriteria `json:"eligibility_criteria"` // Criteria that must be met to redeem the reward
+	Priority             int                 `json:"priority"`             // Higher wins under the priority selection strategy
+	RewardValue          float64             `json:"reward_value"`         // Value of one reward as perceived by the customer
+	RewardCost           float64             `json:"reward_cost"`          // Estimated cost of allocating one reward
+	Stackable            bool                `json:"stackable"`            // Whether the campaign can be won together with other campaigns
+	StackingGroup        string              `json:"stacking_group"`       // Stackable campaigns of the same group never combine
+	Timezone             string              `json:"timezone"`             // IANA timezone the time windows are evaluated in, UTC when empty
 }
 
 // EligibilityCriteria defines conditions that must be met to redeem the reward
 type EligibilityCriteria struct {
-	MinimumPurchaseAmount float64
+	MinimumPurchaseAmount float64          `json:"minimum_purchase_amount"`
+	UserLimits            UserRewardLimits `json:"user_limits"`   // Caps on the rewards a single user can receive
+	ProductRules          ProductRules     `json:"product_rules"` // Products and categories the order must or must not contain
+	GeoTargeting          GeoTargeting     `json:"geo_targeting"` // Locations the reward can be delivered to
+	SegmentIDs            []SegmentID      `json:"segment_ids"`   // The user must belong to one of the segments, empty targets everyone
+	TimeWindows           []TimeWindow     `json:"time_windows"`  // The order must be placed within one of the windows, empty means any time
+	PaymentRules          PaymentRules     `json:"payment_rules"` // Payment instruments the order must or must not be paid with
+	ChannelRules          ChannelRules     `json:"channel_rules"` // Sales channels the order must or must not come from
+	Combinability         Combinability    `json:"combinability"` // How the reward combines with coupons and discounts on the order
+	Conditions            string           `json:"conditions"`    // Expression the order must satisfy, e.g. order.value >= 500 && user.tier in ["gold"]
+	Tiers                 []RewardTier     `json:"tiers"`         // Order value bands with their own reward group, empty gives every order the campaign reward group
+	Referral              ReferralRewards  `json:"referral"`      // Reward groups of a referral campaign, which only pays out referrals
 	// Additional criteria can be added here
 }
+
+// RewardTier gives its reward group to orders of at least the minimum purchase amount. The highest qualifying
+// tier with stock left wins, the campaign reward group is the base tier below all of them.
+type RewardTier struct {
+	Name                  string  `json:"name"`
+	MinimumPurchaseAmount float64 `json:"minimum_purchase_amount"`
+	RewardGroupID         int64   `json:"reward_group_id"`
+	RewardValue           float64 `json:"reward_value"` // Value of the tier reward for the selection strategies, the campaign reward value when zero
+	MaxRewards            int     `json:"max_rewards"`  // Rewards the tier can give out in total, zero means no cap
+}
+
+// ReferralRewards makes a referral campaign, paid out on the first qualifying order of a referred user.
+// A zero reward group gives that side nothing.
+type ReferralRewards struct {
+	ReferrerRewardGroupID int64 `json:"referrer_reward_group_id"` // Reward of the user who shared the referral
+	RefereeRewardGroupID  int64 `json:"referee_reward_group_id"`  // Reward of the referred user placing the order
+}
+
+// ProductRules restricts a campaign to orders with, or without, given products and categories
+type ProductRules struct {
+	RequiredProductIDs []int64               `json:"required_product_ids"` // Every listed product must be in the order
+	ExcludedProductIDs []int64               `json:"excluded_product_ids"` // None of the listed products may be in the order
+	RequiredCategories []CategoryRequirement `json:"required_categories"`  // Every listed category must be in the order
+	ExcludedCategories []string              `json:"excluded_categories"`  // None of the listed categories may be in the order
+}
+
+// CategoryRequirement is a category the order must contain, a zero minimum means any amount
+type CategoryRequirement struct {
+	Category    string  `json:"category"`
+	MinQuantity int     `json:"min_quantity"` // Units of the category in the order
+	MinAmount   float64 `json:"min_amount"`   // Value of the category in the order
+}
+
+// GeoTargeting restricts a campaign to delivery locations, empty lists do not restrict.
+// Countries and states are matched case-insensitively, postal codes by prefix.
+type GeoTargeting struct {
+	AllowedCountries   []string  `json:"allowed_countries"`
+	DeniedCountries    []string  `json:"denied_countries"`
+	AllowedStates      []string  `json:"allowed_states"`
+	DeniedStates       []string  `json:"denied_states"`
+	AllowedPostalCodes []string  `json:"allowed_postal_codes"`
+	DeniedPostalCodes  []string  `json:"denied_postal_codes"`
+	Areas              []GeoArea `json:"areas"` // The location must lie within one of the areas
+}
+
+// GeoArea is either a circle around Center or a polygon
+type GeoArea struct {
+	Center   *GeoPoint  `json:"center,omitempty"`
+	RadiusKm float64    `json:"radius_km,omitempty"`
+	Polygon  []GeoPoint `json:"polygon,omitempty"`
+}
+
+// GeoPoint is a latitude/longitude pair in degrees
+type GeoPoint struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// PaymentRules restricts a campaign to payment instruments, empty lists do not restrict.
+// Issuers are matched case-insensitively.
+type PaymentRules struct {
+	AllowedTypes    []PaymentType `json:"allowed_types"`
+	ExcludedTypes   []PaymentType `json:"excluded_types"`
+	AllowedIssuers  []string      `json:"allowed_issuers"`
+	ExcludedIssuers []string      `json:"excluded_issuers"`
+}
+
+// ChannelRules restricts a campaign to sales channels, empty lists do not restrict
+type ChannelRules struct {
+	AllowedChannels  []SalesChannel `json:"allowed_channels"`
+	ExcludedChannels []SalesChannel `json:"excluded_channels"`
+}
+
+// ConflictAction is what happens to the reward when it conflicts with a promotion on the order
+type ConflictAction string
+
+const (
+	ConflictReject    ConflictAction = "reject"    // The order is not eligible
+	ConflictDowngrade ConflictAction = "downgrade" // The order gets the downgrade reward group instead
+)
+
+// Combinability declares which applied promotions the reward conflicts with. Promotions are
+// matched on code or exclusivity group, case-insensitively. CombinableWith overrides every other rule,
+// the reward is combinable with everything else unless it is exclusive.
+type Combinability struct {
+	Exclusive              bool           `json:"exclusive"`                 // Conflicts with any promotion
+	ExclusivityGroups      []string       `json:"exclusivity_groups"`        // Conflicts with promotions of these groups
+	CombinableWith         []string       `json:"combinable_with"`           // Codes or groups that never conflict
+	ConflictsWith          []string       `json:"conflicts_with"`            // Codes or groups that always conflict
+	OnConflict             ConflictAction `json:"on_conflict"`               // Reject when empty
+	DowngradeRewardGroupID int64          `json:"downgrade_reward_group_id"` // Reward group given on a downgrade
+	DowngradeRewardValue   float64        `json:"downgrade_reward_value"`    // Value of the downgraded reward, used for selection
+}
+
+// TimeWindow is a recurring window in the campaign timezone, a cron-like rule where empty fields match any value.
+// "8-10 AM" is {start_time: "08:00", end_time: "10:00"}, "weekends only" is {weekdays: [0, 6]}
+// and "every Friday in December" is {months: [12], weekdays: [5]}.
+// An end time before the start time makes the window cross midnight.
+type TimeWindow struct {
+	Months      []int  `json:"months,omitempty"`        // 1 (January) to 12
+	DaysOfMonth []int  `json:"days_of_month,omitempty"` // 1 to 31
+	Weekdays    []int  `json:"weekdays,omitempty"`      // 0 (Sunday) to 6
+	StartTime   string `json:"start_time,omitempty"`    // HH:MM, inclusive
+	EndTime     string `json:"end_time,omitempty"`      // HH:MM, exclusive
+}
+
+// UserRewardLimits caps how many rewards a single user can receive, a zero value means unlimited
+type UserRewardLimits struct {
+	MaxPerCampaign int `json:"max_per_campaign"` // Rewards from this campaign
+	MaxPerWindow   int `json:"max_per_window"`   // Rewards from this campaign within the rolling window
+	WindowDays     int `json:"window_days"`      // Length of the rolling window
+	MaxLifetime    int `json:"max_lifetime"`     // Rewards from all campaigns together
+}
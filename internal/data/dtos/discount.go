@@ -0,0 +1,29 @@
+package dtos
+
+import "time"
+
+// DiscountKind tells how a discount reward is handed to the customer
+type DiscountKind string
+
+const (
+	DiscountKindCoupon      DiscountKind = "coupon"       // a coupon code redeemable on a later order
+	DiscountKindStoreCredit DiscountKind = "store_credit" // credit added to the wallet of the customer
+)
+
+// DiscountIssueRequest asks the discount issuer to create a coupon or a wallet credit
+type DiscountIssueRequest struct {
+	IdempotencyKey string       `json:"idempotency_key"`      // Issuing twice with the same key returns the first discount
+	UserID         string       `json:"user_id"`              // Customer the discount is issued to
+	OrderID        int64        `json:"order_id"`             // Order which earned the discount
+	RewardItemID   string       `json:"reward_item_id"`       // Reward item the discount stands for
+	Kind           DiscountKind `json:"kind"`                 // Coupon or store credit
+	Amount         float64      `json:"amount"`               // Value of the discount
+	ExpiresAt      *time.Time   `json:"expires_at,omitempty"` // When the discount stops being usable
+}
+
+// DiscountIssueResponse describes an issued discount
+type DiscountIssueResponse struct {
+	Reference string       `json:"reference"`      // Reference of the coupon or the wallet transaction at the issuer
+	Kind      DiscountKind `json:"kind"`           // Coupon or store credit
+	Code      string       `json:"code,omitempty"` // Code the customer enters at checkout, only for coupons
+}
@@ -0,0 +1,7 @@
+package dtos
+
+// ChooseGiftRequest is the body of the gift choice endpoint
+type ChooseGiftRequest struct {
+	UserID    string `json:"user_id"`    // User choosing, it has to be the one the reward was allocated to
+	ProductID int64  `json:"product_id"` // One of the options of the choice
+}
@@ -2,7 +2,65 @@ package dtos
 
 // OrderDTO represents the data structure for an order request to check reward eligibility.
 type OrderDTO struct {
-	OrderID    int64   `json:"order_id"`    // Unique identifier for the order
-	OrderValue float64 `json:"order_value"` // Total value of the order
-	Quantity   int     `json:"quantity"`    // Number of items in the order
+	OrderID    int64          `json:"order_id"`    // Unique identifier for the order
+	UserID     string         `json:"user_id"`     // User who placed the order
+	OrderValue float64        `json:"order_value"` // Total value of the order
+	Quantity   int            `json:"quantity"`    // Number of items in the order
+	Items      []OrderItemDTO `json:"items"`       // Line items, taken from the cached order when left empty
+	Location   *UserLocation  `json:"location"`    // Delivery location, taken from the user details when left empty
+	Payment    *PaymentMethod `json:"payment"`     // Instrument the order was paid with
+	Channel    SalesChannel   `json:"channel"`     // Channel the order was placed through
+
+	AppliedPromotions []AppliedPromotion `json:"applied_promotions"` // Coupons and discounts already applied to the order
+}
+
+// PromotionType is the kind of promotion applied to an order
+type PromotionType string
+
+const (
+	PromotionTypeCoupon   PromotionType = "coupon"
+	PromotionTypeDiscount PromotionType = "discount"
+)
+
+// AppliedPromotion is a coupon or discount applied to an order
+type AppliedPromotion struct {
+	Code             string        `json:"code"`              // Promo code, or the id of an automatic discount
+	Type             PromotionType `json:"type"`              // Coupon or discount
+	ExclusivityGroup string        `json:"exclusivity_group"` // Group the promotion belongs to, e.g. "sitewide"
+	Amount           float64       `json:"amount"`            // Amount taken off the order
+}
+
+// PaymentType is the kind of instrument an order was paid with
+type PaymentType string
+
+const (
+	PaymentTypeCard       PaymentType = "card"
+	PaymentTypeWallet     PaymentType = "wallet"
+	PaymentTypeUPI        PaymentType = "upi"
+	PaymentTypeNetBanking PaymentType = "net_banking"
+	PaymentTypeCash       PaymentType = "cash"
+)
+
+// PaymentMethod describes the payment instrument of an order
+type PaymentMethod struct {
+	Type   PaymentType `json:"type"`   // Kind of instrument
+	Issuer string      `json:"issuer"` // Bank or wallet provider that issued the instrument
+}
+
+// SalesChannel is where an order was placed
+type SalesChannel string
+
+const (
+	ChannelWeb SalesChannel = "web"
+	ChannelApp SalesChannel = "app"
+	ChannelPOS SalesChannel = "pos"
+)
+
+// OrderItemDTO represents a line item of an order.
+type OrderItemDTO struct {
+	ProductID int64   `json:"product_id"` // Unique identifier for the product
+	SKU       string  `json:"sku"`        // Stock keeping unit of the product
+	Category  string  `json:"category"`   // Category the product belongs to
+	Quantity  int     `json:"quantity"`   // Number of units ordered
+	Price     float64 `json:"price"`      // Price per unit
 }
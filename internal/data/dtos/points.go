@@ -0,0 +1,7 @@
+package dtos
+
+// RedeemPointsRequest is the body of the points redeem endpoint
+type RedeemPointsRequest struct {
+	Points    int64  `json:"points"`    // Points to spend
+	Reference string `json:"reference"` // Unique reference of the redemption, makes retries safe
+}
@@ -0,0 +1,9 @@
+package dtos
+
+// RedeemRewardRequest is the body of the redeem endpoint
+type RedeemRewardRequest struct {
+	UserID    string  `json:"user_id"`             // User redeeming the reward, it has to be the one the reward was allocated to
+	Amount    float64 `json:"amount"`              // Part of the value to redeem, the whole balance when 0
+	OrderID   *int64  `json:"order_id,omitempty"`  // Checkout order the reward is redeemed on
+	Reference string  `json:"reference,omitempty"` // Unique reference of the redemption, makes retries safe
+}
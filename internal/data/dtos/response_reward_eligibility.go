@@ -1,8 +1,44 @@
 package dtos
 
+import uuid "github.com/satori/go.uuid"
+
+// IneligibilityReason is a machine readable reason for an order not qualifying for a reward
+type IneligibilityReason string
+
+const (
+	ReasonNoRunningCampaign      IneligibilityReason = "no_running_campaign"
+	ReasonCampaignInactive       IneligibilityReason = "campaign_inactive"
+	ReasonAllocationLimitReached IneligibilityReason = "allocation_limit_reached"
+	ReasonBudgetExhausted        IneligibilityReason = "budget_exhausted"
+	ReasonVoucherStockExhausted  IneligibilityReason = "voucher_stock_exhausted"
+	ReasonUserRewardLimitReached IneligibilityReason = "user_reward_limit_reached"
+	ReasonOrderValueTooLow       IneligibilityReason = "order_value_too_low"
+	ReasonProductRulesNotMet     IneligibilityReason = "product_rules_not_met"
+	ReasonOutsideTargetArea      IneligibilityReason = "outside_target_area"
+	ReasonNotShippable           IneligibilityReason = "not_shippable"
+	ReasonNotInSegment           IneligibilityReason = "not_in_segment"
+	ReasonOutsideTimeWindow      IneligibilityReason = "outside_time_window"
+	ReasonPaymentNotAccepted     IneligibilityReason = "payment_not_accepted"
+	ReasonChannelNotAccepted     IneligibilityReason = "channel_not_accepted"
+	ReasonPromotionConflict      IneligibilityReason = "promotion_conflict"
+	ReasonConditionsNotMet       IneligibilityReason = "conditions_not_met"
+	ReasonReferralCampaign       IneligibilityReason = "referral_campaign"
+	ReasonOrderCompleted         IneligibilityReason = "order_completed"
+)
+
 // RewardEligibilityResponse represents the response of reward eligibility check.
 type RewardEligibilityResponse struct {
-	Eligible bool   `json:"eligible"` // Eligibility status (true or false)
-	Message  string `json:"message"`  // A message providing more context
-	Reason   string `json:"reason"`   // (Optional) Reason for ineligibility
+	Eligible    bool             `json:"eligible"`               // Eligibility status (true or false)
+	Message     string           `json:"message"`                // A message providing more context
+	Reason      string           `json:"reason"`                 // (Optional) Reason for ineligibility
+	CampaignIDs []uuid.UUID      `json:"campaign_ids,omitempty"` // Campaigns the order wins rewards from, best first
+	Rewards     []EligibleReward `json:"rewards,omitempty"`      // Reward group to allocate for each of the campaigns
+}
+
+// EligibleReward is the reward an order wins from a campaign
+type EligibleReward struct {
+	CampaignID    uuid.UUID `json:"campaign_id"`
+	RewardGroupID int64     `json:"reward_group_id"`
+	Downgraded    bool      `json:"downgraded"`     // The reward was downgraded because of a promotion on the order
+	Tier          string    `json:"tier,omitempty"` // Value tier the reward group was chosen from, empty for the base reward
 }
@@ -0,0 +1,12 @@
+package dtos
+
+// SegmentID identifies a customer segment of the segment service
+type SegmentID string
+
+const (
+	SegmentNewCustomer   SegmentID = "new_customer"
+	SegmentLoyaltySilver SegmentID = "loyalty_silver"
+	SegmentLoyaltyGold   SegmentID = "loyalty_gold"
+	SegmentVIP           SegmentID = "vip"
+	SegmentDormant       SegmentID = "dormant"
+)
@@ -4,6 +4,7 @@ type UserDetail struct {
 	UserID        string       `json:"user_id"`
 	UserName      string       `json:"user_name"`
 	Email         string       `json:"email"`
+	Tier          string       `json:"tier"` // Loyalty tier, e.g. gold or vip
 	Location      UserLocation `json:"location"`
 	PhoneNumber   *string      `json:"phone_number,omitempty"`   // Optional contact number for delivery
 	DeliveryNotes *string      `json:"delivery_notes,omitempty"` // Optional delivery instructions
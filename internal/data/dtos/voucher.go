@@ -0,0 +1,6 @@
+package dtos
+
+// ConsumeVoucherCodeRequest is the body of the voucher code consume endpoint
+type ConsumeVoucherCodeRequest struct {
+	UserID string `json:"user_id"` // User using the code, it has to be the one the code was assigned to
+}
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS order_reward_item;
+DROP TABLE IF EXISTS order_reward_group;
+DROP TABLE IF EXISTS reward_group_reward_products;
+DROP TABLE IF EXISTS reward_group_reward_items;
+DROP TABLE IF EXISTS reward_items;
+DROP TABLE IF EXISTS reward_groups;
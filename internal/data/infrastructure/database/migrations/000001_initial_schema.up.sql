@@ -0,0 +1,51 @@
+CREATE TABLE IF NOT EXISTS reward_groups (
+    id          BIGSERIAL PRIMARY KEY,
+    name        TEXT        NOT NULL,
+    expires_at  TIMESTAMPTZ NULL,
+    campaign_id BIGINT      NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_reward_groups_campaign_id ON reward_groups (campaign_id);
+
+CREATE TABLE IF NOT EXISTS reward_items (
+    id                BIGSERIAL PRIMARY KEY,
+    type              TEXT           NOT NULL CHECK (type IN ('product', 'discount', 'voucher')),
+    item_id           TEXT           NULL,
+    discount_amount   NUMERIC(12, 2) NULL,
+    voucher_code      TEXT           NULL,
+    product_id        TEXT           NULL,
+    expiration_date   TIMESTAMPTZ    NULL,
+    reward_conditions JSONB          NULL,
+    is_active         BOOLEAN        NOT NULL DEFAULT TRUE,
+    metadata          JSONB          NULL
+);
+
+-- reward items which are allocated from a reward group
+CREATE TABLE IF NOT EXISTS reward_group_reward_items (
+    reward_group_id BIGINT NOT NULL,
+    reward_item_id  BIGINT NOT NULL,
+    PRIMARY KEY (reward_group_id, reward_item_id)
+);
+
+-- products that make up a reward group
+CREATE TABLE IF NOT EXISTS reward_group_reward_products (
+    reward_group_id BIGINT NOT NULL,
+    product_id      BIGINT NOT NULL,
+    PRIMARY KEY (reward_group_id, product_id)
+);
+
+CREATE TABLE IF NOT EXISTS order_reward_group (
+    order_id        BIGINT NOT NULL,
+    reward_group_id BIGINT NOT NULL,
+    PRIMARY KEY (order_id, reward_group_id)
+);
+
+CREATE TABLE IF NOT EXISTS order_reward_item (
+    order_id       BIGINT      NOT NULL,
+    reward_item_id BIGINT      NOT NULL,
+    shipment_id    BIGINT      NULL,
+    allocated_date TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    is_redeemed    BOOLEAN     NULL,
+    redeemed_date  TIMESTAMPTZ NULL,
+    PRIMARY KEY (order_id, reward_item_id)
+);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS campaigns (
+    id                     UUID PRIMARY KEY,
+    reward_group_id        BIGINT         NOT NULL,
+    name                   TEXT           NOT NULL,
+    start_date             TIMESTAMPTZ    NOT NULL,
+    end_date               TIMESTAMPTZ    NOT NULL,
+    status                 TEXT           NOT NULL,
+    budget                 NUMERIC(14, 2) NOT NULL,
+    allocated_rewards      INTEGER        NOT NULL DEFAULT 0,
+    total_eligible_rewards INTEGER        NOT NULL DEFAULT 0,
+    target_audience        TEXT           NOT NULL DEFAULT '',
+    eligibility_criteria   JSONB          NOT NULL DEFAULT '{}',
+    created_at             TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    updated_at             TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    CHECK (start_date <= end_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaigns_status_dates ON campaigns (status, start_date, end_date);
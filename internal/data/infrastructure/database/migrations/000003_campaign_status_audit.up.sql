@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS campaign_status_audit (
+    id          BIGSERIAL PRIMARY KEY,
+    campaign_id UUID        NOT NULL REFERENCES campaigns (id) ON DELETE CASCADE,
+    from_status TEXT        NOT NULL,
+    to_status   TEXT        NOT NULL,
+    actor       TEXT        NOT NULL,
+    reason      TEXT        NOT NULL DEFAULT '',
+    changed_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaign_status_audit_campaign_id ON campaign_status_audit (campaign_id, changed_at);
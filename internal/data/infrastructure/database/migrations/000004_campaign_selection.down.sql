@@ -0,0 +1,6 @@
+ALTER TABLE campaigns
+    DROP COLUMN IF EXISTS stacking_group,
+    DROP COLUMN IF EXISTS stackable,
+    DROP COLUMN IF EXISTS reward_cost,
+    DROP COLUMN IF EXISTS reward_value,
+    DROP COLUMN IF EXISTS priority;
@@ -0,0 +1,6 @@
+ALTER TABLE campaigns
+    ADD COLUMN IF NOT EXISTS priority       INTEGER        NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS reward_value   NUMERIC(12, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS reward_cost    NUMERIC(12, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS stackable      BOOLEAN        NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS stacking_group TEXT           NOT NULL DEFAULT '';
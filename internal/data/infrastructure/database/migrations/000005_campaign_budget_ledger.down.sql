@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS campaign_budget_ledger;
+
+ALTER TABLE reward_items
+    DROP COLUMN IF EXISTS cost;
@@ -0,0 +1,14 @@
+ALTER TABLE reward_items
+    ADD COLUMN IF NOT EXISTS cost NUMERIC(12, 2) NOT NULL DEFAULT 0 CHECK (cost >= 0);
+
+-- debits are positive, credits negative, remaining budget = campaigns.budget - SUM(amount)
+CREATE TABLE IF NOT EXISTS campaign_budget_ledger (
+    id          BIGSERIAL PRIMARY KEY,
+    campaign_id UUID           NOT NULL REFERENCES campaigns (id) ON DELETE CASCADE,
+    order_id    BIGINT         NOT NULL,
+    entry_type  TEXT           NOT NULL CHECK (entry_type IN ('reward', 'shipping', 'reversal')),
+    amount      NUMERIC(14, 2) NOT NULL,
+    created_at  TIMESTAMPTZ    NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaign_budget_ledger_campaign_order ON campaign_budget_ledger (campaign_id, order_id);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS user_reward_ledger (
+    id           BIGSERIAL PRIMARY KEY,
+    user_id      TEXT        NOT NULL,
+    campaign_id  UUID        NOT NULL REFERENCES campaigns (id) ON DELETE CASCADE,
+    order_id     BIGINT      NOT NULL,
+    allocated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    reversed_at  TIMESTAMPTZ NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_reward_ledger_user ON user_reward_ledger (user_id, campaign_id, allocated_at)
+    WHERE reversed_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_reward_ledger_order ON user_reward_ledger (campaign_id, order_id)
+    WHERE reversed_at IS NULL;
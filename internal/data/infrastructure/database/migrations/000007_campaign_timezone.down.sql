@@ -0,0 +1,2 @@
+ALTER TABLE campaigns
+    DROP COLUMN IF EXISTS timezone;
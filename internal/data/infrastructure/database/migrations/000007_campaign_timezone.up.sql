@@ -0,0 +1,2 @@
+ALTER TABLE campaigns
+    ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'UTC';
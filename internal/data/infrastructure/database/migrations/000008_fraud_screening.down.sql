@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS reward_reviews;
+DROP TABLE IF EXISTS fraud_blocklist;
+DROP TABLE IF EXISTS reward_fingerprints;
@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS reward_fingerprints (
+    id           BIGSERIAL PRIMARY KEY,
+    order_id     BIGINT      NOT NULL,
+    campaign_id  UUID        NOT NULL REFERENCES campaigns (id) ON DELETE CASCADE,
+    user_id      TEXT        NOT NULL,
+    device_id    TEXT        NOT NULL DEFAULT '',
+    address_hash TEXT        NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (campaign_id, order_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reward_fingerprints_user ON reward_fingerprints (user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_reward_fingerprints_device ON reward_fingerprints (device_id, created_at) WHERE device_id <> '';
+CREATE INDEX IF NOT EXISTS idx_reward_fingerprints_address ON reward_fingerprints (address_hash, created_at) WHERE address_hash <> '';
+
+CREATE TABLE IF NOT EXISTS fraud_blocklist (
+    kind       TEXT        NOT NULL,
+    value      TEXT        NOT NULL,
+    reason     TEXT        NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (kind, value)
+);
+
+CREATE TABLE IF NOT EXISTS reward_reviews (
+    id          BIGSERIAL PRIMARY KEY,
+    order_id    BIGINT      NOT NULL,
+    user_id     TEXT        NOT NULL,
+    campaign_id UUID        NOT NULL REFERENCES campaigns (id) ON DELETE CASCADE,
+    event       JSONB       NOT NULL,
+    signals     JSONB       NOT NULL DEFAULT '[]',
+    status      TEXT        NOT NULL DEFAULT 'pending',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    decided_at  TIMESTAMPTZ NULL,
+    decided_by  TEXT        NOT NULL DEFAULT '',
+    note        TEXT        NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reward_reviews_pending ON reward_reviews (campaign_id, order_id)
+    WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_reward_reviews_status ON reward_reviews (status, created_at);
@@ -0,0 +1,2 @@
+ALTER TABLE reward_reviews
+    DROP COLUMN IF EXISTS reasons;
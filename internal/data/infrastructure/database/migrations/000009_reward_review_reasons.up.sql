@@ -0,0 +1,2 @@
+ALTER TABLE reward_reviews
+    ADD COLUMN IF NOT EXISTS reasons JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS voucher_codes;
+DROP TABLE IF EXISTS voucher_pools;
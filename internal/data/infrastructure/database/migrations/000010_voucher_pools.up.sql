@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS voucher_pools (
+    id              BIGSERIAL PRIMARY KEY,
+    name            TEXT        NOT NULL,
+    reward_group_id BIGINT      NOT NULL,
+    format          JSONB       NOT NULL,
+    expires_at      TIMESTAMPTZ NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_voucher_pools_reward_group ON voucher_pools (reward_group_id);
+
+CREATE TABLE IF NOT EXISTS voucher_codes (
+    id          BIGSERIAL PRIMARY KEY,
+    pool_id     BIGINT      NOT NULL REFERENCES voucher_pools (id) ON DELETE CASCADE,
+    code        TEXT        NOT NULL UNIQUE,
+    status      TEXT        NOT NULL DEFAULT 'available'
+        CHECK (status IN ('available', 'assigned', 'consumed', 'voided')),
+    order_id    BIGINT      NULL,
+    user_id     TEXT        NOT NULL DEFAULT '',
+    assigned_at TIMESTAMPTZ NULL,
+    consumed_at TIMESTAMPTZ NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_voucher_codes_available ON voucher_codes (pool_id, id) WHERE status = 'available';
+CREATE INDEX IF NOT EXISTS idx_voucher_codes_order ON voucher_codes (order_id) WHERE order_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_voucher_codes_pool_order ON voucher_codes (pool_id, order_id)
+    WHERE status = 'assigned';
@@ -0,0 +1,5 @@
+ALTER TABLE order_reward_item
+    DROP COLUMN IF EXISTS issued_reference;
+
+ALTER TABLE reward_items
+    DROP COLUMN IF EXISTS discount_kind;
@@ -0,0 +1,6 @@
+ALTER TABLE reward_items
+    ADD COLUMN IF NOT EXISTS discount_kind TEXT NULL CHECK (discount_kind IN ('coupon', 'store_credit'));
+
+-- reference of the coupon or wallet credit issued for a non-physical reward item
+ALTER TABLE order_reward_item
+    ADD COLUMN IF NOT EXISTS issued_reference TEXT NULL;
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS reward_redemptions;
+
+ALTER TABLE order_reward_item
+    DROP COLUMN IF EXISTS expires_at,
+    DROP COLUMN IF EXISTS redeemed_amount,
+    DROP COLUMN IF EXISTS value,
+    DROP COLUMN IF EXISTS user_id,
+    DROP COLUMN IF EXISTS id;
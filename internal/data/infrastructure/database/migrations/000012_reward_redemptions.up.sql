@@ -0,0 +1,20 @@
+-- allocated reward items become addressable and carry what is needed to redeem them
+ALTER TABLE order_reward_item
+    ADD COLUMN IF NOT EXISTS id              BIGSERIAL UNIQUE,
+    ADD COLUMN IF NOT EXISTS user_id         TEXT           NULL,
+    ADD COLUMN IF NOT EXISTS value           NUMERIC(12, 2) NOT NULL DEFAULT 0 CHECK (value >= 0),
+    ADD COLUMN IF NOT EXISTS redeemed_amount NUMERIC(12, 2) NOT NULL DEFAULT 0 CHECK (redeemed_amount >= 0),
+    ADD COLUMN IF NOT EXISTS expires_at      TIMESTAMPTZ    NULL;
+
+-- every (partial) redemption of an allocated reward item, the reference makes redemptions idempotent
+CREATE TABLE IF NOT EXISTS reward_redemptions (
+    id                   BIGSERIAL PRIMARY KEY,
+    order_reward_item_id BIGINT         NOT NULL REFERENCES order_reward_item (id) ON DELETE CASCADE,
+    user_id              TEXT           NOT NULL,
+    order_id             BIGINT         NULL,
+    reference            TEXT           NOT NULL UNIQUE,
+    amount               NUMERIC(12, 2) NOT NULL CHECK (amount >= 0),
+    redeemed_at          TIMESTAMPTZ    NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_reward_redemptions_reward ON reward_redemptions (order_reward_item_id, redeemed_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_order_reward_item_unexpired;
+
+ALTER TABLE order_reward_item
+    DROP COLUMN IF EXISTS reminded_at,
+    DROP COLUMN IF EXISTS expired_at;
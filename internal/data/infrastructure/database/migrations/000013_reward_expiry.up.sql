@@ -0,0 +1,6 @@
+ALTER TABLE order_reward_item
+    ADD COLUMN IF NOT EXISTS expired_at  TIMESTAMPTZ NULL,
+    ADD COLUMN IF NOT EXISTS reminded_at TIMESTAMPTZ NULL;
+
+CREATE INDEX IF NOT EXISTS idx_order_reward_item_unexpired ON order_reward_item (expires_at)
+    WHERE expired_at IS NULL AND is_redeemed IS NOT TRUE;
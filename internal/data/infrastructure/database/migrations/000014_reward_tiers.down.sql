@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_user_reward_ledger_tier;
+
+ALTER TABLE user_reward_ledger
+    DROP COLUMN IF EXISTS reward_group_id;
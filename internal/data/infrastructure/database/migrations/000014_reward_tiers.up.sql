@@ -0,0 +1,5 @@
+ALTER TABLE user_reward_ledger
+    ADD COLUMN IF NOT EXISTS reward_group_id BIGINT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_user_reward_ledger_tier ON user_reward_ledger (campaign_id, reward_group_id)
+    WHERE reversed_at IS NULL;
@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS points_ledger_entries;
+DROP TABLE IF EXISTS points_transactions;
+
+DELETE FROM reward_group_reward_items
+WHERE reward_item_id IN (SELECT id FROM reward_items WHERE type = 'points');
+DELETE FROM reward_items WHERE type = 'points';
+
+ALTER TABLE reward_items
+    DROP COLUMN IF EXISTS points,
+    DROP CONSTRAINT IF EXISTS reward_items_type_check;
+ALTER TABLE reward_items
+    ADD CONSTRAINT reward_items_type_check CHECK (type IN ('product', 'discount', 'voucher'));
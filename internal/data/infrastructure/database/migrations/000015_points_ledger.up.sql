@@ -0,0 +1,38 @@
+ALTER TABLE reward_items
+    DROP CONSTRAINT IF EXISTS reward_items_type_check;
+ALTER TABLE reward_items
+    ADD CONSTRAINT reward_items_type_check CHECK (type IN ('product', 'discount', 'voucher', 'points')),
+    ADD COLUMN IF NOT EXISTS points BIGINT NULL CHECK (points > 0);
+
+-- what moved the points of a user, reversals and expiries take their points from the accrual in source_id
+CREATE TABLE IF NOT EXISTS points_transactions (
+    id             BIGSERIAL PRIMARY KEY,
+    user_id        TEXT        NOT NULL,
+    type           TEXT        NOT NULL CHECK (type IN ('accrue', 'reverse', 'expire', 'redeem')),
+    points         BIGINT      NOT NULL CHECK (points > 0),
+    reference      TEXT        NOT NULL UNIQUE,
+    order_id       BIGINT      NULL,
+    campaign_id    UUID        NULL,
+    reward_item_id BIGINT      NULL,
+    source_id      BIGINT      NULL REFERENCES points_transactions (id),
+    expires_at     TIMESTAMPTZ NULL,
+    expired_at     TIMESTAMPTZ NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_points_transactions_user ON points_transactions (user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_points_transactions_order ON points_transactions (order_id)
+    WHERE type = 'accrue';
+CREATE INDEX IF NOT EXISTS idx_points_transactions_due ON points_transactions (expires_at)
+    WHERE type = 'accrue' AND expired_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_points_transactions_source ON points_transactions (source_id);
+
+-- double-entry lines, the entries of a transaction sum to zero
+CREATE TABLE IF NOT EXISTS points_ledger_entries (
+    id             BIGSERIAL PRIMARY KEY,
+    transaction_id BIGINT NOT NULL REFERENCES points_transactions (id) ON DELETE CASCADE,
+    account        TEXT   NOT NULL,
+    amount         BIGINT NOT NULL CHECK (amount <> 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_points_ledger_entries_account ON points_ledger_entries (account);
@@ -0,0 +1,17 @@
+-- the first qualifying order of a referee attributed to the referrer, a referee is referred at most once per campaign
+CREATE TABLE IF NOT EXISTS referrals (
+    id          BIGSERIAL PRIMARY KEY,
+    campaign_id UUID        NOT NULL REFERENCES campaigns (id) ON DELETE CASCADE,
+    referrer_id TEXT        NOT NULL,
+    referee_id  TEXT        NOT NULL,
+    order_id    BIGINT      NOT NULL,
+    status      TEXT        NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'rewarded', 'reversed')),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    rewarded_at TIMESTAMPTZ NULL,
+    reversed_at TIMESTAMPTZ NULL,
+    CHECK (referrer_id <> referee_id),
+    UNIQUE (campaign_id, referee_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_referrals_order ON referrals (order_id);
+CREATE INDEX IF NOT EXISTS idx_referrals_referrer ON referrals (referrer_id, created_at);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS gift_choices;
+
+ALTER TABLE reward_groups
+    DROP COLUMN IF EXISTS choice_window_hours,
+    DROP COLUMN IF EXISTS default_product_id,
+    DROP COLUMN IF EXISTS choose_one;
@@ -0,0 +1,24 @@
+ALTER TABLE reward_groups
+    ADD COLUMN IF NOT EXISTS choose_one          BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS default_product_id  BIGINT  NULL,
+    ADD COLUMN IF NOT EXISTS choice_window_hours INT     NOT NULL DEFAULT 0 CHECK (choice_window_hours >= 0);
+
+-- a choose-one reward waiting for the user to pick the product which ships, one per order and reward group
+CREATE TABLE IF NOT EXISTS gift_choices (
+    id                 BIGSERIAL PRIMARY KEY,
+    order_id           BIGINT      NOT NULL,
+    user_id            TEXT        NOT NULL,
+    campaign_id        UUID        NOT NULL,
+    reward_group_id    BIGINT      NOT NULL REFERENCES reward_groups (id),
+    options            BIGINT[]    NOT NULL,
+    default_product_id BIGINT      NOT NULL,
+    chosen_product_id  BIGINT      NULL,
+    status             TEXT        NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'chosen', 'defaulted', 'cancelled')),
+    deadline           TIMESTAMPTZ NOT NULL,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    decided_at         TIMESTAMPTZ NULL,
+    UNIQUE (order_id, reward_group_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_gift_choices_due ON gift_choices (deadline)
+    WHERE status = 'pending';
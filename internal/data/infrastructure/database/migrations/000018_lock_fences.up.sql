@@ -0,0 +1,6 @@
+-- newest fencing token seen for every lock key, writes carrying an older token are rejected
+CREATE TABLE IF NOT EXISTS lock_fences (
+    lock_key   TEXT        PRIMARY KEY,
+    token      BIGINT      NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,5 @@
+UPDATE gift_choices SET status = 'pending' WHERE status = 'settling';
+
+ALTER TABLE gift_choices
+    DROP CONSTRAINT IF EXISTS gift_choices_status_check,
+    ADD CONSTRAINT gift_choices_status_check CHECK (status IN ('pending', 'chosen', 'defaulted', 'cancelled'));
@@ -0,0 +1,4 @@
+-- a choice is claimed while its product ships, so a concurrent choice and the deadline can not both ship a product
+ALTER TABLE gift_choices
+    DROP CONSTRAINT IF EXISTS gift_choices_status_check,
+    ADD CONSTRAINT gift_choices_status_check CHECK (status IN ('pending', 'settling', 'chosen', 'defaulted', 'cancelled'));
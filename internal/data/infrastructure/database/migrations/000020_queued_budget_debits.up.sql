@@ -0,0 +1,9 @@
+-- costs which were incurred but could not be booked on the ledger right away, the sweeper books them later
+CREATE TABLE IF NOT EXISTS queued_budget_debits (
+    id          BIGSERIAL PRIMARY KEY,
+    campaign_id UUID           NOT NULL REFERENCES campaigns (id) ON DELETE CASCADE,
+    order_id    BIGINT         NOT NULL,
+    entry_type  TEXT           NOT NULL CHECK (entry_type IN ('shipping')),
+    amount      NUMERIC(14, 2) NOT NULL,
+    created_at  TIMESTAMPTZ    NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,4 @@
+ALTER TABLE order_reward_item
+    DROP COLUMN IF EXISTS shipped_at,
+    DROP COLUMN IF EXISTS shipment_reference,
+    DROP COLUMN IF EXISTS inventory_item_ids;
@@ -0,0 +1,5 @@
+-- a product reward keeps the inventory items blocked for it and its shipment, expiring it only releases items which never shipped
+ALTER TABLE order_reward_item
+    ADD COLUMN IF NOT EXISTS inventory_item_ids BIGINT[]    NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS shipment_reference TEXT        NULL,
+    ADD COLUMN IF NOT EXISTS shipped_at         TIMESTAMPTZ NULL;
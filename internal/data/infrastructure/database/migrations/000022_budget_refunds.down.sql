@@ -0,0 +1,5 @@
+DELETE FROM campaign_budget_ledger WHERE entry_type = 'refund';
+
+ALTER TABLE campaign_budget_ledger
+    DROP CONSTRAINT IF EXISTS campaign_budget_ledger_entry_type_check,
+    ADD CONSTRAINT campaign_budget_ledger_entry_type_check CHECK (entry_type IN ('reward', 'shipping', 'reversal'));
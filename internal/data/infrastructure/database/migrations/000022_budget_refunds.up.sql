@@ -0,0 +1,4 @@
+-- a gift choice of which nothing could ship credits back the cost of its products, the rest of the allocation stays booked
+ALTER TABLE campaign_budget_ledger
+    DROP CONSTRAINT IF EXISTS campaign_budget_ledger_entry_type_check,
+    ADD CONSTRAINT campaign_budget_ledger_entry_type_check CHECK (entry_type IN ('reward', 'shipping', 'reversal', 'refund'));
@@ -0,0 +1,254 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/craftizmv/rewards/internal/data/infrastructure/database/migrations"
+	"github.com/craftizmv/rewards/pkg/logger"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// migrationLockID serialises concurrent migration runs from several replicas
+const migrationLockID = 7_462_011_893
+
+var (
+	ErrSchemaOutdated = errors.New("database schema is out of date, run the migrate command")
+	ErrSchemaTooNew   = errors.New("database schema is newer than this binary")
+)
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration is a single versioned schema change
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Migrator applies the embedded migrations and keeps track of them in the schema_migrations table
+type Migrator struct {
+	db         *sql.DB
+	log        logger.ILogger
+	migrations []*Migration
+}
+
+// NewMigrator creates a Migrator for the migrations embedded in the binary
+func NewMigrator(db *sql.DB, log logger.ILogger) (*Migrator, error) {
+	loaded, err := loadMigrations(migrations.FS)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		log:        log,
+		migrations: loaded,
+	}, nil
+}
+
+// loadMigrations reads and pairs the up/down files sorted by version
+func loadMigrations(fsys fs.FS) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %v", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		matches := migrationFileName.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %v", entry.Name(), err)
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %v", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = migration
+		} else if migration.Name != matches[2] {
+			return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, migration.Name, matches[2])
+		}
+
+		if matches[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	loaded := make([]*Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both up and down files", migration.Version, migration.Name)
+		}
+		loaded = append(loaded, migration)
+	}
+
+	sort.Slice(loaded, func(i, j int) bool {
+		return loaded[i].Version < loaded[j].Version
+	})
+
+	return loaded, nil
+}
+
+// LatestVersion returns the version the binary expects the schema to be at
+func (m *Migrator) LatestVersion() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// CurrentVersion returns the highest applied migration, 0 if none has been applied yet
+func (m *Migrator) CurrentVersion(ctx context.Context) (int64, error) {
+	if err := m.ensureVersionTable(ctx); err != nil {
+		return 0, err
+	}
+
+	var version sql.NullInt64
+	err := m.db.QueryRowContext(ctx, `SELECT MAX(version) FROM schema_migrations`).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %v", err)
+	}
+
+	return version.Int64, nil
+}
+
+// EnsureUpToDate fails if the schema does not match the migrations shipped with the binary
+func (m *Migrator) EnsureUpToDate(ctx context.Context) error {
+	current, err := m.CurrentVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	latest := m.LatestVersion()
+	if current < latest {
+		return fmt.Errorf("%w: current version %d, expected %d", ErrSchemaOutdated, current, latest)
+	}
+	if current > latest {
+		return fmt.Errorf("%w: current version %d, expected %d", ErrSchemaTooNew, current, latest)
+	}
+
+	return nil
+}
+
+// Up applies all pending migrations and returns the number applied
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	current, err := m.CurrentVersion(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, migration := range m.migrations {
+		if migration.Version <= current {
+			continue
+		}
+
+		skipped := false
+		err := m.runInTx(ctx, func(tx *sql.Tx) error {
+			// another replica may have applied it while we were waiting for the lock
+			err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)`, migration.Version).Scan(&skipped)
+			if err != nil || skipped {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+				return err
+			}
+			_, err = tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, migration.Version, migration.Name)
+			return err
+		})
+		if err != nil {
+			return applied, fmt.Errorf("failed to apply migration %d_%s: %v", migration.Version, migration.Name, err)
+		}
+		if skipped {
+			continue
+		}
+
+		m.log.Infof("applied migration %d_%s", migration.Version, migration.Name)
+		applied++
+	}
+
+	return applied, nil
+}
+
+// Down reverts the last steps applied migrations and returns the number reverted
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	current, err := m.CurrentVersion(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	reverted := 0
+	for i := len(m.migrations) - 1; i >= 0 && reverted < steps; i-- {
+		migration := m.migrations[i]
+		if migration.Version > current {
+			continue
+		}
+
+		err := m.runInTx(ctx, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+			return err
+		})
+		if err != nil {
+			return reverted, fmt.Errorf("failed to revert migration %d_%s: %v", migration.Version, migration.Name, err)
+		}
+
+		m.log.Infof("reverted migration %d_%s", migration.Version, migration.Name)
+		reverted++
+	}
+
+	return reverted, nil
+}
+
+func (m *Migrator) ensureVersionTable(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    BIGINT PRIMARY KEY,
+			name       TEXT        NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %v", err)
+	}
+	return nil
+}
+
+// runInTx runs fn in a transaction holding the migration lock, so that only one replica migrates at a time
+func (m *Migrator) runInTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %v", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationLockID); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to take migration lock: %v", err)
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,17 @@
+package mocks
+
+import . "github.com/craftizmv/rewards/internal/data/dtos"
+
+// GetMockSegmentsByUserID returns the mock segments of a user, unknown users are new customers
+func GetMockSegmentsByUserID(userID string) []SegmentID {
+	switch userID {
+	case "abc123":
+		return []SegmentID{SegmentLoyaltyGold, SegmentVIP}
+	case "def456":
+		return []SegmentID{SegmentLoyaltySilver}
+	case "ghi789":
+		return []SegmentID{SegmentDormant}
+	default:
+		return []SegmentID{SegmentNewCustomer}
+	}
+}
@@ -1,14 +1,22 @@
 package proxies
 
 import (
+	"github.com/craftizmv/rewards/internal/app/repository"
 	. "github.com/craftizmv/rewards/internal/data/dtos"
 	"github.com/craftizmv/rewards/internal/data/infrastructure/external/mocks"
+	uuid "github.com/satori/go.uuid"
+	"time"
 )
 
-type CampaignProxy struct{}
+// CampaignProxy serves the campaigns managed through the campaign admin API
+type CampaignProxy struct {
+	campaignRepo repository.CampaignRepository
+}
 
-func NewCampaignProxy() *CampaignProxy {
-	return &CampaignProxy{}
+func NewCampaignProxy(campaignRepo repository.CampaignRepository) *CampaignProxy {
+	return &CampaignProxy{
+		campaignRepo: campaignRepo,
+	}
 }
 
 // ReturnMockCampaigns - returns list of mock campaigns
@@ -16,6 +24,12 @@ func (p *CampaignProxy) ReturnMockCampaigns() []*CampaignDTO {
 	return mocks.MockCampaigns()
 }
 
-func (p *CampaignProxy) FetchMostEligibleCampaign() *CampaignDTO {
-	return mocks.MockValidCampaign()
+// FetchCampaign returns the campaign with the given id
+func (p *CampaignProxy) FetchCampaign(id uuid.UUID) (*CampaignDTO, error) {
+	return p.campaignRepo.GetCampaignByID(id)
+}
+
+// FetchActiveCampaigns returns every campaign running right now, selecting among them is up to the caller
+func (p *CampaignProxy) FetchActiveCampaigns() ([]*CampaignDTO, error) {
+	return p.campaignRepo.GetActiveCampaigns(time.Now())
 }
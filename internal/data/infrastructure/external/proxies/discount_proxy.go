@@ -0,0 +1,48 @@
+package proxies
+
+import (
+	"fmt"
+	. "github.com/craftizmv/rewards/internal/app/contracts"
+	"github.com/craftizmv/rewards/internal/data/dtos"
+	"github.com/craftizmv/rewards/pkg/logger"
+	"time"
+)
+
+// DiscountProxy is a proxy that adds retries before delegating to the actual discount issuer
+type DiscountProxy struct {
+	issuer DiscountIssuer
+	logger logger.ILogger
+}
+
+// NewDiscountProxy creates a new instance of the DiscountProxy with the injected discount issuer
+func NewDiscountProxy(issuer DiscountIssuer, logger logger.ILogger) *DiscountProxy {
+	return &DiscountProxy{
+		issuer: issuer,
+		logger: logger,
+	}
+}
+
+// IssueDiscount creates the coupon or the wallet credit, retrying is safe as the issuer deduplicates on the idempotency key
+func (p *DiscountProxy) IssueDiscount(request *dtos.DiscountIssueRequest) (*dtos.DiscountIssueResponse, error) {
+	var lastErr error
+	for i := 0; i < 3; i++ {
+		response, err := p.issuer.IssueDiscount(request)
+		if err == nil {
+			return response, nil
+		}
+		lastErr = err
+		p.logger.Error("failed to issue discount, retrying", "attempt", i+1, "idempotencyKey", request.IdempotencyKey, "error", err)
+		time.Sleep(1 * time.Second)
+	}
+
+	return nil, fmt.Errorf("failed to issue discount %s after 3 attempts: %w", request.IdempotencyKey, lastErr)
+}
+
+// RevokeDiscount withdraws an issued discount
+func (p *DiscountProxy) RevokeDiscount(reference string) error {
+	if err := p.issuer.RevokeDiscount(reference); err != nil {
+		p.logger.Error("failed to revoke discount", "reference", reference, "error", err)
+		return err
+	}
+	return nil
+}
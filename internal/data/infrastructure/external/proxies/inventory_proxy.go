@@ -16,8 +16,15 @@ func (p *InventoryProxy) ReturnMockInventoryData(productID int64) *InventoryDTO
 	return mocks.GetMockInventoryByProductID(productID)
 }
 
-func (p *InventoryProxy) BulkVerifyInventoryAvailability(itemIDs []int64) (bool, []int64) {
-	return true, []int64{}
+// BulkVerifyInventoryAvailability tells whether all the items are in stock. It fails when the inventory could not be
+// asked, which is not the same as the items being out of stock.
+func (p *InventoryProxy) BulkVerifyInventoryAvailability(itemIDs []int64) (bool, []int64, error) {
+	return true, []int64{}, nil
+}
+
+// ReleaseInventoryItems puts blocked items back in stock, e.g. when the reward they were blocked for expired unclaimed
+func (p *InventoryProxy) ReleaseInventoryItems(itemIDs []int64) bool {
+	return true
 }
 
 func (p *InventoryProxy) BlockInventoryForProducts(productIDs []int64) (bool, []int64) {
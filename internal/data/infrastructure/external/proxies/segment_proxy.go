@@ -0,0 +1,129 @@
+package proxies
+
+import (
+	"container/list"
+	. "github.com/craftizmv/rewards/internal/app/contracts"
+	"github.com/craftizmv/rewards/internal/data/dtos"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSegmentCacheTTL        = 5 * time.Minute
+	defaultSegmentCacheMaxEntries = 10000
+)
+
+// SegmentConfig holds the settings of the segment proxy
+type SegmentConfig struct {
+	CacheTTL        time.Duration `mapstructure:"cacheTTL"`        // How long a membership lookup is reused
+	CacheMaxEntries int           `mapstructure:"cacheMaxEntries"` // Users kept in the cache, the least recently used are evicted
+}
+
+type segmentCacheEntry struct {
+	userID    string
+	segments  []dtos.SegmentID
+	expiresAt time.Time
+}
+
+// SegmentProxy looks up segment memberships and caches them, so that evaluating
+// several campaigns for the same order costs a single call to the segment service.
+// The cache holds at most maxEntries users, expired and least recently used lookups are evicted.
+type SegmentProxy struct {
+	segmentService SegmentService
+	ttl            time.Duration
+	maxEntries     int
+
+	mu      sync.Mutex
+	cache   map[string]*list.Element
+	recency *list.List // most recently used first
+}
+
+// NewSegmentProxy creates a new instance of the SegmentProxy with the injected segment service
+func NewSegmentProxy(segmentService SegmentService, cfg *SegmentConfig) *SegmentProxy {
+	ttl := defaultSegmentCacheTTL
+	maxEntries := defaultSegmentCacheMaxEntries
+	if cfg != nil && cfg.CacheTTL > 0 {
+		ttl = cfg.CacheTTL
+	}
+	if cfg != nil && cfg.CacheMaxEntries > 0 {
+		maxEntries = cfg.CacheMaxEntries
+	}
+	return &SegmentProxy{
+		segmentService: segmentService,
+		ttl:            ttl,
+		maxEntries:     maxEntries,
+		cache:          make(map[string]*list.Element),
+		recency:        list.New(),
+	}
+}
+
+// GetUserSegments returns the segments of the user, from the cache while the lookup is fresh
+func (p *SegmentProxy) GetUserSegments(userID string) ([]dtos.SegmentID, error) {
+	now := time.Now()
+	if segments, ok := p.cached(userID, now); ok {
+		return segments, nil
+	}
+
+	segments, err := p.segmentService.GetUserSegments(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	p.store(userID, segments, now.Add(p.ttl))
+	return segments, nil
+}
+
+// cached returns the fresh lookup of the user, an expired one is evicted
+func (p *SegmentProxy) cached(userID string, now time.Time) ([]dtos.SegmentID, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	element, ok := p.cache[userID]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*segmentCacheEntry)
+	if !now.Before(entry.expiresAt) {
+		p.recency.Remove(element)
+		delete(p.cache, userID)
+		return nil, false
+	}
+	p.recency.MoveToFront(element)
+	return entry.segments, true
+}
+
+// store caches the lookup of the user and evicts the least recently used users above the size limit
+func (p *SegmentProxy) store(userID string, segments []dtos.SegmentID, expiresAt time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if element, ok := p.cache[userID]; ok {
+		entry := element.Value.(*segmentCacheEntry)
+		entry.segments, entry.expiresAt = segments, expiresAt
+		p.recency.MoveToFront(element)
+		return
+	}
+
+	p.cache[userID] = p.recency.PushFront(&segmentCacheEntry{userID: userID, segments: segments, expiresAt: expiresAt})
+	for p.recency.Len() > p.maxEntries {
+		oldest := p.recency.Back()
+		p.recency.Remove(oldest)
+		delete(p.cache, oldest.Value.(*segmentCacheEntry).userID)
+	}
+}
+
+// IsInAnySegment reports whether the user belongs to at least one of the segments
+func (p *SegmentProxy) IsInAnySegment(userID string, segmentIDs []dtos.SegmentID) (bool, error) {
+	segments, err := p.GetUserSegments(userID)
+	if err != nil {
+		return false, err
+	}
+	for _, segment := range segments {
+		for _, segmentID := range segmentIDs {
+			if segment == segmentID {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -64,6 +64,26 @@ func (p *ShippingProxy) ShipItems(itemIDs []int64, shipmentDetail *dtos.UserDeta
 	return nil, fmt.Errorf("failed to ship after 3 attempts")
 }
 
+// QuoteShipment asks the shipper what shipping the products would cost
+func (p *ShippingProxy) QuoteShipment(productIDs []int64, shipmentDetail *dtos.UserDetail) (*dtos.ShipmentResponse, error) {
+	quote, err := p.shipper.QuoteShipment(productIDs, shipmentDetail)
+	if err != nil {
+		fmt.Printf("Failed to get a shipping quote for products %v: %v\n", productIDs, err)
+		return nil, err
+	}
+	return quote, nil
+}
+
+// IsShippable checks the carrier coverage for the location before anything gets shipped
+func (p *ShippingProxy) IsShippable(location *dtos.UserLocation) (bool, error) {
+	covered, err := p.shipper.CheckCoverage(location)
+	if err != nil {
+		fmt.Printf("Failed to check coverage for %s, %s: %v\n", location.PostalCode, location.Country, err)
+		return false, err
+	}
+	return covered, nil
+}
+
 // GetShipmentStatus adds logging and then delegates the actual status check to the shipper
 func (p *ShippingProxy) GetShipmentStatus(shipmentID string) (string, error) {
 	fmt.Printf("Querying shipment status for Tracking ID: %s...\n", shipmentID)
@@ -16,6 +16,7 @@ func (u UserProxy) GetUserDetails(userID string) *dtos.UserDetail {
 		UserID:   "abc123",
 		UserName: "MV",
 		Email:    "abc@gmail.com",
+		Tier:     "gold",
 		Location: dtos.UserLocation{
 			StreetAddress: "add1",
 			AddressLine2:  &addr2,
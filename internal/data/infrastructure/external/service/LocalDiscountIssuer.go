@@ -0,0 +1,62 @@
+package service
+
+import (
+	"fmt"
+	"github.com/craftizmv/rewards/internal/data/dtos"
+	uuid "github.com/satori/go.uuid"
+	"strings"
+	"sync"
+)
+
+// LocalDiscountIssuer is a local stand-in for the coupon and wallet services, it keeps the issued discounts in memory
+type LocalDiscountIssuer struct {
+	mu     sync.Mutex
+	issued map[string]*dtos.DiscountIssueResponse
+}
+
+func NewLocalDiscountIssuer() *LocalDiscountIssuer {
+	return &LocalDiscountIssuer{
+		issued: make(map[string]*dtos.DiscountIssueResponse),
+	}
+}
+
+func (s *LocalDiscountIssuer) IssueDiscount(request *dtos.DiscountIssueRequest) (*dtos.DiscountIssueResponse, error) {
+	if request.Amount <= 0 {
+		return nil, fmt.Errorf("discount amount must be greater than 0, got %.2f", request.Amount)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if response, ok := s.issued[request.IdempotencyKey]; ok {
+		return response, nil
+	}
+
+	id := uuid.NewV4().String()
+	response := &dtos.DiscountIssueResponse{Kind: request.Kind}
+	switch request.Kind {
+	case dtos.DiscountKindCoupon:
+		response.Reference = "coupon-" + id
+		response.Code = strings.ToUpper(strings.ReplaceAll(id, "-", "")[:12])
+	case dtos.DiscountKindStoreCredit:
+		response.Reference = "credit-" + id
+	default:
+		return nil, fmt.Errorf("unknown discount kind: %s", request.Kind)
+	}
+
+	s.issued[request.IdempotencyKey] = response
+	return response, nil
+}
+
+func (s *LocalDiscountIssuer) RevokeDiscount(reference string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, response := range s.issued {
+		if response.Reference == reference {
+			delete(s.issued, key)
+			return nil
+		}
+	}
+	return fmt.Errorf("discount %s not found", reference)
+}
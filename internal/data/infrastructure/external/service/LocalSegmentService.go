@@ -0,0 +1,19 @@
+package service
+
+import (
+	"github.com/craftizmv/rewards/internal/data/dtos"
+	"github.com/craftizmv/rewards/internal/data/infrastructure/external/mocks"
+)
+
+// LocalSegmentService is a local stand-in for the segment service, backed by mock memberships
+type LocalSegmentService struct {
+}
+
+func NewLocalSegmentService() *LocalSegmentService {
+	return &LocalSegmentService{}
+}
+
+func (s *LocalSegmentService) GetUserSegments(userID string) ([]dtos.SegmentID, error) {
+	// Simulate API call to the segment service
+	return mocks.GetMockSegmentsByUserID(userID), nil
+}
@@ -22,6 +22,16 @@ func (l *LogiDeli) ShipItems(itemID []int64, detail *dtos.UserDetail) (*dtos.Shi
 	return &dtos.ShipmentResponse{}, nil
 }
 
+func (l *LogiDeli) QuoteShipment(productIDs []int64, detail *dtos.UserDetail) (*dtos.ShipmentResponse, error) {
+	// Simulate a quote from LogiDeli
+	return &dtos.ShipmentResponse{IsShippingPossible: true}, nil
+}
+
+func (l *LogiDeli) CheckCoverage(location *dtos.UserLocation) (bool, error) {
+	// Simulate a lookup in the LogiDeli coverage data
+	return location.Country != "" && location.PostalCode != "", nil
+}
+
 func (l *LogiDeli) GetShipmentStatus(shipmentID string) (string, error) {
 	// Simulate getting shipment status from LogiDeli
 	return "In Transit", nil
@@ -0,0 +1,119 @@
+package lock
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/craftizmv/rewards/pkg/logger"
+	"time"
+)
+
+var (
+	ErrLockNotAcquired = errors.New("lock is held by another owner")
+	ErrLockLost        = errors.New("lock ownership was lost")
+)
+
+// ILocker hands out distributed locks keyed by a resource name
+type ILocker interface {
+	Acquire(ctx context.Context, key string) (Lock, error)
+}
+
+// Lock is a held distributed lock.
+// Token is a fencing token which increases monotonically for every successful acquisition of the same key.
+// Writes done under the lock carry it, so that the store rejects the writes of an owner whose lease expired.
+type Lock interface {
+	Key() string
+	Token() int64
+	Held() bool
+	Release(ctx context.Context) error
+}
+
+// Backends a lock can be taken from
+const (
+	BackendRedis    = "redis"
+	BackendPostgres = "postgres"
+)
+
+// Config struct for the distributed lock configuration
+type Config struct {
+	Backend       string        `mapstructure:"backend"` // single authority handing out the locks, redis by default
+	Addr          string        `mapstructure:"addr"`
+	Password      string        `mapstructure:"password"`
+	DB            int           `mapstructure:"db"`
+	TTL           time.Duration `mapstructure:"ttl"`           // lease time of a lock before it must be renewed
+	RenewInterval time.Duration `mapstructure:"renewInterval"` // how often the owner extends the lease
+	RetryInterval time.Duration `mapstructure:"retryInterval"` // wait between two acquire attempts
+	WaitTimeout   time.Duration `mapstructure:"waitTimeout"`   // max time spent trying to acquire a lock
+}
+
+const (
+	defaultTTL           = 30 * time.Second
+	defaultRetryInterval = 100 * time.Millisecond
+	defaultWaitTimeout   = 10 * time.Second
+)
+
+// NewLocker returns the locker of the configured backend. All replicas must take their locks from the same
+// backend: the locks and fencing tokens of two backends know nothing of each other, so when the backend is
+// unreachable the acquisition fails rather than falling back to another one. The tokens of the backends are not
+// comparable either, the recorded fences have to be cleared when the backend is switched.
+func NewLocker(config *Config, db *sql.DB, logger logger.ILogger) (ILocker, error) {
+	backend := BackendRedis
+	if config != nil && config.Backend != "" {
+		backend = config.Backend
+	}
+
+	switch backend {
+	case BackendRedis:
+		return NewRedisLocker(config, logger), nil
+	case BackendPostgres:
+		return NewPostgresLocker(db, config, logger), nil
+	default:
+		return nil, fmt.Errorf("unknown lock backend %q", backend)
+	}
+}
+
+// withDefaults returns a copy of the config with the missing values filled in
+func (c *Config) withDefaults() Config {
+	cfg := Config{}
+	if c != nil {
+		cfg = *c
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultTTL
+	}
+	if cfg.RenewInterval <= 0 || cfg.RenewInterval >= cfg.TTL {
+		cfg.RenewInterval = cfg.TTL / 3
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = defaultRetryInterval
+	}
+	if cfg.WaitTimeout <= 0 {
+		cfg.WaitTimeout = defaultWaitTimeout
+	}
+	return cfg
+}
+
+// retryUntil calls tryAcquire until it succeeds, fails, or the wait timeout elapses
+func retryUntil(ctx context.Context, cfg Config, tryAcquire func() (bool, error)) error {
+	deadline := time.Now().Add(cfg.WaitTimeout)
+	for {
+		ok, err := tryAcquire()
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return ErrLockNotAcquired
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(cfg.RetryInterval):
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package lock
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/craftizmv/rewards/pkg/logger"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// PostgresLocker implements ILocker with session level postgres advisory locks.
+// Every held lock pins one pooled connection.
+type PostgresLocker struct {
+	db  *sql.DB
+	cfg Config
+	log logger.ILogger
+}
+
+// NewPostgresLocker creates a new instance of PostgresLocker
+func NewPostgresLocker(db *sql.DB, config *Config, logger logger.ILogger) *PostgresLocker {
+	return &PostgresLocker{
+		db:  db,
+		cfg: config.withDefaults(),
+		log: logger,
+	}
+}
+
+// Acquire blocks until the advisory lock for key is taken or the wait timeout elapses
+func (l *PostgresLocker) Acquire(ctx context.Context, key string) (Lock, error) {
+	// advisory locks belong to the session, so the same connection must be used until release
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection for advisory lock: %v", err)
+	}
+
+	lockID := advisoryLockID(key)
+	err = retryUntil(ctx, l.cfg, func() (bool, error) {
+		var ok bool
+		err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, lockID).Scan(&ok)
+		return ok, err
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// transaction ids only move forward, which is all a fencing token needs
+	var token int64
+	if err := conn.QueryRowContext(ctx, `SELECT txid_current()`).Scan(&token); err != nil {
+		_, _ = conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, lockID)
+		conn.Close()
+		return nil, fmt.Errorf("failed to generate fencing token: %v", err)
+	}
+
+	pgLock := &postgresLock{
+		locker: l,
+		conn:   conn,
+		key:    key,
+		lockID: lockID,
+		token:  token,
+		stop:   make(chan struct{}),
+	}
+	pgLock.held.Store(true)
+	go pgLock.keepAlive()
+
+	return pgLock, nil
+}
+
+// advisoryLockID maps the lock key to the bigint key space of advisory locks
+func advisoryLockID(key string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return int64(h.Sum64())
+}
+
+type postgresLock struct {
+	locker   *PostgresLocker
+	conn     *sql.Conn
+	key      string
+	lockID   int64
+	token    int64
+	held     atomic.Bool
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func (l *postgresLock) Key() string {
+	return l.key
+}
+
+func (l *postgresLock) Token() int64 {
+	return l.token
+}
+
+func (l *postgresLock) Held() bool {
+	return l.held.Load()
+}
+
+// keepAlive pings the session holding the lock, postgres drops the lock as soon as the session dies
+func (l *postgresLock) keepAlive() {
+	ticker := time.NewTicker(l.locker.cfg.RenewInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), l.locker.cfg.RenewInterval)
+			err := l.conn.PingContext(ctx)
+			cancel()
+			if err != nil {
+				l.locker.log.Errorf("lost advisory lock %s with token %d: %v", l.key, l.token, err)
+				l.held.Store(false)
+				return
+			}
+		}
+	}
+}
+
+// Release unlocks the advisory lock and returns the connection to the pool
+func (l *postgresLock) Release(ctx context.Context) error {
+	l.stopOnce.Do(func() { close(l.stop) })
+	defer l.conn.Close()
+
+	if !l.held.Swap(false) {
+		return ErrLockLost
+	}
+
+	var released bool
+	if err := l.conn.QueryRowContext(ctx, `SELECT pg_advisory_unlock($1)`, l.lockID).Scan(&released); err != nil {
+		return err
+	}
+	if !released {
+		return ErrLockLost
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package lock
+
+import (
+	"context"
+	"github.com/craftizmv/rewards/pkg/logger"
+	"github.com/redis/go-redis/v9"
+	uuid "github.com/satori/go.uuid"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// acquireScript sets the lock only if it is free and bumps the fencing counter of the key in the same step.
+var acquireScript = redis.NewScript(`
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return redis.call("INCR", KEYS[2])
+end
+return 0
+`)
+
+// renewScript extends the lease only if the caller still owns the lock.
+var renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseScript deletes the lock only if the caller still owns the lock.
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// RedisLocker implements ILocker on top of a single redis instance
+type RedisLocker struct {
+	client *redis.Client
+	cfg    Config
+	log    logger.ILogger
+}
+
+// NewRedisLocker returns a new RedisLocker with provided config
+func NewRedisLocker(config *Config, logger logger.ILogger) *RedisLocker {
+	cfg := config.withDefaults()
+	return &RedisLocker{
+		client: redis.NewClient(&redis.Options{
+			Addr:     cfg.Addr,
+			Password: cfg.Password,
+			DB:       cfg.DB,
+		}),
+		cfg: cfg,
+		log: logger,
+	}
+}
+
+// Acquire blocks until the lock for key is taken or the wait timeout elapses.
+// The lease is renewed in the background until the lock is released.
+func (l *RedisLocker) Acquire(ctx context.Context, key string) (Lock, error) {
+	owner := uuid.NewV4().String()
+
+	var token int64
+	err := retryUntil(ctx, l.cfg, func() (bool, error) {
+		res, err := acquireScript.Run(ctx, l.client, []string{key, fencingKey(key)}, owner, l.cfg.TTL.Milliseconds()).Int64()
+		if err != nil {
+			return false, err
+		}
+		token = res
+		return token > 0, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	redisLock := &redisLock{
+		locker: l,
+		key:    key,
+		owner:  owner,
+		token:  token,
+		stop:   make(chan struct{}),
+	}
+	redisLock.held.Store(true)
+	go redisLock.keepAlive()
+
+	return redisLock, nil
+}
+
+func fencingKey(key string) string {
+	return key + ":fencing"
+}
+
+type redisLock struct {
+	locker   *RedisLocker
+	key      string
+	owner    string
+	token    int64
+	held     atomic.Bool
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func (l *redisLock) Key() string {
+	return l.key
+}
+
+func (l *redisLock) Token() int64 {
+	return l.token
+}
+
+func (l *redisLock) Held() bool {
+	return l.held.Load()
+}
+
+// keepAlive renews the lease until the lock is released or the ownership is lost.
+// A renewal which fails on a transient error is retried, the lock is only given up once another owner could
+// have taken it: when redis says the lock is gone, or when the lease may run out before the next attempt.
+func (l *redisLock) keepAlive() {
+	cfg := l.locker.cfg
+	timer := time.NewTimer(cfg.RenewInterval)
+	defer timer.Stop()
+
+	lastRenewed := time.Now()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-timer.C:
+			ctx, cancel := context.WithTimeout(context.Background(), cfg.RenewInterval)
+			renewed, err := renewScript.Run(ctx, l.locker.client, []string{l.key}, l.owner, cfg.TTL.Milliseconds()).Int64()
+			cancel()
+
+			switch {
+			case err == nil && renewed == 0:
+				l.locker.log.Errorf("lost lock %s with token %d: owned by another owner", l.key, l.token)
+				l.held.Store(false)
+				return
+			case err == nil:
+				lastRenewed = time.Now()
+				timer.Reset(cfg.RenewInterval)
+			case time.Since(lastRenewed)+cfg.RetryInterval+cfg.RenewInterval >= cfg.TTL:
+				l.locker.log.Errorf("lost lock %s with token %d, lease ran out while retrying: %v", l.key, l.token, err)
+				l.held.Store(false)
+				return
+			default:
+				l.locker.log.Errorf("failed to renew lock %s with token %d, retrying: %v", l.key, l.token, err)
+				timer.Reset(cfg.RetryInterval)
+			}
+		}
+	}
+}
+
+// Release stops the renewal and frees the lock if it is still owned
+func (l *redisLock) Release(ctx context.Context) error {
+	l.stopOnce.Do(func() { close(l.stop) })
+
+	if !l.held.Swap(false) {
+		return ErrLockLost
+	}
+
+	released, err := releaseScript.Run(ctx, l.locker.client, []string{l.key}, l.owner).Int64()
+	if err != nil {
+		return err
+	}
+	if released == 0 {
+		return ErrLockLost
+	}
+	return nil
+}
@@ -1,25 +1,93 @@
 package events
 
+import (
+	"github.com/craftizmv/rewards/internal/data/dtos"
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
 type AllocateReward struct {
-	UserID       string `json:"user_id"`
-	OrderID      int64  `json:"order_id"`
-	CampaignID   int64  `json:"campaign_id"`
-	RewardTypeID int64  `json:"reward_type_id"`
-	OrderStatus  string `json:"order_status"`
-	OrderValue   int    `json:"order_value"`
+	UserID       string    `json:"user_id"`
+	OrderID      int64     `json:"order_id"`
+	CampaignID   uuid.UUID `json:"campaign_id"`
+	RewardTypeID int64     `json:"reward_type_id"`
+	OrderStatus  string    `json:"order_status"`
+	OrderValue   int       `json:"order_value"`
+	PlacedAt     time.Time `json:"placed_at"` // When the order was placed, the time windows of the campaign are checked against it
+	DeviceID     string    `json:"device_id"` // Device the order was placed from, used by fraud screening
+
+	PaymentType   dtos.PaymentType  `json:"payment_type"`   // Instrument the order was paid with
+	PaymentIssuer string            `json:"payment_issuer"` // Bank or wallet provider of the instrument
+	Channel       dtos.SalesChannel `json:"channel"`        // Channel the order was placed through
+
+	AppliedPromotions []dtos.AppliedPromotion `json:"applied_promotions"` // Coupons and discounts applied to the order
+
+	ReferredOrderID int64 `json:"referred_order_id,omitempty"` // Set when the reward pays out a referral, the first order of the referee
+}
+
+// StatusOrderID returns the order whose status decides the allocation, the referee order for referral rewards
+func (e AllocateReward) StatusOrderID() int64 {
+	if e.ReferredOrderID != 0 {
+		return e.ReferredOrderID
+	}
+	return e.OrderID
+}
+
+// PaymentMethod returns the payment instrument of the order, nil when the event carries none
+func (e AllocateReward) PaymentMethod() *dtos.PaymentMethod {
+	if e.PaymentType == "" && e.PaymentIssuer == "" {
+		return nil
+	}
+	return &dtos.PaymentMethod{Type: e.PaymentType, Issuer: e.PaymentIssuer}
 }
 
 type ReAllocateReward struct {
-	UserID        string `json:"user_id"`
-	CampaignID    int64  `json:"campaign_id"`
-	RewardTypeID  int64  `json:"reward_type_id"`
-	RewardGroupID int64  `json:"reward_group_id"`
+	UserID        string    `json:"user_id"`
+	CampaignID    uuid.UUID `json:"campaign_id"`
+	RewardTypeID  int64     `json:"reward_type_id"`
+	RewardGroupID int64     `json:"reward_group_id"`
 }
 
 type RevokeReward struct {
-	UserID       string `json:"user_id"`
-	OrderID      int64  `json:"order_id"`
-	OrderStatus  string `json:"order_status"`
-	CampaignID   int64  `json:"campaign_id"`
-	RewardTypeID int64  `json:"reward_type_id"`
+	UserID       string    `json:"user_id"`
+	OrderID      int64     `json:"order_id"`
+	OrderStatus  string    `json:"order_status"`
+	CampaignID   uuid.UUID `json:"campaign_id"`
+	RewardTypeID int64     `json:"reward_type_id"`
+}
+
+// RedeemReward is published by checkout when an order redeems an allocated reward
+type RedeemReward struct {
+	RewardID   int64     `json:"reward_id"`   // Allocated reward item which is redeemed
+	UserID     string    `json:"user_id"`     // User placing the checkout order
+	OrderID    int64     `json:"order_id"`    // Checkout order the reward is redeemed on
+	Amount     float64   `json:"amount"`      // Part of the value redeemed, the whole balance when 0
+	Reference  string    `json:"reference"`   // Unique reference of the redemption at checkout
+	RedeemedAt time.Time `json:"redeemed_at"` // When checkout applied the reward
+}
+
+// RewardExpired is published when an allocated reward or voucher code expires unused
+type RewardExpired struct {
+	RewardID     *int64    `json:"reward_id,omitempty"`    // Allocated reward item, unset for voucher codes
+	RewardItemID int64     `json:"reward_item_id"`         // Reward item the reward was allocated from
+	VoucherCode  string    `json:"voucher_code,omitempty"` // Code back in its pool, or voided if the pool expired, only for voucher codes
+	UserID       string    `json:"user_id"`
+	OrderID      int64     `json:"order_id"`
+	ExpiredAt    time.Time `json:"expired_at"`
+}
+
+// ReferralConfirmed is published when the first order of a referred user is confirmed
+type ReferralConfirmed struct {
+	CampaignID   uuid.UUID `json:"campaign_id"`   // Referral campaign of the referral code
+	ReferrerID   string    `json:"referrer_id"`   // User who shared the referral code
+	RefereeID    string    `json:"referee_id"`    // Referred user who placed the order
+	ReferralCode string    `json:"referral_code"` // Code the referee signed up with
+	OrderID      int64     `json:"order_id"`
+	OrderValue   int       `json:"order_value"`
+	PlacedAt     time.Time `json:"placed_at"`
+	DeviceID     string    `json:"device_id"`
+
+	PaymentType   dtos.PaymentType  `json:"payment_type"`
+	PaymentIssuer string            `json:"payment_issuer"`
+	Channel       dtos.SalesChannel `json:"channel"`
 }
@@ -0,0 +1,282 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/craftizmv/rewards/internal/app/repository"
+	. "github.com/craftizmv/rewards/internal/domain/entities"
+	uuid "github.com/satori/go.uuid"
+)
+
+// PostgresCampaignBudgetRepository is the concrete implementation of the CampaignBudgetRepository interface for Postgres
+type PostgresCampaignBudgetRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresCampaignBudgetRepository creates a new instance of PostgresCampaignBudgetRepository
+func NewPostgresCampaignBudgetRepository(db *sql.DB) repository.CampaignBudgetRepository {
+	return &PostgresCampaignBudgetRepository{
+		db: db,
+	}
+}
+
+// Debit books a cost against the campaign budget, the campaign row is locked so concurrent debits can't overspend
+func (r *PostgresCampaignBudgetRepository) Debit(entry *BudgetEntry, force bool, fence *repository.Fence) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %v", err)
+	}
+
+	if err := checkFence(tx, fence); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	budget, spent, err := lockCampaignBudget(tx, entry.CampaignID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if !force && budget-spent < entry.Amount {
+		tx.Rollback()
+		return fmt.Errorf("%w: campaign %s has %.2f left, %.2f required", ErrGiftExceedsBudget, entry.CampaignID, budget-spent, entry.Amount)
+	}
+
+	_, err = tx.Exec(`INSERT INTO campaign_budget_ledger (campaign_id, order_id, entry_type, amount) VALUES ($1, $2, $3, $4)`,
+		entry.CampaignID, entry.OrderID, entry.Type, entry.Amount)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to insert budget entry for campaign %s: %v", entry.CampaignID, err)
+	}
+
+	// every allocation books exactly one reward entry
+	if entry.Type == BudgetEntryReward {
+		_, err = tx.Exec(`UPDATE campaigns SET allocated_rewards = allocated_rewards + 1 WHERE id = $1`, entry.CampaignID)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to update allocated rewards of campaign %s: %v", entry.CampaignID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+// Refund books a credit against the campaign budget, the entry carries the negative amount
+func (r *PostgresCampaignBudgetRepository) Refund(entry *BudgetEntry, fence *repository.Fence) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %v", err)
+	}
+
+	if err := checkFence(tx, fence); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, _, err := lockCampaignBudget(tx, entry.CampaignID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO campaign_budget_ledger (campaign_id, order_id, entry_type, amount) VALUES ($1, $2, $3, $4)`,
+		entry.CampaignID, entry.OrderID, BudgetEntryRefund, entry.Amount)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to insert budget refund for order %d: %v", entry.OrderID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+// QueueDebit records the cost for BookQueuedDebits
+func (r *PostgresCampaignBudgetRepository) QueueDebit(entry *BudgetEntry) error {
+	_, err := r.db.Exec(`INSERT INTO queued_budget_debits (campaign_id, order_id, entry_type, amount) VALUES ($1, $2, $3, $4)`,
+		entry.CampaignID, entry.OrderID, entry.Type, entry.Amount)
+	if err != nil {
+		return fmt.Errorf("failed to queue %s debit of order %d: %v", entry.Type, entry.OrderID, err)
+	}
+
+	return nil
+}
+
+// BookQueuedDebits moves queued costs to the ledger oldest first, each one is booked and removed from the queue
+// in the same transaction so it is booked exactly once
+func (r *PostgresCampaignBudgetRepository) BookQueuedDebits(limit int) (int, error) {
+	booked := 0
+	for booked < limit {
+		done, err := r.bookQueuedDebit()
+		if err != nil {
+			return booked, err
+		}
+		if done {
+			return booked, nil
+		}
+		booked++
+	}
+
+	return booked, nil
+}
+
+// bookQueuedDebit books the oldest queued cost, it returns true when the queue is empty
+func (r *PostgresCampaignBudgetRepository) bookQueuedDebit() (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to start transaction: %v", err)
+	}
+
+	var id int64
+	var entry BudgetEntry
+	err = tx.QueryRow(`SELECT id, campaign_id, order_id, entry_type, amount FROM queued_budget_debits ORDER BY id LIMIT 1 FOR UPDATE SKIP LOCKED`).
+		Scan(&id, &entry.CampaignID, &entry.OrderID, &entry.Type, &entry.Amount)
+	if err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return true, nil
+		}
+		return false, fmt.Errorf("failed to fetch queued debit: %v", err)
+	}
+
+	if _, _, err := lockCampaignBudget(tx, entry.CampaignID); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	_, err = tx.Exec(`INSERT INTO campaign_budget_ledger (campaign_id, order_id, entry_type, amount) VALUES ($1, $2, $3, $4)`,
+		entry.CampaignID, entry.OrderID, entry.Type, entry.Amount)
+	if err != nil {
+		tx.Rollback()
+		return false, fmt.Errorf("failed to book queued debit %d: %v", id, err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM queued_budget_debits WHERE id = $1`, id); err != nil {
+		tx.Rollback()
+		return false, fmt.Errorf("failed to remove queued debit %d: %v", id, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return false, nil
+}
+
+// CreditOrder books a reversal of the net amount debited for the order and releases its reward,
+// it is a no-op if nothing is left to credit and no reward is left to release
+func (r *PostgresCampaignBudgetRepository) CreditOrder(campaignID uuid.UUID, orderID int64) (float64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to start transaction: %v", err)
+	}
+
+	if _, _, err := lockCampaignBudget(tx, campaignID); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	rows, err := tx.Query(`SELECT entry_type, amount FROM campaign_budget_ledger WHERE campaign_id = $1 AND order_id = $2 ORDER BY id`,
+		campaignID, orderID)
+	if err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("failed to fetch budget entries of order %d: %v", orderID, err)
+	}
+	var entries []*BudgetEntry
+	for rows.Next() {
+		entry := &BudgetEntry{CampaignID: campaignID, OrderID: orderID}
+		if err := rows.Scan(&entry.Type, &entry.Amount); err != nil {
+			rows.Close()
+			tx.Rollback()
+			return 0, fmt.Errorf("failed to scan budget entry of order %d: %v", orderID, err)
+		}
+		entries = append(entries, entry)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("failed to fetch budget entries of order %d: %v", orderID, err)
+	}
+
+	// the counter follows the reward debit, not the amount, a reward without a cost takes up a reward as well
+	net, released := OrderCredit(entries)
+	if net <= 0 && !released {
+		tx.Rollback()
+		return 0, nil
+	}
+
+	_, err = tx.Exec(`INSERT INTO campaign_budget_ledger (campaign_id, order_id, entry_type, amount) VALUES ($1, $2, $3, $4)`,
+		campaignID, orderID, BudgetEntryReversal, -net)
+	if err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("failed to insert budget reversal for order %d: %v", orderID, err)
+	}
+
+	if released {
+		_, err = tx.Exec(`UPDATE campaigns SET allocated_rewards = GREATEST(allocated_rewards - 1, 0) WHERE id = $1`, campaignID)
+		if err != nil {
+			tx.Rollback()
+			return 0, fmt.Errorf("failed to update allocated rewards of campaign %s: %v", campaignID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return net, nil
+}
+
+// GetRemainingBudget returns the campaign budget minus everything booked on the ledger
+func (r *PostgresCampaignBudgetRepository) GetRemainingBudget(campaignID uuid.UUID) (float64, error) {
+	var remaining float64
+	err := r.db.QueryRow(`
+		SELECT c.budget - COALESCE((SELECT SUM(l.amount) FROM campaign_budget_ledger l WHERE l.campaign_id = c.id), 0)
+		FROM campaigns c
+		WHERE c.id = $1`, campaignID).Scan(&remaining)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("%w: %s", repository.ErrCampaignNotFound, campaignID)
+		}
+		return 0, fmt.Errorf("failed to fetch remaining budget of campaign %s: %v", campaignID, err)
+	}
+
+	return remaining, nil
+}
+
+// GetSpentBudget returns the net amount booked on the ledger of the campaign
+func (r *PostgresCampaignBudgetRepository) GetSpentBudget(campaignID uuid.UUID) (float64, error) {
+	var spent float64
+	err := r.db.QueryRow(`SELECT COALESCE(SUM(amount), 0) FROM campaign_budget_ledger WHERE campaign_id = $1`, campaignID).Scan(&spent)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch spent budget of campaign %s: %v", campaignID, err)
+	}
+
+	return spent, nil
+}
+
+// lockCampaignBudget locks the campaign row for the rest of the transaction and returns its budget and spent amount
+func lockCampaignBudget(tx *sql.Tx, campaignID uuid.UUID) (float64, float64, error) {
+	var budget float64
+	err := tx.QueryRow(`SELECT budget FROM campaigns WHERE id = $1 FOR UPDATE`, campaignID).Scan(&budget)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, 0, fmt.Errorf("%w: %s", repository.ErrCampaignNotFound, campaignID)
+		}
+		return 0, 0, fmt.Errorf("failed to lock campaign %s: %v", campaignID, err)
+	}
+
+	var spent float64
+	err = tx.QueryRow(`SELECT COALESCE(SUM(amount), 0) FROM campaign_budget_ledger WHERE campaign_id = $1`, campaignID).Scan(&spent)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to fetch spent budget of campaign %s: %v", campaignID, err)
+	}
+
+	return budget, spent, nil
+}
@@ -0,0 +1,314 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/craftizmv/rewards/internal/app/repository"
+	. "github.com/craftizmv/rewards/internal/data/dtos"
+	"github.com/craftizmv/rewards/internal/domain/entities"
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+const campaignColumns = `id, reward_group_id, name, start_date, end_date, status, budget,
+	allocated_rewards, total_eligible_rewards, target_audience, eligibility_criteria,
+	priority, reward_value, reward_cost, stackable, stacking_group, timezone`
+
+// PostgresCampaignRepository is the concrete implementation of the CampaignRepository interface for Postgres
+type PostgresCampaignRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresCampaignRepository creates a new instance of PostgresCampaignRepository
+func NewPostgresCampaignRepository(db *sql.DB) repository.CampaignRepository {
+	return &PostgresCampaignRepository{
+		db: db,
+	}
+}
+
+// CreateCampaign inserts a new campaign
+func (r *PostgresCampaignRepository) CreateCampaign(campaign *CampaignDTO) error {
+	criteria, err := json.Marshal(campaign.EligibilityCriteria)
+	if err != nil {
+		return fmt.Errorf("failed to marshal eligibility criteria: %v", err)
+	}
+
+	query := `INSERT INTO campaigns (` + campaignColumns + `)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)`
+
+	_, err = r.db.Exec(query,
+		campaign.ID,
+		campaign.RewardGroupID,
+		campaign.Name,
+		campaign.StartDate,
+		campaign.EndDate,
+		campaign.Status,
+		campaign.Budget,
+		campaign.AllocatedRewards,
+		campaign.TotalEligibleRewards,
+		campaign.TargetAudience,
+		criteria,
+		campaign.Priority,
+		campaign.RewardValue,
+		campaign.RewardCost,
+		campaign.Stackable,
+		campaign.StackingGroup,
+		campaign.Timezone,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert campaign %s: %v", campaign.ID, err)
+	}
+
+	return nil
+}
+
+// UpdateCampaign overwrites the editable fields of a campaign
+func (r *PostgresCampaignRepository) UpdateCampaign(campaign *CampaignDTO) error {
+	criteria, err := json.Marshal(campaign.EligibilityCriteria)
+	if err != nil {
+		return fmt.Errorf("failed to marshal eligibility criteria: %v", err)
+	}
+
+	query := `
+		UPDATE campaigns
+		SET
+			reward_group_id = $2,
+			name = $3,
+			start_date = $4,
+			end_date = $5,
+			budget = $6,
+			total_eligible_rewards = $7,
+			target_audience = $8,
+			eligibility_criteria = $9,
+			priority = $10,
+			reward_value = $11,
+			reward_cost = $12,
+			stackable = $13,
+			stacking_group = $14,
+			timezone = $15,
+			updated_at = NOW()
+		WHERE id = $1
+	`
+
+	result, err := r.db.Exec(query,
+		campaign.ID,
+		campaign.RewardGroupID,
+		campaign.Name,
+		campaign.StartDate,
+		campaign.EndDate,
+		campaign.Budget,
+		campaign.TotalEligibleRewards,
+		campaign.TargetAudience,
+		criteria,
+		campaign.Priority,
+		campaign.RewardValue,
+		campaign.RewardCost,
+		campaign.Stackable,
+		campaign.StackingGroup,
+		campaign.Timezone,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update campaign %s: %v", campaign.ID, err)
+	}
+
+	return checkCampaignAffected(result, campaign.ID)
+}
+
+// TransitionCampaignStatus moves a campaign to a new status and records the change in the audit trail
+func (r *PostgresCampaignRepository) TransitionCampaignStatus(change *entities.CampaignStatusChange) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %v", err)
+	}
+
+	// the status guard makes concurrent transitions of the same campaign fail instead of overwriting each other
+	result, err := tx.Exec(`UPDATE campaigns SET status = $3, updated_at = NOW() WHERE id = $1 AND status = $2`,
+		change.CampaignID, change.From, change.To)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to update status of campaign %s: %v", change.CampaignID, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("error checking rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		tx.Rollback()
+		return fmt.Errorf("%w: campaign %s is no longer %s", repository.ErrCampaignStatusChanged, change.CampaignID, change.From)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO campaign_status_audit (campaign_id, from_status, to_status, actor, reason, changed_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		change.CampaignID, change.From, change.To, change.Actor, change.Reason, change.ChangedAt)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to insert status audit of campaign %s: %v", change.CampaignID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+// ListCampaignStatusChanges retrieves the status audit trail of a campaign, oldest first
+func (r *PostgresCampaignRepository) ListCampaignStatusChanges(id uuid.UUID) ([]*entities.CampaignStatusChange, error) {
+	query := `SELECT campaign_id, from_status, to_status, actor, reason, changed_at
+			  FROM campaign_status_audit
+			  WHERE campaign_id = $1
+			  ORDER BY changed_at, id`
+
+	rows, err := r.db.Query(query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch status audit of campaign %s: %v", id, err)
+	}
+	defer rows.Close()
+
+	var changes []*entities.CampaignStatusChange
+	for rows.Next() {
+		var change entities.CampaignStatusChange
+		if err := rows.Scan(&change.CampaignID, &change.From, &change.To, &change.Actor, &change.Reason, &change.ChangedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan status audit: %v", err)
+		}
+		changes = append(changes, &change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return changes, nil
+}
+
+// GetCampaignByID retrieves a campaign by its ID
+func (r *PostgresCampaignRepository) GetCampaignByID(id uuid.UUID) (*CampaignDTO, error) {
+	query := `SELECT ` + campaignColumns + ` FROM campaigns WHERE id = $1`
+
+	campaign, err := scanCampaign(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: %s", repository.ErrCampaignNotFound, id)
+		}
+		return nil, err
+	}
+
+	return campaign, nil
+}
+
+// ListCampaigns retrieves the campaigns, optionally filtered by status
+func (r *PostgresCampaignRepository) ListCampaigns(status Status) ([]*CampaignDTO, error) {
+	query := `SELECT ` + campaignColumns + ` FROM campaigns
+			  WHERE ($1 = '' OR status = $1)
+			  ORDER BY start_date DESC`
+
+	rows, err := r.db.Query(query, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list campaigns: %v", err)
+	}
+	defer rows.Close()
+
+	return scanCampaigns(rows)
+}
+
+// GetActiveCampaigns retrieves the active campaigns whose date range contains the given time
+func (r *PostgresCampaignRepository) GetActiveCampaigns(at time.Time) ([]*CampaignDTO, error) {
+	query := `SELECT ` + campaignColumns + ` FROM campaigns
+			  WHERE status = $1 AND start_date <= $2 AND end_date >= $2
+			  ORDER BY start_date`
+
+	rows, err := r.db.Query(query, Active, at)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch active campaigns: %v", err)
+	}
+	defer rows.Close()
+
+	return scanCampaigns(rows)
+}
+
+// GetCampaignsDueForTransition retrieves the scheduled campaigns which have to start and the running ones which have to end
+func (r *PostgresCampaignRepository) GetCampaignsDueForTransition(at time.Time) ([]*CampaignDTO, error) {
+	query := `SELECT ` + campaignColumns + ` FROM campaigns
+			  WHERE (status = $1 AND start_date <= $4)
+			     OR (status IN ($2, $3) AND end_date < $4)
+			  ORDER BY start_date`
+
+	rows, err := r.db.Query(query, Scheduled, Active, Paused, at)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch campaigns due for transition: %v", err)
+	}
+	defer rows.Close()
+
+	return scanCampaigns(rows)
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCampaign(row rowScanner) (*CampaignDTO, error) {
+	var campaign CampaignDTO
+	var criteria []byte
+
+	err := row.Scan(
+		&campaign.ID,
+		&campaign.RewardGroupID,
+		&campaign.Name,
+		&campaign.StartDate,
+		&campaign.EndDate,
+		&campaign.Status,
+		&campaign.Budget,
+		&campaign.AllocatedRewards,
+		&campaign.TotalEligibleRewards,
+		&campaign.TargetAudience,
+		&criteria,
+		&campaign.Priority,
+		&campaign.RewardValue,
+		&campaign.RewardCost,
+		&campaign.Stackable,
+		&campaign.StackingGroup,
+		&campaign.Timezone,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(criteria, &campaign.EligibilityCriteria); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal eligibility criteria of campaign %s: %v", campaign.ID, err)
+	}
+
+	return &campaign, nil
+}
+
+func scanCampaigns(rows *sql.Rows) ([]*CampaignDTO, error) {
+	var campaigns []*CampaignDTO
+	for rows.Next() {
+		campaign, err := scanCampaign(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan campaign: %v", err)
+		}
+		campaigns = append(campaigns, campaign)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return campaigns, nil
+}
+
+func checkCampaignAffected(result sql.Result, id uuid.UUID) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: %s", repository.ErrCampaignNotFound, id)
+	}
+	return nil
+}
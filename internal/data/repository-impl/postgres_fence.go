@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/craftizmv/rewards/internal/app/repository"
+)
+
+// checkFence records the token of the fence and fails with ErrStaleFence if a newer one was recorded before.
+// The row stays locked until the transaction ends, so the write of a newer owner waits for it and a stale
+// owner can never write after a newer one.
+func checkFence(tx *sql.Tx, fence *repository.Fence) error {
+	if fence == nil {
+		return nil
+	}
+
+	query := `
+		INSERT INTO lock_fences (lock_key, token, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (lock_key) DO UPDATE
+		SET token = EXCLUDED.token, updated_at = EXCLUDED.updated_at
+		WHERE lock_fences.token <= EXCLUDED.token
+	`
+	result, err := tx.Exec(query, fence.Key, fence.Token)
+	if err != nil {
+		return fmt.Errorf("failed to check fencing token of %s: %v", fence.Key, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %v", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: %s with token %d", repository.ErrStaleFence, fence.Key, fence.Token)
+	}
+	return nil
+}
+
+// execFenced runs a single statement behind the fence, in a transaction of its own
+func execFenced(db *sql.DB, fence *repository.Fence, query string, args ...interface{}) (sql.Result, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+
+	if err := checkFence(tx, fence); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/craftizmv/rewards/internal/app/repository"
+	. "github.com/craftizmv/rewards/internal/domain/entities"
+	"github.com/lib/pq"
+	"time"
+)
+
+// fingerprintColumns maps the kinds allocations are counted by to their column
+var fingerprintColumns = map[FingerprintKind]string{
+	FingerprintUser:    "user_id",
+	FingerprintDevice:  "device_id",
+	FingerprintAddress: "address_hash",
+}
+
+// PostgresFraudRepository is the concrete implementation of the FraudRepository interface for Postgres
+type PostgresFraudRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresFraudRepository creates a new instance of PostgresFraudRepository
+func NewPostgresFraudRepository(db *sql.DB) repository.FraudRepository {
+	return &PostgresFraudRepository{
+		db: db,
+	}
+}
+
+// RecordFingerprint stores the fingerprint of an allocation
+func (r *PostgresFraudRepository) RecordFingerprint(fingerprint *RewardFingerprint) error {
+	query := `
+		INSERT INTO reward_fingerprints (order_id, campaign_id, user_id, device_id, address_hash, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (campaign_id, order_id) DO NOTHING
+	`
+
+	_, err := r.db.Exec(query,
+		fingerprint.OrderID,
+		fingerprint.CampaignID,
+		fingerprint.UserID,
+		fingerprint.DeviceID,
+		fingerprint.AddressHash,
+		fingerprint.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record fingerprint of order %d: %v", fingerprint.OrderID, err)
+	}
+
+	return nil
+}
+
+// CountOrders counts the distinct orders with the given fingerprint value since the given time
+func (r *PostgresFraudRepository) CountOrders(kind FingerprintKind, value string, since time.Time, excludeOrderID int64) (int, error) {
+	column, ok := fingerprintColumns[kind]
+	if !ok {
+		return 0, fmt.Errorf("orders cannot be counted by %s", kind)
+	}
+
+	query := `SELECT COUNT(DISTINCT order_id) FROM reward_fingerprints
+			  WHERE ` + column + ` = $1 AND created_at >= $2 AND order_id <> $3`
+
+	var count int
+	if err := r.db.QueryRow(query, value, since, excludeOrderID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count orders by %s: %v", kind, err)
+	}
+
+	return count, nil
+}
+
+// CountOtherUsersAtAddress counts the other users that got rewards shipped to the address
+func (r *PostgresFraudRepository) CountOtherUsersAtAddress(addressHash string, userID string) (int, error) {
+	query := `SELECT COUNT(DISTINCT user_id) FROM reward_fingerprints WHERE address_hash = $1 AND user_id <> $2`
+
+	var count int
+	if err := r.db.QueryRow(query, addressHash, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count users at address: %v", err)
+	}
+
+	return count, nil
+}
+
+// FindBlocklistEntries returns the blocklist entries matching any of the given values, empty values are skipped
+func (r *PostgresFraudRepository) FindBlocklistEntries(values map[FingerprintKind]string) ([]*BlocklistEntry, error) {
+	var kinds, keys []string
+	for kind, value := range values {
+		if value == "" {
+			continue
+		}
+		kinds = append(kinds, string(kind))
+		keys = append(keys, value)
+	}
+	if len(kinds) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT b.kind, b.value, b.reason, b.created_at
+		FROM fraud_blocklist b
+		JOIN UNNEST($1::text[], $2::text[]) AS k(kind, value) ON b.kind = k.kind AND b.value = k.value
+	`
+
+	rows, err := r.db.Query(query, pq.Array(kinds), pq.Array(keys))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query blocklist: %v", err)
+	}
+	defer rows.Close()
+
+	var entries []*BlocklistEntry
+	for rows.Next() {
+		var entry BlocklistEntry
+		if err := rows.Scan(&entry.Kind, &entry.Value, &entry.Reason, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan blocklist entry: %v", err)
+		}
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate blocklist: %v", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,227 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/craftizmv/rewards/internal/app/repository"
+	. "github.com/craftizmv/rewards/internal/domain/entities"
+	"github.com/lib/pq"
+	"time"
+)
+
+const giftChoiceColumns = `id, order_id, user_id, campaign_id, reward_group_id, options, default_product_id,
+	chosen_product_id, status, deadline, created_at, decided_at`
+
+// PostgresGiftChoiceRepository is the concrete implementation of the GiftChoiceRepository interface for Postgres
+type PostgresGiftChoiceRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresGiftChoiceRepository creates a new instance of PostgresGiftChoiceRepository
+func NewPostgresGiftChoiceRepository(db *sql.DB) repository.GiftChoiceRepository {
+	return &PostgresGiftChoiceRepository{
+		db: db,
+	}
+}
+
+// CreateGiftChoice records a pending choice, or loads the one recorded for the order and reward group before
+func (r *PostgresGiftChoiceRepository) CreateGiftChoice(choice *GiftChoice, fence *repository.Fence) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %v", err)
+	}
+
+	if err := checkFence(tx, fence); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	query := `
+		INSERT INTO gift_choices (order_id, user_id, campaign_id, reward_group_id, options, default_product_id, status, deadline, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (order_id, reward_group_id) DO NOTHING
+		RETURNING id
+	`
+
+	err = tx.QueryRow(query,
+		choice.OrderID,
+		choice.UserID,
+		choice.CampaignID,
+		choice.RewardGroupID,
+		pq.Array(choice.Options),
+		choice.DefaultProductID,
+		GiftChoicePending,
+		choice.Deadline,
+		choice.CreatedAt,
+	).Scan(&choice.ID)
+	switch {
+	case err == nil:
+		choice.Status = GiftChoicePending
+	case err == sql.ErrNoRows:
+		// the reward group was offered for the order before
+		query = `SELECT ` + giftChoiceColumns + ` FROM gift_choices WHERE order_id = $1 AND reward_group_id = $2`
+		existing, err := scanGiftChoice(tx.QueryRow(query, choice.OrderID, choice.RewardGroupID))
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to fetch gift choice of order %d: %v", choice.OrderID, err)
+		}
+		*choice = *existing
+	default:
+		tx.Rollback()
+		return fmt.Errorf("failed to create gift choice of order %d: %v", choice.OrderID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+// GetGiftChoice fetches a gift choice by id
+func (r *PostgresGiftChoiceRepository) GetGiftChoice(id int64) (*GiftChoice, error) {
+	query := `SELECT ` + giftChoiceColumns + ` FROM gift_choices WHERE id = $1`
+
+	choice, err := scanGiftChoice(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %d", repository.ErrGiftChoiceNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch gift choice %d: %v", id, err)
+	}
+
+	return choice, nil
+}
+
+// ListDueGiftChoices returns the pending choices past their deadline, oldest deadline first
+func (r *PostgresGiftChoiceRepository) ListDueGiftChoices(at time.Time, limit int) ([]*GiftChoice, error) {
+	query := `SELECT ` + giftChoiceColumns + ` FROM gift_choices
+			  WHERE status = 'pending' AND deadline <= $1
+			  ORDER BY deadline
+			  LIMIT $2`
+
+	rows, err := r.db.Query(query, at, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due gift choices: %v", err)
+	}
+	defer rows.Close()
+
+	var choices []*GiftChoice
+	for rows.Next() {
+		choice, err := scanGiftChoice(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan gift choice: %v", err)
+		}
+		choices = append(choices, choice)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate gift choices: %v", err)
+	}
+
+	return choices, nil
+}
+
+// ClaimGiftChoice claims a pending choice for shipping
+func (r *PostgresGiftChoiceRepository) ClaimGiftChoice(id int64, fence *repository.Fence) error {
+	result, err := execFenced(r.db, fence, `UPDATE gift_choices SET status = 'settling' WHERE id = $1 AND status = 'pending'`, id)
+	if err != nil {
+		return fmt.Errorf("failed to claim gift choice %d: %w", id, err)
+	}
+
+	return checkAffected(result, ErrGiftChoiceClosed, id)
+}
+
+// ReleaseGiftChoice gives a claimed choice back to the user
+func (r *PostgresGiftChoiceRepository) ReleaseGiftChoice(id int64, fence *repository.Fence) error {
+	result, err := execFenced(r.db, fence, `UPDATE gift_choices SET status = 'pending' WHERE id = $1 AND status = 'settling'`, id)
+	if err != nil {
+		return fmt.Errorf("failed to release gift choice %d: %w", id, err)
+	}
+
+	return checkAffected(result, ErrGiftChoiceClosed, id)
+}
+
+// SettleGiftChoice records the product shipped for a claimed choice
+func (r *PostgresGiftChoiceRepository) SettleGiftChoice(id int64, status GiftChoiceStatus, productID int64, at time.Time, fence *repository.Fence) error {
+	query := `
+		UPDATE gift_choices
+		SET status = $2, chosen_product_id = $3, decided_at = $4
+		WHERE id = $1 AND status = 'settling'
+	`
+
+	result, err := execFenced(r.db, fence, query, id, status, productID, at)
+	if err != nil {
+		return fmt.Errorf("failed to settle gift choice %d: %w", id, err)
+	}
+
+	return checkAffected(result, ErrGiftChoiceClosed, id)
+}
+
+// CancelGiftChoice cancels a single pending choice
+func (r *PostgresGiftChoiceRepository) CancelGiftChoice(id int64, at time.Time, fence *repository.Fence) error {
+	query := `
+		UPDATE gift_choices
+		SET status = 'cancelled', decided_at = $2
+		WHERE id = $1 AND status = 'pending'
+	`
+
+	result, err := execFenced(r.db, fence, query, id, at)
+	if err != nil {
+		return fmt.Errorf("failed to cancel gift choice %d: %w", id, err)
+	}
+
+	return checkAffected(result, ErrGiftChoiceClosed, id)
+}
+
+// CancelGiftChoices cancels the pending choices of the order
+func (r *PostgresGiftChoiceRepository) CancelGiftChoices(orderID int64, at time.Time, fence *repository.Fence) (int, error) {
+	query := `
+		UPDATE gift_choices
+		SET status = 'cancelled', decided_at = $2
+		WHERE order_id = $1 AND status = 'pending'
+	`
+
+	result, err := execFenced(r.db, fence, query, orderID, at)
+	if err != nil {
+		return 0, fmt.Errorf("failed to cancel gift choices of order %d: %w", orderID, err)
+	}
+	cancelled, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error checking rows affected: %v", err)
+	}
+
+	return int(cancelled), nil
+}
+
+func scanGiftChoice(row rowScanner) (*GiftChoice, error) {
+	var choice GiftChoice
+	var chosenProductID sql.NullInt64
+	var decidedAt sql.NullTime
+
+	err := row.Scan(
+		&choice.ID,
+		&choice.OrderID,
+		&choice.UserID,
+		&choice.CampaignID,
+		&choice.RewardGroupID,
+		pq.Array(&choice.Options),
+		&choice.DefaultProductID,
+		&chosenProductID,
+		&choice.Status,
+		&choice.Deadline,
+		&choice.CreatedAt,
+		&decidedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if chosenProductID.Valid {
+		choice.ChosenProductID = &chosenProductID.Int64
+	}
+	if decidedAt.Valid {
+		choice.DecidedAt = &decidedAt.Time
+	}
+
+	return &choice, nil
+}
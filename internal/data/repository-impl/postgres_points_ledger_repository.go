@@ -0,0 +1,369 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/craftizmv/rewards/internal/app/repository"
+	. "github.com/craftizmv/rewards/internal/domain/entities"
+	"github.com/lib/pq"
+	"time"
+)
+
+// remaining is what is left of an accrual once its reversals and expiries are taken out
+const pointsTransactionColumns = `t.id, t.user_id, t.type, t.points, t.reference, t.order_id, t.campaign_id,
+	t.reward_item_id, t.source_id, t.expires_at, t.created_at,
+	CASE WHEN t.type = 'accrue'
+		THEN t.points - COALESCE((SELECT SUM(s.points) FROM points_transactions s WHERE s.source_id = t.id), 0)
+		ELSE 0 END`
+
+const userPointsBalanceQuery = `SELECT COALESCE(SUM(amount), 0) FROM points_ledger_entries WHERE account = $1`
+
+// PostgresPointsLedgerRepository is the concrete implementation of the PointsLedgerRepository interface for Postgres
+type PostgresPointsLedgerRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresPointsLedgerRepository creates a new instance of PostgresPointsLedgerRepository
+func NewPostgresPointsLedgerRepository(db *sql.DB) repository.PointsLedgerRepository {
+	return &PostgresPointsLedgerRepository{
+		db: db,
+	}
+}
+
+// PostTransaction records the transaction, postings of the same user are serialised so the balance check holds
+func (r *PostgresPointsLedgerRepository) PostTransaction(transaction *PointsTransaction, fence *repository.Fence) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %v", err)
+	}
+
+	if err := checkFence(tx, fence); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := lockPointsAccount(tx, transaction.UserID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// retried allocations and redemptions deliver the same transaction again
+	err = tx.QueryRow(`SELECT id, created_at FROM points_transactions WHERE reference = $1`, transaction.Reference).
+		Scan(&transaction.ID, &transaction.CreatedAt)
+	if err == nil {
+		tx.Rollback()
+		return nil
+	}
+	if err != sql.ErrNoRows {
+		tx.Rollback()
+		return fmt.Errorf("failed to look up points transaction %s: %v", transaction.Reference, err)
+	}
+
+	if transaction.Type == PointsRedeem {
+		balance, err := userPointsBalance(tx, transaction.UserID)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := CheckRedeemable(balance, transaction.Points); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := insertPointsTransaction(tx, transaction); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+// ReverseAccrual posts a reversal of what is left of the accrual
+func (r *PostgresPointsLedgerRepository) ReverseAccrual(accrualID int64, at time.Time) (*PointsTransaction, error) {
+	return r.settleAccrual(accrualID, PointsReverse, at)
+}
+
+// ExpireAccrual posts an expiry of what is left of the accrual and marks it as expired
+func (r *PostgresPointsLedgerRepository) ExpireAccrual(accrualID int64, at time.Time) (*PointsTransaction, error) {
+	return r.settleAccrual(accrualID, PointsExpire, at)
+}
+
+// settleAccrual takes what is left of the accrual out of the account of the user, expiries stop at the balance
+func (r *PostgresPointsLedgerRepository) settleAccrual(accrualID int64, txType PointsTransactionType, at time.Time) (*PointsTransaction, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+
+	var userID string
+	err = tx.QueryRow(`SELECT user_id FROM points_transactions WHERE id = $1 AND type = $2`, accrualID, PointsAccrue).Scan(&userID)
+	if err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: %d", repository.ErrPointsAccrualNotFound, accrualID)
+		}
+		return nil, fmt.Errorf("failed to fetch points accrual %d: %v", accrualID, err)
+	}
+
+	if err := lockPointsAccount(tx, userID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	accrual, err := scanPointsTransaction(tx.QueryRow(`SELECT `+pointsTransactionColumns+` FROM points_transactions t WHERE t.id = $1`, accrualID))
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to fetch points accrual %d: %v", accrualID, err)
+	}
+
+	points := accrual.Remaining
+	if txType == PointsExpire {
+		balance, err := userPointsBalance(tx, userID)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if balance < points {
+			points = balance
+		}
+
+		if _, err := tx.Exec(`UPDATE points_transactions SET expired_at = $2 WHERE id = $1`, accrualID, at); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to mark points accrual %d as expired: %v", accrualID, err)
+		}
+	}
+
+	var settled *PointsTransaction
+	if points > 0 {
+		settled, err = NewPointsTransaction(userID, txType, points, PointsSourceReference(txType, accrualID))
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		settled.OrderID = accrual.OrderID
+		settled.CampaignID = accrual.CampaignID
+		settled.RewardItemID = accrual.RewardItemID
+		settled.SourceID = &accrual.ID
+		settled.CreatedAt = at
+
+		if err := insertPointsTransaction(tx, settled); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return settled, nil
+}
+
+// GetBalance sums the account of the user and the transactions per type
+func (r *PostgresPointsLedgerRepository) GetBalance(userID string) (*PointsBalance, error) {
+	balance := PointsBalance{UserID: userID}
+
+	query := `
+		SELECT
+			COALESCE(SUM(points) FILTER (WHERE type = 'accrue'), 0),
+			COALESCE(SUM(points) FILTER (WHERE type = 'reverse'), 0),
+			COALESCE(SUM(points) FILTER (WHERE type = 'expire'), 0),
+			COALESCE(SUM(points) FILTER (WHERE type = 'redeem'), 0)
+		FROM points_transactions
+		WHERE user_id = $1
+	`
+	err := r.db.QueryRow(query, userID).Scan(&balance.Accrued, &balance.Reversed, &balance.Expired, &balance.Redeemed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch points of user %s: %v", userID, err)
+	}
+
+	if balance.Balance, err = userPointsBalance(r.db, userID); err != nil {
+		return nil, err
+	}
+
+	return &balance, nil
+}
+
+// ListTransactions retrieves a page of the transactions of the user with their entries, newest first
+func (r *PostgresPointsLedgerRepository) ListTransactions(userID string, limit, offset int) ([]*PointsTransaction, error) {
+	query := `SELECT ` + pointsTransactionColumns + ` FROM points_transactions t
+			  WHERE t.user_id = $1
+			  ORDER BY t.created_at DESC, t.id DESC
+			  LIMIT $2 OFFSET $3`
+
+	transactions, err := r.queryPointsTransactions(query, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadEntries(transactions); err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// GetOrderAccruals retrieves the accruals of the order, oldest first
+func (r *PostgresPointsLedgerRepository) GetOrderAccruals(orderID int64) ([]*PointsTransaction, error) {
+	query := `SELECT ` + pointsTransactionColumns + ` FROM points_transactions t
+			  WHERE t.order_id = $1 AND t.type = $2
+			  ORDER BY t.id`
+
+	return r.queryPointsTransactions(query, orderID, PointsAccrue)
+}
+
+// ListAccrualsDueForExpiry retrieves the accruals which expire at or before the given time and were not expired yet
+func (r *PostgresPointsLedgerRepository) ListAccrualsDueForExpiry(at time.Time, limit int) ([]*PointsTransaction, error) {
+	query := `SELECT ` + pointsTransactionColumns + ` FROM points_transactions t
+			  WHERE t.type = $1 AND t.expired_at IS NULL AND t.expires_at <= $2
+			  ORDER BY t.expires_at
+			  LIMIT $3`
+
+	return r.queryPointsTransactions(query, PointsAccrue, at, limit)
+}
+
+func (r *PostgresPointsLedgerRepository) queryPointsTransactions(query string, args ...interface{}) ([]*PointsTransaction, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch points transactions: %v", err)
+	}
+	defer rows.Close()
+
+	var transactions []*PointsTransaction
+	for rows.Next() {
+		transaction, err := scanPointsTransaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan points transaction: %v", err)
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return transactions, nil
+}
+
+// loadEntries fills in the ledger entries of the transactions
+func (r *PostgresPointsLedgerRepository) loadEntries(transactions []*PointsTransaction) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*PointsTransaction, len(transactions))
+	ids := make([]int64, 0, len(transactions))
+	for _, transaction := range transactions {
+		byID[transaction.ID] = transaction
+		ids = append(ids, transaction.ID)
+	}
+
+	rows, err := r.db.Query(`SELECT transaction_id, account, amount FROM points_ledger_entries
+							 WHERE transaction_id = ANY($1)
+							 ORDER BY id`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to fetch points ledger entries: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var transactionID int64
+		var entry PointsEntry
+		if err := rows.Scan(&transactionID, &entry.Account, &entry.Amount); err != nil {
+			return fmt.Errorf("failed to scan points ledger entry: %v", err)
+		}
+		byID[transactionID].Entries = append(byID[transactionID].Entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return nil
+}
+
+// queryRower is satisfied by both *sql.DB and *sql.Tx
+type queryRower interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// lockPointsAccount serialises the postings of the user, the lock is released on commit or rollback
+func lockPointsAccount(tx *sql.Tx, userID string) error {
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext($1))`, UserPointsAccount(userID)); err != nil {
+		return fmt.Errorf("failed to lock points of user %s: %v", userID, err)
+	}
+	return nil
+}
+
+func userPointsBalance(q queryRower, userID string) (int64, error) {
+	var balance int64
+	if err := q.QueryRow(userPointsBalanceQuery, UserPointsAccount(userID)).Scan(&balance); err != nil {
+		return 0, fmt.Errorf("failed to fetch points balance of user %s: %v", userID, err)
+	}
+	return balance, nil
+}
+
+// insertPointsTransaction records the transaction and its entries, which have to balance
+func insertPointsTransaction(tx *sql.Tx, transaction *PointsTransaction) error {
+	var sum int64
+	for _, entry := range transaction.Entries {
+		sum += entry.Amount
+	}
+	if sum != 0 || len(transaction.Entries) < 2 {
+		return fmt.Errorf("points transaction %s does not balance", transaction.Reference)
+	}
+
+	if transaction.CreatedAt.IsZero() {
+		transaction.CreatedAt = time.Now()
+	}
+	err := tx.QueryRow(`
+		INSERT INTO points_transactions (user_id, type, points, reference, order_id, campaign_id, reward_item_id, source_id, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id`,
+		transaction.UserID, transaction.Type, transaction.Points, transaction.Reference, transaction.OrderID,
+		transaction.CampaignID, transaction.RewardItemID, transaction.SourceID, transaction.ExpiresAt, transaction.CreatedAt,
+	).Scan(&transaction.ID)
+	if err != nil {
+		return fmt.Errorf("failed to insert points transaction %s: %v", transaction.Reference, err)
+	}
+
+	for _, entry := range transaction.Entries {
+		_, err := tx.Exec(`INSERT INTO points_ledger_entries (transaction_id, account, amount) VALUES ($1, $2, $3)`,
+			transaction.ID, entry.Account, entry.Amount)
+		if err != nil {
+			return fmt.Errorf("failed to insert points ledger entry of transaction %d: %v", transaction.ID, err)
+		}
+	}
+
+	if transaction.Type == PointsAccrue {
+		transaction.Remaining = transaction.Points
+	}
+	return nil
+}
+
+func scanPointsTransaction(row rowScanner) (*PointsTransaction, error) {
+	var transaction PointsTransaction
+	err := row.Scan(
+		&transaction.ID,
+		&transaction.UserID,
+		&transaction.Type,
+		&transaction.Points,
+		&transaction.Reference,
+		&transaction.OrderID,
+		&transaction.CampaignID,
+		&transaction.RewardItemID,
+		&transaction.SourceID,
+		&transaction.ExpiresAt,
+		&transaction.CreatedAt,
+		&transaction.Remaining,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/craftizmv/rewards/internal/app/repository"
+	. "github.com/craftizmv/rewards/internal/domain/entities"
+	"time"
+)
+
+const referralColumns = `id, campaign_id, referrer_id, referee_id, order_id, status, created_at, rewarded_at, reversed_at`
+
+// PostgresReferralRepository is the concrete implementation of the ReferralRepository interface for Postgres
+type PostgresReferralRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresReferralRepository creates a new instance of PostgresReferralRepository
+func NewPostgresReferralRepository(db *sql.DB) repository.ReferralRepository {
+	return &PostgresReferralRepository{
+		db: db,
+	}
+}
+
+// AttributeReferral records the referral, or returns the one recorded for the same order before
+func (r *PostgresReferralRepository) AttributeReferral(referral *Referral) error {
+	query := `
+		INSERT INTO referrals (campaign_id, referrer_id, referee_id, order_id, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (campaign_id, referee_id) DO NOTHING
+		RETURNING id
+	`
+
+	err := r.db.QueryRow(query,
+		referral.CampaignID,
+		referral.ReferrerID,
+		referral.RefereeID,
+		referral.OrderID,
+		ReferralPending,
+		referral.CreatedAt,
+	).Scan(&referral.ID)
+	if err == nil {
+		referral.Status = ReferralPending
+		return nil
+	}
+	if err != sql.ErrNoRows {
+		return fmt.Errorf("failed to attribute order %d to referrer %s: %v", referral.OrderID, referral.ReferrerID, err)
+	}
+
+	// the referee was referred before, which is fine as long as it is the same attribution
+	query = `SELECT ` + referralColumns + ` FROM referrals WHERE campaign_id = $1 AND referee_id = $2`
+	existing, err := scanReferral(r.db.QueryRow(query, referral.CampaignID, referral.RefereeID))
+	if err != nil {
+		return fmt.Errorf("failed to fetch referral of referee %s: %v", referral.RefereeID, err)
+	}
+	if existing.OrderID != referral.OrderID || existing.ReferrerID != referral.ReferrerID {
+		return fmt.Errorf("%w: referee %s, campaign %s, order %d", ErrRefereeAlreadyReferred, referral.RefereeID, referral.CampaignID, existing.OrderID)
+	}
+	*referral = *existing
+
+	return nil
+}
+
+// ListReferralsByOrderID returns the referrals the order was attributed to, oldest first
+func (r *PostgresReferralRepository) ListReferralsByOrderID(orderID int64) ([]*Referral, error) {
+	query := `SELECT ` + referralColumns + ` FROM referrals WHERE order_id = $1 ORDER BY created_at`
+
+	rows, err := r.db.Query(query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list referrals of order %d: %v", orderID, err)
+	}
+	defer rows.Close()
+
+	var referrals []*Referral
+	for rows.Next() {
+		referral, err := scanReferral(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan referral: %v", err)
+		}
+		referrals = append(referrals, referral)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate referrals: %v", err)
+	}
+
+	return referrals, nil
+}
+
+// UpdateReferralStatus moves the referral from one status to the next and stamps when it happened
+func (r *PostgresReferralRepository) UpdateReferralStatus(id int64, from ReferralStatus, to ReferralStatus, at time.Time) error {
+	query := `
+		UPDATE referrals
+		SET status = $3,
+		    rewarded_at = CASE WHEN $3 = 'rewarded' THEN $4 ELSE rewarded_at END,
+		    reversed_at = CASE WHEN $3 = 'reversed' THEN $4 ELSE reversed_at END
+		WHERE id = $1 AND status = $2
+	`
+
+	result, err := r.db.Exec(query, id, from, to, at)
+	if err != nil {
+		return fmt.Errorf("failed to update referral %d: %v", id, err)
+	}
+
+	return checkAffected(result, repository.ErrReferralStatusChanged, id)
+}
+
+func scanReferral(row rowScanner) (*Referral, error) {
+	var referral Referral
+	var rewardedAt, reversedAt sql.NullTime
+
+	err := row.Scan(
+		&referral.ID,
+		&referral.CampaignID,
+		&referral.ReferrerID,
+		&referral.RefereeID,
+		&referral.OrderID,
+		&referral.Status,
+		&referral.CreatedAt,
+		&rewardedAt,
+		&reversedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if rewardedAt.Valid {
+		referral.RewardedAt = &rewardedAt.Time
+	}
+	if reversedAt.Valid {
+		referral.ReversedAt = &reversedAt.Time
+	}
+
+	return &referral, nil
+}
@@ -0,0 +1,329 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/craftizmv/rewards/internal/app/repository"
+	. "github.com/craftizmv/rewards/internal/domain/entities"
+	"strconv"
+)
+
+// PostgresRewardCatalogRepository is the concrete implementation of the RewardCatalogRepository interface for Postgres
+type PostgresRewardCatalogRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRewardCatalogRepository creates a new instance of PostgresRewardCatalogRepository
+func NewPostgresRewardCatalogRepository(db *sql.DB) repository.RewardCatalogRepository {
+	return &PostgresRewardCatalogRepository{
+		db: db,
+	}
+}
+
+// CreateRewardGroup inserts a new reward group
+func (r *PostgresRewardCatalogRepository) CreateRewardGroup(group *RewardGroup) error {
+	query := `INSERT INTO reward_groups (name, expires_at, campaign_id, choose_one, default_product_id, choice_window_hours)
+			  VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+
+	err := r.db.QueryRow(query, group.Name, group.ExpiresAt, group.CampaignID,
+		group.ChooseOne, group.DefaultProductID, group.ChoiceWindowHours).Scan(&group.ID)
+	if err != nil {
+		return fmt.Errorf("failed to insert reward group %s: %v", group.Name, err)
+	}
+
+	return nil
+}
+
+// UpdateRewardGroup overwrites the editable fields of a reward group
+func (r *PostgresRewardCatalogRepository) UpdateRewardGroup(group *RewardGroup) error {
+	query := `UPDATE reward_groups
+			  SET name = $2, expires_at = $3, campaign_id = $4, choose_one = $5, default_product_id = $6, choice_window_hours = $7
+			  WHERE id = $1`
+
+	result, err := r.db.Exec(query, group.ID, group.Name, group.ExpiresAt, group.CampaignID,
+		group.ChooseOne, group.DefaultProductID, group.ChoiceWindowHours)
+	if err != nil {
+		return fmt.Errorf("failed to update reward group %d: %v", group.ID, err)
+	}
+
+	return checkAffected(result, repository.ErrRewardGroupNotFound, group.ID)
+}
+
+// GetRewardGroup retrieves a reward group by its ID
+func (r *PostgresRewardCatalogRepository) GetRewardGroup(id int64) (*RewardGroup, error) {
+	query := `SELECT ` + rewardGroupColumns + ` FROM reward_groups WHERE id = $1`
+
+	group, err := scanRewardGroup(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: %d", repository.ErrRewardGroupNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to fetch reward group %d: %v", id, err)
+	}
+
+	return group, nil
+}
+
+// ListRewardGroups retrieves all reward groups
+func (r *PostgresRewardCatalogRepository) ListRewardGroups() ([]*RewardGroup, error) {
+	rows, err := r.db.Query(`SELECT ` + rewardGroupColumns + ` FROM reward_groups ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reward groups: %v", err)
+	}
+	defer rows.Close()
+
+	var groups []*RewardGroup
+	for rows.Next() {
+		group, err := scanRewardGroup(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reward group: %v", err)
+		}
+		groups = append(groups, group)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return groups, nil
+}
+
+// CreateRewardItem inserts a new reward item
+func (r *PostgresRewardCatalogRepository) CreateRewardItem(item *RewardItem) error {
+	conditions, metadata, err := marshalRewardItemJSON(item)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO reward_items (type, item_id, discount_amount, discount_kind, voucher_code, points, product_id,
+				  expiration_date, cost, reward_conditions, is_active, metadata)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			  RETURNING id`
+
+	var id int64
+	err = r.db.QueryRow(query,
+		item.Type,
+		item.ItemID,
+		item.DiscountAmount,
+		nullableDiscountKind(item),
+		item.VoucherCode,
+		item.Points,
+		item.ProductID,
+		item.ExpirationDate,
+		item.Cost,
+		conditions,
+		item.IsActive,
+		metadata,
+	).Scan(&id)
+	if err != nil {
+		return fmt.Errorf("failed to insert reward item: %v", err)
+	}
+
+	item.RewardItemID = strconv.FormatInt(id, 10)
+	return nil
+}
+
+// UpdateRewardItem overwrites the fields of a reward item
+func (r *PostgresRewardCatalogRepository) UpdateRewardItem(item *RewardItem) error {
+	id, err := strconv.ParseInt(item.RewardItemID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: %s", repository.ErrRewardItemNotFound, item.RewardItemID)
+	}
+
+	conditions, metadata, err := marshalRewardItemJSON(item)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE reward_items
+		SET
+			type = $2,
+			item_id = $3,
+			discount_amount = $4,
+			discount_kind = $5,
+			voucher_code = $6,
+			points = $7,
+			product_id = $8,
+			expiration_date = $9,
+			cost = $10,
+			reward_conditions = $11,
+			is_active = $12,
+			metadata = $13
+		WHERE id = $1
+	`
+
+	result, err := r.db.Exec(query,
+		id,
+		item.Type,
+		item.ItemID,
+		item.DiscountAmount,
+		nullableDiscountKind(item),
+		item.VoucherCode,
+		item.Points,
+		item.ProductID,
+		item.ExpirationDate,
+		item.Cost,
+		conditions,
+		item.IsActive,
+		metadata,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update reward item %d: %v", id, err)
+	}
+
+	return checkAffected(result, repository.ErrRewardItemNotFound, id)
+}
+
+// GetRewardItem retrieves a reward item by its ID
+func (r *PostgresRewardCatalogRepository) GetRewardItem(id int64) (*RewardItem, error) {
+	query := `SELECT ` + rewardItemColumns + ` FROM reward_items ri WHERE ri.id = $1`
+
+	item, err := scanRewardItem(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: %d", repository.ErrRewardItemNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to fetch reward item %d: %v", id, err)
+	}
+
+	return item, nil
+}
+
+// ListRewardGroupItems retrieves the reward items attached to a reward group
+func (r *PostgresRewardCatalogRepository) ListRewardGroupItems(rewardGroupID int64) ([]*RewardItem, error) {
+	query := `SELECT ` + rewardItemColumns + `
+			  FROM reward_group_reward_items rgri
+			  JOIN reward_items ri ON ri.id = rgri.reward_item_id
+			  WHERE rgri.reward_group_id = $1
+			  ORDER BY ri.id`
+
+	rows, err := r.db.Query(query, rewardGroupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch reward items of reward group %d: %v", rewardGroupID, err)
+	}
+	defer rows.Close()
+
+	return scanRewardItems(rows)
+}
+
+// AttachRewardItem maps a reward item to a reward group
+func (r *PostgresRewardCatalogRepository) AttachRewardItem(rewardGroupID, rewardItemID int64) error {
+	query := `INSERT INTO reward_group_reward_items (reward_group_id, reward_item_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+
+	if _, err := r.db.Exec(query, rewardGroupID, rewardItemID); err != nil {
+		return fmt.Errorf("failed to attach reward item %d to reward group %d: %v", rewardItemID, rewardGroupID, err)
+	}
+
+	return nil
+}
+
+// DetachRewardItem removes a reward item from a reward group
+func (r *PostgresRewardCatalogRepository) DetachRewardItem(rewardGroupID, rewardItemID int64) error {
+	query := `DELETE FROM reward_group_reward_items WHERE reward_group_id = $1 AND reward_item_id = $2`
+
+	result, err := r.db.Exec(query, rewardGroupID, rewardItemID)
+	if err != nil {
+		return fmt.Errorf("failed to detach reward item %d from reward group %d: %v", rewardItemID, rewardGroupID, err)
+	}
+
+	return checkAffected(result, repository.ErrCatalogMappingNotFound, rewardItemID)
+}
+
+// AddRewardGroupProduct maps a product to a reward group
+func (r *PostgresRewardCatalogRepository) AddRewardGroupProduct(rewardGroupID, productID int64) error {
+	query := `INSERT INTO reward_group_reward_products (reward_group_id, product_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+
+	if _, err := r.db.Exec(query, rewardGroupID, productID); err != nil {
+		return fmt.Errorf("failed to add product %d to reward group %d: %v", productID, rewardGroupID, err)
+	}
+
+	return nil
+}
+
+// RemoveRewardGroupProduct removes a product from a reward group
+func (r *PostgresRewardCatalogRepository) RemoveRewardGroupProduct(rewardGroupID, productID int64) error {
+	query := `DELETE FROM reward_group_reward_products WHERE reward_group_id = $1 AND product_id = $2`
+
+	result, err := r.db.Exec(query, rewardGroupID, productID)
+	if err != nil {
+		return fmt.Errorf("failed to remove product %d from reward group %d: %v", productID, rewardGroupID, err)
+	}
+
+	return checkAffected(result, repository.ErrCatalogMappingNotFound, productID)
+}
+
+// ListRewardGroupProductIDs retrieves the products mapped to a reward group
+func (r *PostgresRewardCatalogRepository) ListRewardGroupProductIDs(rewardGroupID int64) ([]int64, error) {
+	rows, err := r.db.Query(`SELECT product_id FROM reward_group_reward_products WHERE reward_group_id = $1 ORDER BY product_id`, rewardGroupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch products of reward group %d: %v", rewardGroupID, err)
+	}
+	defer rows.Close()
+
+	var productIDs []int64
+	for rows.Next() {
+		var productID int64
+		if err := rows.Scan(&productID); err != nil {
+			return nil, fmt.Errorf("failed to scan product id: %v", err)
+		}
+		productIDs = append(productIDs, productID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return productIDs, nil
+}
+
+// marshalRewardItemJSON encodes the JSONB columns of a reward item, nil when they are not set
+func marshalRewardItemJSON(item *RewardItem) ([]byte, []byte, error) {
+	var conditions, metadata []byte
+	if item.RewardConditions != nil {
+		encoded, err := json.Marshal(item.RewardConditions)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal reward conditions: %v", err)
+		}
+		conditions = encoded
+	}
+	if len(item.Metadata) > 0 {
+		metadata = item.Metadata
+	}
+	return conditions, metadata, nil
+}
+
+func nullableDiscountKind(item *RewardItem) sql.NullString {
+	return sql.NullString{String: string(item.DiscountKind), Valid: item.DiscountKind != ""}
+}
+
+const rewardGroupColumns = `id, name, expires_at, campaign_id, choose_one, default_product_id, choice_window_hours`
+
+func scanRewardGroup(row rowScanner) (*RewardGroup, error) {
+	var group RewardGroup
+	err := row.Scan(
+		&group.ID,
+		&group.Name,
+		&group.ExpiresAt,
+		&group.CampaignID,
+		&group.ChooseOne,
+		&group.DefaultProductID,
+		&group.ChoiceWindowHours,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func checkAffected(result sql.Result, notFound error, id int64) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: %d", notFound, id)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/craftizmv/rewards/internal/app/repository"
+	. "github.com/craftizmv/rewards/internal/domain/entities"
+	"github.com/lib/pq"
+	"time"
+)
+
+// rewardDueAt is the earliest expiry date of an allocated reward item, the one of the item itself,
+// of the reward item it was allocated from or of the reward groups the user reward ledger recorded for the order
+const rewardDueAt = `LEAST(ori.expires_at, ri.expiration_date,
+	(SELECT MIN(rg.expires_at) FROM user_reward_ledger url JOIN reward_groups rg ON rg.id = url.reward_group_id
+	 WHERE url.order_id = ori.order_id AND url.reversed_at IS NULL))`
+
+const expiringRewardColumns = `ori.id, ori.order_id, ori.reward_item_id, ori.shipment_id, ori.allocated_date, ori.is_redeemed, ori.redeemed_date,
+	ori.issued_reference, ori.user_id, ori.value, ori.redeemed_amount, ori.expires_at, ori.expired_at,
+	ori.inventory_item_ids, ori.shipment_reference, ori.shipped_at, ri.type, ` + rewardDueAt
+
+// PostgresRewardExpiryRepository is the concrete implementation of the RewardExpiryRepository interface for Postgres
+type PostgresRewardExpiryRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRewardExpiryRepository creates a new instance of PostgresRewardExpiryRepository
+func NewPostgresRewardExpiryRepository(db *sql.DB) repository.RewardExpiryRepository {
+	return &PostgresRewardExpiryRepository{
+		db: db,
+	}
+}
+
+// ListRewardsDueForExpiry retrieves the unredeemed rewards whose expiry date is reached
+func (r *PostgresRewardExpiryRepository) ListRewardsDueForExpiry(at time.Time, limit int) ([]*ExpiringReward, error) {
+	query := `SELECT * FROM (
+				  SELECT ` + expiringRewardColumns + ` AS due_at
+				  FROM order_reward_item ori
+				  JOIN reward_items ri ON ri.id = ori.reward_item_id
+				  WHERE ori.expired_at IS NULL AND ori.is_redeemed IS NOT TRUE
+			  ) due
+			  WHERE due_at <= $1
+			  ORDER BY due_at, id
+			  LIMIT $2`
+
+	rows, err := r.db.Query(query, at, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch rewards due for expiry: %v", err)
+	}
+	defer rows.Close()
+
+	return scanExpiringRewards(rows)
+}
+
+// ExpireReward marks the reward as expired unless it was redeemed or expired in the meantime
+func (r *PostgresRewardExpiryRepository) ExpireReward(id int64, at time.Time) (bool, error) {
+	result, err := r.db.Exec(`UPDATE order_reward_item SET expired_at = $2 WHERE id = $1 AND expired_at IS NULL AND is_redeemed IS NOT TRUE`, id, at)
+	if err != nil {
+		return false, fmt.Errorf("failed to expire reward %d: %v", id, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error checking rows affected: %v", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// ListRewardsDueForReminder retrieves the unredeemed rewards which expire soon and whose owner was not reminded yet
+func (r *PostgresRewardExpiryRepository) ListRewardsDueForReminder(now time.Time, remindBefore time.Time, limit int) ([]*ExpiringReward, error) {
+	query := `SELECT * FROM (
+				  SELECT ` + expiringRewardColumns + ` AS due_at
+				  FROM order_reward_item ori
+				  JOIN reward_items ri ON ri.id = ori.reward_item_id
+				  WHERE ori.expired_at IS NULL AND ori.is_redeemed IS NOT TRUE AND ori.reminded_at IS NULL AND ori.user_id IS NOT NULL
+			  ) due
+			  WHERE due_at > $1 AND due_at <= $2
+			  ORDER BY due_at, id
+			  LIMIT $3`
+
+	rows, err := r.db.Query(query, now, remindBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch rewards due for a reminder: %v", err)
+	}
+	defer rows.Close()
+
+	return scanExpiringRewards(rows)
+}
+
+// MarkReminded records that the owner of the reward was reminded of its expiry
+func (r *PostgresRewardExpiryRepository) MarkReminded(id int64, at time.Time) error {
+	if _, err := r.db.Exec(`UPDATE order_reward_item SET reminded_at = $2 WHERE id = $1`, id, at); err != nil {
+		return fmt.Errorf("failed to mark reward %d as reminded: %v", id, err)
+	}
+	return nil
+}
+
+func scanExpiringRewards(rows *sql.Rows) ([]*ExpiringReward, error) {
+	var rewards []*ExpiringReward
+	for rows.Next() {
+		var item OrderRewardItem
+		var reward ExpiringReward
+		var userID sql.NullString
+		err := rows.Scan(
+			&item.ID,
+			&item.OrderID,
+			&item.RewardItemID,
+			&item.ShipmentID,
+			&item.AllocatedDate,
+			&item.IsRedeemed,
+			&item.RedeemedDate,
+			&item.IssuedRef,
+			&userID,
+			&item.Value,
+			&item.RedeemedAmount,
+			&item.ExpiresAt,
+			&item.ExpiredAt,
+			pq.Array(&item.InventoryItemIDs),
+			&item.ShipmentRef,
+			&item.ShippedAt,
+			&reward.Type,
+			&reward.DueAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan expiring reward: %v", err)
+		}
+		item.UserID = userID.String
+		reward.Reward = &item
+		rewards = append(rewards, &reward)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return rewards, nil
+}
@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/craftizmv/rewards/internal/app/repository"
+	. "github.com/craftizmv/rewards/internal/domain/entities"
+)
+
+// PostgresRewardRedemptionRepository is the concrete implementation of the RewardRedemptionRepository interface for Postgres
+type PostgresRewardRedemptionRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRewardRedemptionRepository creates a new instance of PostgresRewardRedemptionRepository
+func NewPostgresRewardRedemptionRepository(db *sql.DB) repository.RewardRedemptionRepository {
+	return &PostgresRewardRedemptionRepository{
+		db: db,
+	}
+}
+
+// GetOrderRewardItem retrieves an allocated reward item by its ID
+func (r *PostgresRewardRedemptionRepository) GetOrderRewardItem(id int64) (*OrderRewardItem, error) {
+	query := `SELECT ` + orderRewardItemColumns + ` FROM order_reward_item WHERE id = $1`
+
+	item, err := scanOrderRewardItem(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: %d", repository.ErrRewardNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to fetch reward %d: %v", id, err)
+	}
+
+	return item, nil
+}
+
+// RedeemReward locks the reward, checks the redemption against it and records the redemption
+func (r *PostgresRewardRedemptionRepository) RedeemReward(redemption *RewardRedemption) (*OrderRewardItem, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+
+	query := `SELECT ` + orderRewardItemColumns + ` FROM order_reward_item WHERE id = $1 FOR UPDATE`
+	item, err := scanOrderRewardItem(tx.QueryRow(query, redemption.OrderRewardItemID))
+	if err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: %d", repository.ErrRewardNotFound, redemption.OrderRewardItemID)
+		}
+		return nil, fmt.Errorf("failed to lock reward %d: %v", redemption.OrderRewardItemID, err)
+	}
+
+	// checkout retries deliver the same redemption again, a reference recorded for another reward or user is not one
+	var recordedRewardID int64
+	var recordedUserID string
+	err = tx.QueryRow(`SELECT order_reward_item_id, user_id FROM reward_redemptions WHERE reference = $1`, redemption.Reference).
+		Scan(&recordedRewardID, &recordedUserID)
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to look up redemption %s: %v", redemption.Reference, err)
+	case recordedRewardID != redemption.OrderRewardItemID || recordedUserID != redemption.UserID:
+		tx.Rollback()
+		return nil, fmt.Errorf("%w: %s was used for reward %d", ErrRedemptionReferenceReused, redemption.Reference, recordedRewardID)
+	default:
+		tx.Rollback()
+		return item, nil
+	}
+
+	if err := item.Redeem(redemption); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO reward_redemptions (order_reward_item_id, user_id, order_id, reference, amount, redeemed_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`,
+		redemption.OrderRewardItemID, redemption.UserID, redemption.OrderID, redemption.Reference, redemption.Amount, redemption.RedeemedAt,
+	).Scan(&redemption.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to record redemption of reward %d: %v", item.ID, err)
+	}
+
+	_, err = tx.Exec(`UPDATE order_reward_item SET redeemed_amount = $2, is_redeemed = $3, redeemed_date = $4 WHERE id = $1`,
+		item.ID, item.RedeemedAmount, item.IsRedeemed, item.RedeemedDate)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to update reward %d: %v", item.ID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return item, nil
+}
+
+// ListRedemptions retrieves the redemptions of a reward, oldest first
+func (r *PostgresRewardRedemptionRepository) ListRedemptions(orderRewardItemID int64) ([]*RewardRedemption, error) {
+	query := `SELECT id, order_reward_item_id, user_id, order_id, reference, amount, redeemed_at
+			  FROM reward_redemptions
+			  WHERE order_reward_item_id = $1
+			  ORDER BY redeemed_at, id`
+
+	rows, err := r.db.Query(query, orderRewardItemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch redemptions of reward %d: %v", orderRewardItemID, err)
+	}
+	defer rows.Close()
+
+	var redemptions []*RewardRedemption
+	for rows.Next() {
+		var redemption RewardRedemption
+		err := rows.Scan(&redemption.ID, &redemption.OrderRewardItemID, &redemption.UserID, &redemption.OrderID,
+			&redemption.Reference, &redemption.Amount, &redemption.RedeemedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan redemption: %v", err)
+		}
+		redemptions = append(redemptions, &redemption)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return redemptions, nil
+}
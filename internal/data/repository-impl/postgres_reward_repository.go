@@ -2,12 +2,21 @@ package postgres
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"github.com/craftizmv/rewards/internal/app/repository"
+	"github.com/craftizmv/rewards/internal/data/dtos"
 	. "github.com/craftizmv/rewards/internal/domain/entities"
+	"github.com/lib/pq"
 	"strings"
 )
 
+const rewardItemColumns = `ri.id, ri.type, ri.item_id, ri.discount_amount, ri.discount_kind, ri.voucher_code, ri.points, ri.product_id,
+	ri.expiration_date, ri.cost, ri.reward_conditions, ri.is_active, ri.metadata`
+
+const orderRewardItemColumns = `id, order_id, reward_item_id, shipment_id, allocated_date, is_redeemed, redeemed_date,
+	issued_reference, user_id, value, redeemed_amount, expires_at, expired_at, inventory_item_ids, shipment_reference, shipped_at`
+
 // PostgresRewardRepository is the concrete implementation of the RewardRepository interface for Postgres
 type PostgresRewardRepository struct {
 	db *sql.DB
@@ -23,15 +32,14 @@ func NewPostgresRewardRepository(db *sql.DB) repository.RewardRepository {
 // GetRewardByID retrieves a reward group by its ID
 func (r *PostgresRewardRepository) GetRewardGroupByID(id int64) (*RewardGroup, error) {
 	// Prepare the SQL query
-	query := `SELECT id, name, expires_at, campaign_id 
+	query := `SELECT ` + rewardGroupColumns + `
 			  FROM reward_groups WHERE id = $1`
 
 	// Execute the query
 	row := r.db.QueryRow(query, id)
 
 	// Map the result to a RewardGroup entity
-	var rewardGroup RewardGroup
-	err := row.Scan(&rewardGroup.ID, &rewardGroup.Name, &rewardGroup.ExpiresAt, &rewardGroup.CampaignID)
+	rewardGroup, err := scanRewardGroup(row)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("reward group with id %d not found", id)
@@ -40,7 +48,7 @@ func (r *PostgresRewardRepository) GetRewardGroupByID(id int64) (*RewardGroup, e
 	}
 
 	// Return the result
-	return &rewardGroup, nil
+	return rewardGroup, nil
 }
 
 // GetRewardItemIDsFromRewardGroup retrieves the list of reward item IDs associated with a reward group
@@ -80,6 +88,21 @@ func (r *PostgresRewardRepository) GetRewardItemIDsFromRewardGroup(rewardGroupID
 	return rewardItemIDs, nil
 }
 
+// InsertOrderRewardGroup records the reward group allocated to an order, recording it again is a no-op
+func (r *PostgresRewardRepository) InsertOrderRewardGroup(orderID, rewardGroupID int64, fence *repository.Fence) error {
+	query := `
+		INSERT INTO order_reward_group (order_id, reward_group_id)
+		VALUES ($1, $2)
+		ON CONFLICT (order_id, reward_group_id) DO NOTHING
+	`
+
+	if _, err := execFenced(r.db, fence, query, orderID, rewardGroupID); err != nil {
+		return fmt.Errorf("failed to insert RewardGroupID %d for OrderID %d: %w", rewardGroupID, orderID, err)
+	}
+
+	return nil
+}
+
 // GetRewardGroupIDByOrderID retrieves the RewardGroupID associated with a given OrderID from the OrderRewardGroup table
 func (r *PostgresRewardRepository) GetRewardGroupIDByOrderID(orderID int64) ([]int64, error) {
 	// Prepare the SQL query
@@ -152,6 +175,38 @@ func (r *PostgresRewardRepository) GetProductIDsFromRewardGroup(rewardGroupID in
 	return productIDs, nil
 }
 
+// GetRewardGroupCost sums up the cost of the reward items of a reward group
+func (r *PostgresRewardRepository) GetRewardGroupCost(rewardGroupID int64) (float64, error) {
+	query := `SELECT COALESCE(SUM(ri.cost), 0)
+			  FROM reward_group_reward_items rgri
+			  JOIN reward_items ri ON ri.id = rgri.reward_item_id
+			  WHERE rgri.reward_group_id = $1`
+
+	var cost float64
+	if err := r.db.QueryRow(query, rewardGroupID).Scan(&cost); err != nil {
+		return 0, fmt.Errorf("failed to fetch cost of reward group %d: %v", rewardGroupID, err)
+	}
+
+	return cost, nil
+}
+
+// GetRewardItemsFromRewardGroup retrieves the reward items of a reward group
+func (r *PostgresRewardRepository) GetRewardItemsFromRewardGroup(rewardGroupID int64) ([]*RewardItem, error) {
+	query := `SELECT ` + rewardItemColumns + `
+			  FROM reward_group_reward_items rgri
+			  JOIN reward_items ri ON ri.id = rgri.reward_item_id
+			  WHERE rgri.reward_group_id = $1
+			  ORDER BY ri.id`
+
+	rows, err := r.db.Query(query, rewardGroupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch reward items of reward group %d: %v", rewardGroupID, err)
+	}
+	defer rows.Close()
+
+	return scanRewardItems(rows)
+}
+
 // InsertRewardGroupRewardItem inserts a new mapping between a reward group and a reward item
 func (r *PostgresRewardRepository) InsertRewardGroupRewardItem(rewardGroupID, rewardItemID int64) error {
 	// Prepare the SQL query for insertion
@@ -236,7 +291,7 @@ func (r *PostgresRewardRepository) InsertRewardGroupRewardItemsBatch(rewardGroup
 }
 
 // UpdateOrderRewardItemsBatch performs a bulk update of OrderRewardItem records in batches
-func (r *PostgresRewardRepository) UpdateOrderRewardItemsBatch(orderRewardItems []*OrderRewardItem, batchSize int) error {
+func (r *PostgresRewardRepository) UpdateOrderRewardItemsBatch(orderRewardItems []*OrderRewardItem, batchSize int, fence *repository.Fence) error {
 	// Validate input: Ensure there are items to update
 	if len(orderRewardItems) == 0 {
 		return fmt.Errorf("no order reward items to update")
@@ -248,6 +303,11 @@ func (r *PostgresRewardRepository) UpdateOrderRewardItemsBatch(orderRewardItems
 		return fmt.Errorf("failed to start transaction: %v", err)
 	}
 
+	if err := checkFence(tx, fence); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	// Helper function to perform batch update
 	updateBatch := func(items []*OrderRewardItem) error {
 		// Prepare the base SQL query
@@ -315,8 +375,178 @@ func (r *PostgresRewardRepository) UpdateOrderRewardItemsBatch(orderRewardItems
 	return nil
 }
 
+// InsertOrderRewardItems records the reward items allocated to an order, an item recorded before is overwritten
+func (r *PostgresRewardRepository) InsertOrderRewardItems(orderRewardItems []*OrderRewardItem, fence *repository.Fence) error {
+	if len(orderRewardItems) == 0 {
+		return fmt.Errorf("no order reward items to insert")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %v", err)
+	}
+
+	if err := checkFence(tx, fence); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	query := `
+		INSERT INTO order_reward_item (order_id, reward_item_id, shipment_id, allocated_date, is_redeemed, redeemed_date,
+			issued_reference, user_id, value, expires_at, inventory_item_ids, shipment_reference, shipped_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (order_id, reward_item_id) DO UPDATE
+		SET shipment_id = EXCLUDED.shipment_id,
+			allocated_date = EXCLUDED.allocated_date,
+			is_redeemed = EXCLUDED.is_redeemed,
+			redeemed_date = EXCLUDED.redeemed_date,
+			issued_reference = EXCLUDED.issued_reference,
+			user_id = EXCLUDED.user_id,
+			value = EXCLUDED.value,
+			expires_at = EXCLUDED.expires_at,
+			inventory_item_ids = EXCLUDED.inventory_item_ids,
+			shipment_reference = EXCLUDED.shipment_reference,
+			shipped_at = EXCLUDED.shipped_at
+		RETURNING id
+	`
+	for _, item := range orderRewardItems {
+		err := tx.QueryRow(query, item.OrderID, item.RewardItemID, item.ShipmentID, item.AllocatedDate, item.IsRedeemed, item.RedeemedDate,
+			item.IssuedRef, item.UserID, item.Value, item.ExpiresAt, pq.Array(inventoryItemIDs(item)), item.ShipmentRef, item.ShippedAt).Scan(&item.ID)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to insert reward item %d of order %d: %v", item.RewardItemID, item.OrderID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+// inventoryItemIDs keeps the column from being null for reward items which blocked no inventory
+func inventoryItemIDs(item *OrderRewardItem) []int64 {
+	if item.InventoryItemIDs == nil {
+		return []int64{}
+	}
+	return item.InventoryItemIDs
+}
+
+// GetOrderRewardItems retrieves the reward items allocated to an order
+func (r *PostgresRewardRepository) GetOrderRewardItems(orderID int64) ([]*OrderRewardItem, error) {
+	query := `SELECT ` + orderRewardItemColumns + `
+			  FROM order_reward_item
+			  WHERE order_id = $1
+			  ORDER BY reward_item_id`
+
+	rows, err := r.db.Query(query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch reward items of order %d: %v", orderID, err)
+	}
+	defer rows.Close()
+
+	var items []*OrderRewardItem
+	for rows.Next() {
+		item, err := scanOrderRewardItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan order reward item: %v", err)
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return items, nil
+}
+
+func scanRewardItem(row rowScanner) (*RewardItem, error) {
+	var item RewardItem
+	var discountAmount sql.NullFloat64
+	var discountKind sql.NullString
+	var conditions, metadata []byte
+	err := row.Scan(
+		&item.RewardItemID,
+		&item.Type,
+		&item.ItemID,
+		&discountAmount,
+		&discountKind,
+		&item.VoucherCode,
+		&item.Points,
+		&item.ProductID,
+		&item.ExpirationDate,
+		&item.Cost,
+		&conditions,
+		&item.IsActive,
+		&metadata,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if discountAmount.Valid {
+		item.DiscountAmount = &discountAmount.Float64
+	}
+	item.DiscountKind = dtos.DiscountKind(discountKind.String)
+	if len(conditions) > 0 {
+		if err := json.Unmarshal(conditions, &item.RewardConditions); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal conditions of reward item %s: %v", item.RewardItemID, err)
+		}
+	}
+	item.Metadata = metadata
+	return &item, nil
+}
+
+func scanRewardItems(rows *sql.Rows) ([]*RewardItem, error) {
+	var items []*RewardItem
+	for rows.Next() {
+		item, err := scanRewardItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reward item: %v", err)
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return items, nil
+}
+
+func scanOrderRewardItem(row rowScanner) (*OrderRewardItem, error) {
+	var item OrderRewardItem
+	var userID sql.NullString
+	err := row.Scan(
+		&item.ID,
+		&item.OrderID,
+		&item.RewardItemID,
+		&item.ShipmentID,
+		&item.AllocatedDate,
+		&item.IsRedeemed,
+		&item.RedeemedDate,
+		&item.IssuedRef,
+		&userID,
+		&item.Value,
+		&item.RedeemedAmount,
+		&item.ExpiresAt,
+		&item.ExpiredAt,
+		pq.Array(&item.InventoryItemIDs),
+		&item.ShipmentRef,
+		&item.ShippedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	item.UserID = userID.String
+	return &item, nil
+}
+
 // DeleteRewardGroupByOrderID deletes the association between an OrderID and RewardGroupID from the order_reward_group table
-func (r *PostgresRewardRepository) DeleteRewardGroupByOrderID(orderID int64, rewardGroupID int64) error {
+func (r *PostgresRewardRepository) DeleteRewardGroupByOrderID(orderID int64, rewardGroupID int64, fence *repository.Fence) error {
 	// Prepare the SQL delete query
 	query := `
 		DELETE FROM order_reward_group
@@ -324,9 +554,9 @@ func (r *PostgresRewardRepository) DeleteRewardGroupByOrderID(orderID int64, rew
 	`
 
 	// Execute the delete query
-	result, err := r.db.Exec(query, orderID, rewardGroupID)
+	result, err := execFenced(r.db, fence, query, orderID, rewardGroupID)
 	if err != nil {
-		return fmt.Errorf("failed to delete RewardGroupID %d for OrderID %d: %v", rewardGroupID, orderID, err)
+		return fmt.Errorf("failed to delete RewardGroupID %d for OrderID %d: %w", rewardGroupID, orderID, err)
 	}
 
 	// Check how many rows were affected
@@ -342,7 +572,7 @@ func (r *PostgresRewardRepository) DeleteRewardGroupByOrderID(orderID int64, rew
 }
 
 // DeleteRewardItemsByOrderID deletes all associations between an OrderID and RewardItems from the order_reward_item table
-func (r *PostgresRewardRepository) DeleteRewardItemsByOrderID(orderID int64) error {
+func (r *PostgresRewardRepository) DeleteRewardItemsByOrderID(orderID int64, fence *repository.Fence) error {
 	// Prepare the SQL delete query
 	query := `
 		DELETE FROM order_reward_item
@@ -350,9 +580,9 @@ func (r *PostgresRewardRepository) DeleteRewardItemsByOrderID(orderID int64) err
 	`
 
 	// Execute the delete query
-	result, err := r.db.Exec(query, orderID)
+	result, err := execFenced(r.db, fence, query, orderID)
 	if err != nil {
-		return fmt.Errorf("failed to delete RewardItems for OrderID %d: %v", orderID, err)
+		return fmt.Errorf("failed to delete RewardItems for OrderID %d: %w", orderID, err)
 	}
 
 	// Check how many rows were affected
@@ -367,11 +597,11 @@ func (r *PostgresRewardRepository) DeleteRewardItemsByOrderID(orderID int64) err
 	return nil
 }
 
-// DeleteRewardItemsByRewardGroupID deletes all associations between a RewardGroupID and RewardItems from the reward_group_reward_item table
+// DeleteRewardItemsByRewardGroupID deletes all associations between a RewardGroupID and RewardItems from the reward_group_reward_items table
 func (r *PostgresRewardRepository) DeleteRewardItemsByRewardGroupID(rewardGroupID int64) error {
 	// Prepare the SQL delete query
 	query := `
-		DELETE FROM reward_group_reward_item
+		DELETE FROM reward_group_reward_items
 		WHERE reward_group_id = $1
 	`
 
@@ -0,0 +1,186 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/craftizmv/rewards/internal/app/repository"
+	. "github.com/craftizmv/rewards/internal/domain/entities"
+)
+
+const rewardReviewColumns = `id, order_id, user_id, campaign_id, event, reasons, signals, status,
+	created_at, decided_at, decided_by, note`
+
+// PostgresRewardReviewRepository is the concrete implementation of the RewardReviewRepository interface for Postgres
+type PostgresRewardReviewRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRewardReviewRepository creates a new instance of PostgresRewardReviewRepository
+func NewPostgresRewardReviewRepository(db *sql.DB) repository.RewardReviewRepository {
+	return &PostgresRewardReviewRepository{
+		db: db,
+	}
+}
+
+// EnqueueReview adds a pending review and sets its id
+func (r *PostgresRewardReviewRepository) EnqueueReview(review *RewardReview) error {
+	reasons, err := json.Marshal(review.Reasons)
+	if err != nil {
+		return fmt.Errorf("failed to marshal review reasons: %v", err)
+	}
+	signals, err := json.Marshal(review.Signals)
+	if err != nil {
+		return fmt.Errorf("failed to marshal fraud signals: %v", err)
+	}
+
+	query := `
+		INSERT INTO reward_reviews (order_id, user_id, campaign_id, event, reasons, signals, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (campaign_id, order_id) WHERE status = 'pending' DO NOTHING
+		RETURNING id
+	`
+
+	err = r.db.QueryRow(query,
+		review.OrderID,
+		review.UserID,
+		review.CampaignID,
+		[]byte(review.Event),
+		reasons,
+		signals,
+		ReviewPending,
+		review.CreatedAt,
+	).Scan(&review.ID)
+	if err == sql.ErrNoRows {
+		// the order is already waiting for a review
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to enqueue review of order %d: %v", review.OrderID, err)
+	}
+	review.Status = ReviewPending
+
+	return nil
+}
+
+// GetReview fetches a review by id
+func (r *PostgresRewardReviewRepository) GetReview(id int64) (*RewardReview, error) {
+	query := `SELECT ` + rewardReviewColumns + ` FROM reward_reviews WHERE id = $1`
+
+	review, err := scanRewardReview(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %d", repository.ErrReviewNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch review %d: %v", id, err)
+	}
+
+	return review, nil
+}
+
+// ListReviews returns the reviews with the given status, oldest first
+func (r *PostgresRewardReviewRepository) ListReviews(status ReviewStatus) ([]*RewardReview, error) {
+	query := `SELECT ` + rewardReviewColumns + ` FROM reward_reviews
+			  WHERE ($1 = '' OR status = $1)
+			  ORDER BY created_at`
+
+	rows, err := r.db.Query(query, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reviews: %v", err)
+	}
+	defer rows.Close()
+
+	var reviews []*RewardReview
+	for rows.Next() {
+		review, err := scanRewardReview(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan review: %v", err)
+		}
+		reviews = append(reviews, review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate reviews: %v", err)
+	}
+
+	return reviews, nil
+}
+
+// DecideReview approves or rejects a review that is still pending
+func (r *PostgresRewardReviewRepository) DecideReview(id int64, status ReviewStatus, reviewer string, note string) error {
+	query := `
+		UPDATE reward_reviews
+		SET status = $2, decided_at = NOW(), decided_by = $3, note = $4
+		WHERE id = $1 AND status = 'pending'
+	`
+
+	result, err := r.db.Exec(query, id, status, reviewer, note)
+	if err != nil {
+		return fmt.Errorf("failed to decide review %d: %v", id, err)
+	}
+
+	return checkReviewAffected(result, id)
+}
+
+// ReopenReview puts an approved review back to pending
+func (r *PostgresRewardReviewRepository) ReopenReview(id int64, note string) error {
+	query := `
+		UPDATE reward_reviews
+		SET status = 'pending', decided_at = NULL, decided_by = '', note = $2
+		WHERE id = $1 AND status = 'approved'
+	`
+
+	result, err := r.db.Exec(query, id, note)
+	if err != nil {
+		return fmt.Errorf("failed to reopen review %d: %v", id, err)
+	}
+
+	return checkReviewAffected(result, id)
+}
+
+func checkReviewAffected(result sql.Result, id int64) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check the updated review %d: %v", id, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: %d", repository.ErrReviewNotPending, id)
+	}
+	return nil
+}
+
+func scanRewardReview(row rowScanner) (*RewardReview, error) {
+	var review RewardReview
+	var event, reasons, signals []byte
+	var decidedAt sql.NullTime
+
+	err := row.Scan(
+		&review.ID,
+		&review.OrderID,
+		&review.UserID,
+		&review.CampaignID,
+		&event,
+		&reasons,
+		&signals,
+		&review.Status,
+		&review.CreatedAt,
+		&decidedAt,
+		&review.DecidedBy,
+		&review.Note,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	review.Event = event
+	if decidedAt.Valid {
+		review.DecidedAt = &decidedAt.Time
+	}
+	if err := json.Unmarshal(reasons, &review.Reasons); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal reasons of review %d: %v", review.ID, err)
+	}
+	if err := json.Unmarshal(signals, &review.Signals); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal signals of review %d: %v", review.ID, err)
+	}
+
+	return &review, nil
+}
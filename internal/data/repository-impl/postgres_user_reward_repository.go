@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/craftizmv/rewards/internal/app/repository"
+	"github.com/craftizmv/rewards/internal/data/dtos"
+	. "github.com/craftizmv/rewards/internal/domain/entities"
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+const userRewardUsageQuery = `
+	SELECT
+		COUNT(*) FILTER (WHERE campaign_id = $2),
+		COUNT(*) FILTER (WHERE campaign_id = $2 AND allocated_at >= $3),
+		COUNT(*)
+	FROM user_reward_ledger
+	WHERE user_id = $1 AND reversed_at IS NULL`
+
+// PostgresUserRewardRepository is the concrete implementation of the UserRewardRepository interface for Postgres
+type PostgresUserRewardRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresUserRewardRepository creates a new instance of PostgresUserRewardRepository
+func NewPostgresUserRewardRepository(db *sql.DB) repository.UserRewardRepository {
+	return &PostgresUserRewardRepository{
+		db: db,
+	}
+}
+
+// GetUserRewardUsage counts the rewards the user still holds
+func (r *PostgresUserRewardRepository) GetUserRewardUsage(userID string, campaignID uuid.UUID, windowStart time.Time) (*UserRewardUsage, error) {
+	var usage UserRewardUsage
+	err := r.db.QueryRow(userRewardUsageQuery, userID, campaignID, windowStart).Scan(&usage.InCampaign, &usage.InWindow, &usage.Lifetime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch reward usage of user %s: %v", userID, err)
+	}
+
+	return &usage, nil
+}
+
+// RecordUserReward inserts the reward after checking the limits, concurrent allocations of the same user are serialised
+// and so are the allocations of a capped reward group
+func (r *PostgresUserRewardRepository) RecordUserReward(reward *UserReward, limits dtos.UserRewardLimits, tierCap int, fence *repository.Fence) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %v", err)
+	}
+
+	if err := checkFence(tx, fence); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// the lock is released on commit or rollback
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext($1))`, reward.UserID); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to lock reward ledger of user %s: %v", reward.UserID, err)
+	}
+
+	var usage UserRewardUsage
+	err = tx.QueryRow(userRewardUsageQuery, reward.UserID, reward.CampaignID, WindowStart(limits, reward.AllocatedAt)).
+		Scan(&usage.InCampaign, &usage.InWindow, &usage.Lifetime)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to fetch reward usage of user %s: %v", reward.UserID, err)
+	}
+
+	if err := CheckUserRewardLimits(limits, &usage); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if tierCap > 0 {
+		// the user lock is always taken first, so the two locks can not deadlock
+		tierKey := fmt.Sprintf("tier:%s:%d", reward.CampaignID, reward.RewardGroupID)
+		if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext($1))`, tierKey); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to lock reward group %d of campaign %s: %v", reward.RewardGroupID, reward.CampaignID, err)
+		}
+
+		var allocated int
+		err = tx.QueryRow(`SELECT COUNT(*) FROM user_reward_ledger WHERE campaign_id = $1 AND reward_group_id = $2 AND reversed_at IS NULL`,
+			reward.CampaignID, reward.RewardGroupID).Scan(&allocated)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to count rewards of reward group %d in campaign %s: %v", reward.RewardGroupID, reward.CampaignID, err)
+		}
+		if allocated >= tierCap {
+			tx.Rollback()
+			return fmt.Errorf("%w: reward group %d is capped at %d rewards", ErrTierCapReached, reward.RewardGroupID, tierCap)
+		}
+	}
+
+	_, err = tx.Exec(`INSERT INTO user_reward_ledger (user_id, campaign_id, order_id, reward_group_id, allocated_at) VALUES ($1, $2, $3, $4, $5)`,
+		reward.UserID, reward.CampaignID, reward.OrderID, reward.RewardGroupID, reward.AllocatedAt)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to record reward of user %s: %v", reward.UserID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+// ReverseUserReward marks the reward of the order as reversed
+func (r *PostgresUserRewardRepository) ReverseUserReward(userID string, campaignID uuid.UUID, orderID int64) error {
+	query := `
+		UPDATE user_reward_ledger
+		SET reversed_at = NOW()
+		WHERE user_id = $1 AND campaign_id = $2 AND order_id = $3 AND reversed_at IS NULL
+	`
+
+	if _, err := r.db.Exec(query, userID, campaignID, orderID); err != nil {
+		return fmt.Errorf("failed to reverse reward of user %s for order %d: %v", userID, orderID, err)
+	}
+
+	return nil
+}
+
+// HasUserReward tells whether the reward of the order is recorded and still counts
+func (r *PostgresUserRewardRepository) HasUserReward(userID string, campaignID uuid.UUID, orderID int64) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM user_reward_ledger
+			WHERE user_id = $1 AND campaign_id = $2 AND order_id = $3 AND reversed_at IS NULL
+		)
+	`
+
+	var exists bool
+	if err := r.db.QueryRow(query, userID, campaignID, orderID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to fetch reward of user %s for order %d: %v", userID, orderID, err)
+	}
+
+	return exists, nil
+}
+
+// CountTierRewards counts the rewards of the reward group the campaign still has out
+func (r *PostgresUserRewardRepository) CountTierRewards(campaignID uuid.UUID, rewardGroupID int64) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM user_reward_ledger
+		WHERE campaign_id = $1 AND reward_group_id = $2 AND reversed_at IS NULL
+	`
+
+	var count int
+	if err := r.db.QueryRow(query, campaignID, rewardGroupID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count rewards of reward group %d in campaign %s: %v", rewardGroupID, campaignID, err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,363 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/craftizmv/rewards/internal/app/repository"
+	. "github.com/craftizmv/rewards/internal/domain/entities"
+	"github.com/lib/pq"
+	"time"
+)
+
+const voucherPoolQuery = `
+	SELECT p.id, p.name, p.reward_group_id, p.format, p.expires_at, p.created_at,
+		COUNT(c.id),
+		COUNT(c.id) FILTER (WHERE c.status = 'available'),
+		COUNT(c.id) FILTER (WHERE c.status = 'assigned'),
+		COUNT(c.id) FILTER (WHERE c.status = 'consumed'),
+		COUNT(c.id) FILTER (WHERE c.status = 'voided')
+	FROM voucher_pools p
+	LEFT JOIN voucher_codes c ON c.pool_id = p.id`
+
+const voucherCodeColumns = `id, pool_id, code, status, order_id, user_id, assigned_at, consumed_at`
+
+// voucherCodeBatchSize bounds the number of codes inserted by a single statement
+const voucherCodeBatchSize = 1000
+
+// PostgresVoucherRepository is the concrete implementation of the VoucherRepository interface for Postgres
+type PostgresVoucherRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresVoucherRepository creates a new instance of PostgresVoucherRepository
+func NewPostgresVoucherRepository(db *sql.DB) repository.VoucherRepository {
+	return &PostgresVoucherRepository{
+		db: db,
+	}
+}
+
+// CreatePool inserts a new voucher pool and sets its id
+func (r *PostgresVoucherRepository) CreatePool(pool *VoucherPool) error {
+	format, err := json.Marshal(pool.Format)
+	if err != nil {
+		return fmt.Errorf("failed to marshal voucher code format: %v", err)
+	}
+
+	query := `INSERT INTO voucher_pools (name, reward_group_id, format, expires_at)
+			  VALUES ($1, $2, $3, $4)
+			  RETURNING id, created_at`
+
+	err = r.db.QueryRow(query, pool.Name, pool.RewardGroupID, format, pool.ExpiresAt).Scan(&pool.ID, &pool.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert voucher pool %s: %v", pool.Name, err)
+	}
+
+	return nil
+}
+
+// GetPool fetches a voucher pool with its stock
+func (r *PostgresVoucherRepository) GetPool(id int64) (*VoucherPool, error) {
+	query := voucherPoolQuery + ` WHERE p.id = $1 GROUP BY p.id`
+
+	pool, err := scanVoucherPool(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %d", repository.ErrVoucherPoolNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch voucher pool %d: %v", id, err)
+	}
+
+	return pool, nil
+}
+
+// ListPools returns every voucher pool with its stock
+func (r *PostgresVoucherRepository) ListPools() ([]*VoucherPool, error) {
+	return r.queryPools(voucherPoolQuery + ` GROUP BY p.id ORDER BY p.id`)
+}
+
+// ListPoolsByRewardGroup returns the voucher pools of a reward group with their stock
+func (r *PostgresVoucherRepository) ListPoolsByRewardGroup(rewardGroupID int64) ([]*VoucherPool, error) {
+	return r.queryPools(voucherPoolQuery+` WHERE p.reward_group_id = $1 GROUP BY p.id ORDER BY p.id`, rewardGroupID)
+}
+
+func (r *PostgresVoucherRepository) queryPools(query string, args ...interface{}) ([]*VoucherPool, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list voucher pools: %v", err)
+	}
+	defer rows.Close()
+
+	var pools []*VoucherPool
+	for rows.Next() {
+		pool, err := scanVoucherPool(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan voucher pool: %v", err)
+		}
+		pools = append(pools, pool)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate voucher pools: %v", err)
+	}
+
+	return pools, nil
+}
+
+// AddCodes inserts the codes in batches, codes that exist in any pool are skipped
+func (r *PostgresVoucherRepository) AddCodes(poolID int64, codes []string) (int, error) {
+	query := `INSERT INTO voucher_codes (pool_id, code)
+			  SELECT $1, UNNEST($2::text[])
+			  ON CONFLICT (code) DO NOTHING`
+
+	added := 0
+	for start := 0; start < len(codes); start += voucherCodeBatchSize {
+		end := start + voucherCodeBatchSize
+		if end > len(codes) {
+			end = len(codes)
+		}
+
+		result, err := r.db.Exec(query, poolID, pq.Array(codes[start:end]))
+		if err != nil {
+			return added, fmt.Errorf("failed to add codes to voucher pool %d: %v", poolID, err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return added, fmt.Errorf("failed to count the codes added to voucher pool %d: %v", poolID, err)
+		}
+		added += int(affected)
+	}
+
+	return added, nil
+}
+
+// AssignCode hands out the oldest available code, concurrent allocations skip the codes locked by each other
+func (r *PostgresVoucherRepository) AssignCode(poolID int64, orderID int64, userID string, fence *repository.Fence) (*VoucherCode, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+
+	if err := checkFence(tx, fence); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	existing := `SELECT ` + voucherCodeColumns + ` FROM voucher_codes
+				 WHERE pool_id = $1 AND order_id = $2 AND status = 'assigned'`
+	code, err := scanVoucherCode(tx.QueryRow(existing, poolID, orderID))
+	if err == nil {
+		tx.Rollback()
+		return code, nil
+	}
+	if err != sql.ErrNoRows {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to look up the code of order %d: %v", orderID, err)
+	}
+
+	query := `
+		UPDATE voucher_codes
+		SET status = 'assigned', order_id = $2, user_id = $3, assigned_at = NOW()
+		WHERE id = (
+			SELECT c.id FROM voucher_codes c
+			JOIN voucher_pools p ON p.id = c.pool_id
+			WHERE c.pool_id = $1 AND c.status = 'available' AND (p.expires_at IS NULL OR p.expires_at > NOW())
+			ORDER BY c.id
+			LIMIT 1
+			FOR UPDATE OF c SKIP LOCKED
+		)
+		RETURNING ` + voucherCodeColumns
+
+	code, err = scanVoucherCode(tx.QueryRow(query, poolID, orderID, userID))
+	if err == sql.ErrNoRows {
+		tx.Rollback()
+		return nil, fmt.Errorf("%w: pool %d", repository.ErrVoucherPoolExhausted, poolID)
+	}
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to assign a code of voucher pool %d to order %d: %v", poolID, orderID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return code, nil
+}
+
+// ReleaseCodes makes the codes assigned to the order available again
+func (r *PostgresVoucherRepository) ReleaseCodes(orderID int64) error {
+	query := `
+		UPDATE voucher_codes
+		SET status = 'available', order_id = NULL, user_id = '', assigned_at = NULL
+		WHERE order_id = $1 AND status = 'assigned'
+	`
+
+	if _, err := r.db.Exec(query, orderID); err != nil {
+		return fmt.Errorf("failed to release the codes of order %d: %v", orderID, err)
+	}
+
+	return nil
+}
+
+// reclaimCodes is the update which takes assigned codes back, the codes of a pool which did not expire go back in
+// the pool and the others are voided. It returns the codes with the order and the user they had been handed to.
+const reclaimCodes = `
+		UPDATE voucher_codes vc
+		SET status = CASE WHEN due.pool_expired THEN 'voided' ELSE 'available' END,
+			order_id = CASE WHEN due.pool_expired THEN vc.order_id END,
+			user_id = CASE WHEN due.pool_expired THEN vc.user_id ELSE '' END,
+			assigned_at = CASE WHEN due.pool_expired THEN vc.assigned_at END
+		FROM due
+		WHERE vc.id = due.id
+		RETURNING vc.id, vc.pool_id, vc.code, vc.status, due.order_id, due.user_id, due.assigned_at, vc.consumed_at
+	`
+
+// ReclaimExpiredCodes takes back the unused codes of expired reward groups and of expired pools
+func (r *PostgresVoucherRepository) ReclaimExpiredCodes(at time.Time, limit int) ([]*VoucherCode, error) {
+	query := `
+		WITH due AS (
+			SELECT vc.id, vc.order_id, vc.user_id, vc.assigned_at, COALESCE(vp.expires_at <= $1, FALSE) AS pool_expired
+			FROM voucher_codes vc
+			JOIN voucher_pools vp ON vp.id = vc.pool_id
+			JOIN reward_groups rg ON rg.id = vp.reward_group_id
+			WHERE vc.status = 'assigned' AND (rg.expires_at <= $1 OR vp.expires_at <= $1)
+			ORDER BY vc.id
+			LIMIT $2
+			FOR UPDATE OF vc SKIP LOCKED
+		)` + reclaimCodes
+
+	rows, err := r.db.Query(query, at, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reclaim expired codes: %v", err)
+	}
+	defer rows.Close()
+
+	var codes []*VoucherCode
+	for rows.Next() {
+		code, err := scanVoucherCode(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan voucher code: %v", err)
+		}
+		codes = append(codes, code)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return codes, nil
+}
+
+// ReclaimCodes takes back the unused codes assigned to the order
+func (r *PostgresVoucherRepository) ReclaimCodes(orderID int64, at time.Time) error {
+	query := `
+		WITH due AS (
+			SELECT vc.id, vc.order_id, vc.user_id, vc.assigned_at, COALESCE(vp.expires_at <= $2, FALSE) AS pool_expired
+			FROM voucher_codes vc
+			JOIN voucher_pools vp ON vp.id = vc.pool_id
+			WHERE vc.order_id = $1 AND vc.status = 'assigned'
+			FOR UPDATE OF vc
+		)` + reclaimCodes
+
+	if _, err := r.db.Exec(query, orderID, at); err != nil {
+		return fmt.Errorf("failed to reclaim the codes of order %d: %v", orderID, err)
+	}
+
+	return nil
+}
+
+// ConsumeCode marks a code assigned to the user as used
+func (r *PostgresVoucherRepository) ConsumeCode(code string, userID string) (*VoucherCode, error) {
+	query := `
+		UPDATE voucher_codes
+		SET status = 'consumed', consumed_at = NOW()
+		WHERE code = $1 AND user_id = $2 AND status = 'assigned'
+		RETURNING ` + voucherCodeColumns
+
+	consumed, err := scanVoucherCode(r.db.QueryRow(query, code, userID))
+	if err == nil {
+		return consumed, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to consume voucher code: %v", err)
+	}
+
+	// tell a missing code apart from one of another user or in the wrong status
+	var status VoucherCodeStatus
+	var owner string
+	err = r.db.QueryRow(`SELECT status, user_id FROM voucher_codes WHERE code = $1`, code).Scan(&status, &owner)
+	if err == sql.ErrNoRows {
+		return nil, repository.ErrVoucherCodeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch voucher code: %v", err)
+	}
+	if status == VoucherAssigned && owner != userID {
+		return nil, repository.ErrVoucherCodeNotOwned
+	}
+	return nil, fmt.Errorf("%w: code is %s", repository.ErrVoucherCodeNotActive, status)
+}
+
+func scanVoucherPool(row rowScanner) (*VoucherPool, error) {
+	var pool VoucherPool
+	var format []byte
+	var expiresAt sql.NullTime
+
+	err := row.Scan(
+		&pool.ID,
+		&pool.Name,
+		&pool.RewardGroupID,
+		&format,
+		&expiresAt,
+		&pool.CreatedAt,
+		&pool.Stock.Total,
+		&pool.Stock.Available,
+		&pool.Stock.Assigned,
+		&pool.Stock.Consumed,
+		&pool.Stock.Voided,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if expiresAt.Valid {
+		pool.ExpiresAt = &expiresAt.Time
+	}
+	if err := json.Unmarshal(format, &pool.Format); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal format of voucher pool %d: %v", pool.ID, err)
+	}
+
+	return &pool, nil
+}
+
+func scanVoucherCode(row rowScanner) (*VoucherCode, error) {
+	var code VoucherCode
+	var orderID sql.NullInt64
+	var assignedAt, consumedAt sql.NullTime
+
+	err := row.Scan(
+		&code.ID,
+		&code.PoolID,
+		&code.Code,
+		&code.Status,
+		&orderID,
+		&code.UserID,
+		&assignedAt,
+		&consumedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if orderID.Valid {
+		code.OrderID = &orderID.Int64
+	}
+	if assignedAt.Valid {
+		code.AssignedAt = &assignedAt.Time
+	}
+	if consumedAt.Valid {
+		code.ConsumedAt = &consumedAt.Time
+	}
+
+	return &code, nil
+}
@@ -0,0 +1,76 @@
+package conditions
+
+// check returns the type of the node, or a *CompileError if the node is not well typed
+func check(n node) (Type, error) {
+	switch n := n.(type) {
+	case *literal:
+		return n.typ, nil
+	case *variableRef:
+		return n.def.typ, nil
+	case *list:
+		return TypeInvalid, errorAt(n.at, "a list can only be used on the right of in")
+	case *unary:
+		typ, err := check(n.operand)
+		if err != nil {
+			return TypeInvalid, err
+		}
+		if typ != TypeBool {
+			return TypeInvalid, errorAt(n.at, "! needs a condition, found a %s", typ)
+		}
+		return TypeBool, nil
+	case *binary:
+		return checkBinary(n)
+	}
+	return TypeInvalid, errorAt(n.column(), "unsupported expression")
+}
+
+func checkBinary(n *binary) (Type, error) {
+	left, err := check(n.left)
+	if err != nil {
+		return TypeInvalid, err
+	}
+
+	if n.op.kind == tokenIn {
+		elems, ok := n.right.(*list)
+		if !ok {
+			return TypeInvalid, errorAt(n.right.column(), `right side of in must be a list, such as ["gold", "vip"]`)
+		}
+		if len(elems.elems) == 0 {
+			return TypeInvalid, errorAt(elems.at, "list is empty")
+		}
+		for _, elem := range elems.elems {
+			typ, err := check(elem)
+			if err != nil {
+				return TypeInvalid, err
+			}
+			if typ != left {
+				return TypeInvalid, errorAt(elem.column(), "list holds a %s where a %s is expected", typ, left)
+			}
+		}
+		return TypeBool, nil
+	}
+
+	right, err := check(n.right)
+	if err != nil {
+		return TypeInvalid, err
+	}
+
+	switch n.op.kind {
+	case tokenAnd, tokenOr:
+		if left != TypeBool {
+			return TypeInvalid, errorAt(n.left.column(), "left side of %s must be a condition, found a %s", n.op.text, left)
+		}
+		if right != TypeBool {
+			return TypeInvalid, errorAt(n.right.column(), "right side of %s must be a condition, found a %s", n.op.text, right)
+		}
+	case tokenEq, tokenNe:
+		if left != right {
+			return TypeInvalid, errorAt(n.at, "cannot compare a %s with a %s", left, right)
+		}
+	default:
+		if left != TypeNumber || right != TypeNumber {
+			return TypeInvalid, errorAt(n.at, "%s needs numbers on both sides, found a %s and a %s", n.op.text, left, right)
+		}
+	}
+	return TypeBool, nil
+}
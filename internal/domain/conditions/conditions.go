@@ -0,0 +1,101 @@
+// Package conditions implements the expression language of reward and campaign conditions, e.g.
+//
+//	order.value >= 500 && user.tier in ["gold", "vip"] && now.weekday in [SAT, SUN]
+//
+// Expressions are parsed and type checked when a campaign or reward item is saved, so authors get
+// positioned errors up front, and evaluated against the order, its user and the campaign during eligibility.
+package conditions
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Type is the static type of a value in an expression
+type Type int
+
+const (
+	TypeInvalid Type = iota
+	TypeBool
+	TypeNumber
+	TypeString
+	TypeWeekday
+)
+
+func (t Type) String() string {
+	switch t {
+	case TypeBool:
+		return "boolean"
+	case TypeNumber:
+		return "number"
+	case TypeString:
+		return "string"
+	case TypeWeekday:
+		return "weekday"
+	}
+	return "invalid"
+}
+
+// CompileError is returned when an expression cannot be parsed or does not type check
+type CompileError struct {
+	Column int    // 1-based column the error was found at
+	Msg    string // What is wrong, written for the author of the expression
+}
+
+func (e *CompileError) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Column, e.Msg)
+}
+
+func errorAt(column int, format string, args ...interface{}) *CompileError {
+	return &CompileError{Column: column, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Expression is a compiled, type checked condition
+type Expression struct {
+	source string
+	root   node
+}
+
+// Compile parses the source and checks that it evaluates to a boolean
+func Compile(source string) (*Expression, error) {
+	if strings.TrimSpace(source) == "" {
+		return nil, errorAt(1, "condition is empty")
+	}
+
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		if isComparison(t.kind) || t.kind == tokenIn {
+			return nil, errorAt(t.column, "comparisons cannot be chained, join them with &&")
+		}
+		return nil, errorAt(t.column, "unexpected %s, join conditions with && or ||", t)
+	}
+
+	typ, err := check(root)
+	if err != nil {
+		return nil, err
+	}
+	if typ != TypeBool {
+		return nil, errorAt(root.column(), "condition must be true or false, found a %s", typ)
+	}
+
+	return &Expression{source: source, root: root}, nil
+}
+
+// Eval tells whether the context satisfies the expression
+func (e *Expression) Eval(ctx *Context) bool {
+	return eval(e.root, ctx).(bool)
+}
+
+// String returns the source the expression was compiled from
+func (e *Expression) String() string {
+	return e.source
+}
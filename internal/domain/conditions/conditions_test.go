@@ -0,0 +1,117 @@
+package conditions
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     string
+		wantColumn int
+		wantMsg    string
+	}{
+		// parser
+		{name: "empty", source: "  ", wantColumn: 1, wantMsg: "empty"},
+		{name: "missing operand", source: "order.value >=", wantColumn: 15, wantMsg: "expected a value"},
+		{name: "missing closing paren", source: "(order.value > 5", wantColumn: 17, wantMsg: `")"`},
+		{name: "chained comparison", source: "1 < order.value < 5", wantColumn: 17, wantMsg: "chained"},
+		{name: "trailing value", source: "true false", wantColumn: 6, wantMsg: "join conditions"},
+		{name: "unclosed list", source: `user.tier in ["gold" "vip"]`, wantColumn: 22, wantMsg: `expected "," or "]"`},
+		{name: "minus without number", source: "order.value > -x", wantColumn: 16, wantMsg: "a number after -"},
+		{name: "unknown object", source: "shop.id == 1", wantColumn: 1, wantMsg: "unknown object shop"},
+		{name: "unknown field", source: "order.colour == 1", wantColumn: 1, wantMsg: "unknown field colour of order"},
+		{name: "bare word", source: "user.tier == gold", wantColumn: 14, wantMsg: "quote it"},
+		{name: "lowercase weekday", source: "now.weekday == sat", wantColumn: 16, wantMsg: "capitals as SAT"},
+
+		// type checker
+		{name: "not a condition", source: "order.value", wantColumn: 1, wantMsg: "must be true or false"},
+		{name: "negated number", source: "!order.value", wantColumn: 1, wantMsg: "! needs a condition"},
+		{name: "number and string", source: `order.value == "500"`, wantColumn: 13, wantMsg: "cannot compare a number with a string"},
+		{name: "ordered strings", source: `user.tier > "gold"`, wantColumn: 11, wantMsg: "needs numbers on both sides"},
+		{name: "and of a number", source: "order.value && true", wantColumn: 1, wantMsg: "left side of && must be a condition"},
+		{name: "or of a string", source: `true || "yes"`, wantColumn: 9, wantMsg: "right side of || must be a condition"},
+		{name: "in without list", source: `user.tier in "gold"`, wantColumn: 14, wantMsg: "must be a list"},
+		{name: "in empty list", source: "user.tier in []", wantColumn: 14, wantMsg: "list is empty"},
+		{name: "in mixed list", source: `now.weekday in [SAT, "SUN"]`, wantColumn: 22, wantMsg: "list holds a string where a weekday is expected"},
+		{name: "list outside of in", source: "[1, 2] == [1, 2]", wantColumn: 1, wantMsg: "right of in"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(tt.source)
+			var compileErr *CompileError
+			if !errors.As(err, &compileErr) {
+				t.Fatalf("Compile(%q) error = %v, want a *CompileError", tt.source, err)
+			}
+			if compileErr.Column != tt.wantColumn {
+				t.Errorf("Compile(%q) error at column %d, want %d: %v", tt.source, compileErr.Column, tt.wantColumn, compileErr)
+			}
+			if !strings.Contains(compileErr.Msg, tt.wantMsg) {
+				t.Errorf("Compile(%q) error = %q, want it to mention %q", tt.source, compileErr.Msg, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestEval(t *testing.T) {
+	saturday := time.Date(2024, time.March, 16, 20, 30, 0, 0, time.UTC)
+	ctx := &Context{
+		Order: OrderFacts{
+			Value:       750,
+			Quantity:    3,
+			ItemCount:   2,
+			Channel:     "app",
+			PaymentType: "card",
+			Issuer:      "ACME Bank",
+			Country:     "DE",
+			City:        "Berlin",
+		},
+		User:     UserFacts{ID: "u-1", Tier: "Gold"},
+		Campaign: CampaignFacts{ID: "c-1", Name: "Spring", RewardValue: 25},
+		Now:      saturday,
+	}
+
+	tests := []struct {
+		name   string
+		source string
+		want   bool
+	}{
+		{name: "number at least", source: "order.value >= 500", want: true},
+		{name: "number below", source: "order.value < 500", want: false},
+		{name: "number equal", source: "order.quantity == 3", want: true},
+		{name: "negative number", source: "order.value > -1", want: true},
+		{name: "decimal number", source: "campaign.reward_value <= 25.0", want: true},
+		{name: "string ignores case", source: `user.tier == "gold"`, want: true},
+		{name: "string not equal", source: `order.channel != "web"`, want: true},
+		{name: "string in list", source: `user.tier in ["silver", "GOLD"]`, want: true},
+		{name: "string not in list", source: `order.country in ["FR", "NL"]`, want: false},
+		{name: "weekday in list", source: "now.weekday in [SAT, SUN]", want: true},
+		{name: "weekday equal", source: "now.weekday == MON", want: false},
+		{name: "time fields", source: "now.hour == 20 && now.day == 16 && now.month == 3", want: true},
+		{name: "and", source: `order.value >= 500 && order.payment_type == "cash"`, want: false},
+		{name: "or", source: `order.value >= 1000 || order.city == "berlin"`, want: true},
+		{name: "not", source: "!(order.item_count > 2)", want: true},
+		{name: "and binds tighter than or", source: "true || false && false", want: true},
+		{name: "parentheses", source: "(true || false) && false", want: false},
+		{name: "boolean literal", source: "false == false", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expression, err := Compile(tt.source)
+			if err != nil {
+				t.Fatalf("Compile(%q) error = %v", tt.source, err)
+			}
+			if got := expression.Eval(ctx); got != tt.want {
+				t.Errorf("Eval(%q) = %v, want %v", tt.source, got, tt.want)
+			}
+			if expression.String() != tt.source {
+				t.Errorf("String() = %q, want %q", expression.String(), tt.source)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package conditions
+
+import "time"
+
+// Context holds the facts an expression is evaluated against
+type Context struct {
+	Order    OrderFacts
+	User     UserFacts
+	Campaign CampaignFacts
+	Now      time.Time // Evaluation time, in the timezone of the campaign
+}
+
+// OrderFacts are the order values visible to expressions
+type OrderFacts struct {
+	Value       float64
+	Quantity    int
+	ItemCount   int // Distinct line items
+	Channel     string
+	PaymentType string
+	Issuer      string
+	Country     string
+	State       string
+	City        string
+}
+
+// UserFacts are the values of the user placing the order visible to expressions
+type UserFacts struct {
+	ID   string
+	Tier string
+}
+
+// CampaignFacts are the campaign values visible to expressions
+type CampaignFacts struct {
+	ID          string
+	Name        string
+	RewardValue float64
+}
+
+type variable struct {
+	typ   Type
+	value func(ctx *Context) interface{}
+}
+
+// variables lists every name an expression can refer to, numbers are always float64
+var variables = map[string]variable{
+	"order.value":        {TypeNumber, func(ctx *Context) interface{} { return ctx.Order.Value }},
+	"order.quantity":     {TypeNumber, func(ctx *Context) interface{} { return float64(ctx.Order.Quantity) }},
+	"order.item_count":   {TypeNumber, func(ctx *Context) interface{} { return float64(ctx.Order.ItemCount) }},
+	"order.channel":      {TypeString, func(ctx *Context) interface{} { return ctx.Order.Channel }},
+	"order.payment_type": {TypeString, func(ctx *Context) interface{} { return ctx.Order.PaymentType }},
+	"order.issuer":       {TypeString, func(ctx *Context) interface{} { return ctx.Order.Issuer }},
+	"order.country":      {TypeString, func(ctx *Context) interface{} { return ctx.Order.Country }},
+	"order.state":        {TypeString, func(ctx *Context) interface{} { return ctx.Order.State }},
+	"order.city":         {TypeString, func(ctx *Context) interface{} { return ctx.Order.City }},
+
+	"user.id":   {TypeString, func(ctx *Context) interface{} { return ctx.User.ID }},
+	"user.tier": {TypeString, func(ctx *Context) interface{} { return ctx.User.Tier }},
+
+	"campaign.id":           {TypeString, func(ctx *Context) interface{} { return ctx.Campaign.ID }},
+	"campaign.name":         {TypeString, func(ctx *Context) interface{} { return ctx.Campaign.Name }},
+	"campaign.reward_value": {TypeNumber, func(ctx *Context) interface{} { return ctx.Campaign.RewardValue }},
+
+	"now.weekday": {TypeWeekday, func(ctx *Context) interface{} { return ctx.Now.Weekday() }},
+	"now.hour":    {TypeNumber, func(ctx *Context) interface{} { return float64(ctx.Now.Hour()) }},
+	"now.day":     {TypeNumber, func(ctx *Context) interface{} { return float64(ctx.Now.Day()) }},
+	"now.month":   {TypeNumber, func(ctx *Context) interface{} { return float64(ctx.Now.Month()) }},
+}
@@ -0,0 +1,60 @@
+package conditions
+
+import "strings"
+
+// eval computes the value of a type checked node, so the type assertions cannot fail
+func eval(n node, ctx *Context) interface{} {
+	switch n := n.(type) {
+	case *literal:
+		return n.value
+	case *variableRef:
+		return n.def.value(ctx)
+	case *unary:
+		return !eval(n.operand, ctx).(bool)
+	case *binary:
+		return evalBinary(n, ctx)
+	}
+	return nil
+}
+
+func evalBinary(n *binary, ctx *Context) bool {
+	switch n.op.kind {
+	case tokenAnd:
+		return eval(n.left, ctx).(bool) && eval(n.right, ctx).(bool)
+	case tokenOr:
+		return eval(n.left, ctx).(bool) || eval(n.right, ctx).(bool)
+	case tokenIn:
+		value := eval(n.left, ctx)
+		for _, elem := range n.right.(*list).elems {
+			if equal(value, eval(elem, ctx)) {
+				return true
+			}
+		}
+		return false
+	case tokenEq:
+		return equal(eval(n.left, ctx), eval(n.right, ctx))
+	case tokenNe:
+		return !equal(eval(n.left, ctx), eval(n.right, ctx))
+	}
+
+	left, right := eval(n.left, ctx).(float64), eval(n.right, ctx).(float64)
+	switch n.op.kind {
+	case tokenLt:
+		return left < right
+	case tokenLe:
+		return left <= right
+	case tokenGt:
+		return left > right
+	case tokenGe:
+		return left >= right
+	}
+	return false
+}
+
+// equal compares strings case-insensitively, so "Gold" matches a "gold" tier
+func equal(a, b interface{}) bool {
+	if s, ok := a.(string); ok {
+		return strings.EqualFold(s, b.(string))
+	}
+	return a == b
+}
@@ -0,0 +1,168 @@
+package conditions
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenTrue
+	tokenFalse
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenEq
+	tokenNe
+	tokenLt
+	tokenLe
+	tokenGt
+	tokenGe
+	tokenIn
+	tokenMinus
+	tokenLParen
+	tokenRParen
+	tokenLBracket
+	tokenRBracket
+	tokenComma
+)
+
+var keywords = map[string]tokenKind{
+	"true":  tokenTrue,
+	"false": tokenFalse,
+	"in":    tokenIn,
+}
+
+var twoCharOperators = map[string]tokenKind{
+	"&&": tokenAnd,
+	"||": tokenOr,
+	"==": tokenEq,
+	"!=": tokenNe,
+	"<=": tokenLe,
+	">=": tokenGe,
+}
+
+var oneCharOperators = map[byte]tokenKind{
+	'!': tokenNot,
+	'<': tokenLt,
+	'>': tokenGt,
+	'-': tokenMinus,
+	'(': tokenLParen,
+	')': tokenRParen,
+	'[': tokenLBracket,
+	']': tokenRBracket,
+	',': tokenComma,
+}
+
+// misspelled operators which are common enough to deserve a hint
+var operatorHints = map[byte]string{
+	'&': "&&",
+	'|': "||",
+	'=': "==",
+}
+
+type token struct {
+	kind   tokenKind
+	text   string // Source text, the unquoted value for strings
+	column int    // 1-based column of the first character
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of condition"
+	case tokenIdent:
+		return fmt.Sprintf("identifier %s", t.text)
+	case tokenNumber:
+		return fmt.Sprintf("number %s", t.text)
+	case tokenString:
+		return fmt.Sprintf("string %q", t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(source); {
+		c := source[i]
+		start := i
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case isDigit(c):
+			for i < len(source) && (isDigit(source[i]) || source[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: source[start:i], column: start + 1})
+		case isIdentStart(c):
+			for i < len(source) && (isIdentStart(source[i]) || isDigit(source[i]) || source[i] == '.') {
+				i++
+			}
+			text := source[start:i]
+			kind, ok := keywords[text]
+			if !ok {
+				kind = tokenIdent
+			}
+			tokens = append(tokens, token{kind: kind, text: text, column: start + 1})
+		case c == '"' || c == '\'':
+			text, end, err := scanString(source, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, column: start + 1})
+			i = end
+		default:
+			if i+1 < len(source) {
+				if kind, ok := twoCharOperators[source[i:i+2]]; ok {
+					tokens = append(tokens, token{kind: kind, text: source[i : i+2], column: start + 1})
+					i += 2
+					continue
+				}
+			}
+			if kind, ok := oneCharOperators[c]; ok {
+				tokens = append(tokens, token{kind: kind, text: string(c), column: start + 1})
+				i++
+				continue
+			}
+			if hint, ok := operatorHints[c]; ok {
+				return nil, errorAt(start+1, "unexpected character %q, did you mean %s?", c, hint)
+			}
+			return nil, errorAt(start+1, "unexpected character %q", c)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, column: len(source) + 1}), nil
+}
+
+// scanString reads the string literal starting at the quote at index start.
+// It returns the unquoted value and the index after the closing quote.
+func scanString(source string, start int) (string, int, error) {
+	quote := source[start]
+	var value strings.Builder
+	for i := start + 1; i < len(source); i++ {
+		switch c := source[i]; {
+		case c == quote:
+			return value.String(), i + 1, nil
+		case c == '\\' && i+1 < len(source):
+			i++
+			value.WriteByte(source[i])
+		default:
+			value.WriteByte(c)
+		}
+	}
+	return "", 0, errorAt(start+1, "string is not terminated, add a closing %c", quote)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
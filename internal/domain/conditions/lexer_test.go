@@ -0,0 +1,105 @@
+package conditions
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []token
+	}{
+		{
+			name:   "comparison",
+			source: "order.value >= 500",
+			want: []token{
+				{kind: tokenIdent, text: "order.value", column: 1},
+				{kind: tokenGe, text: ">=", column: 13},
+				{kind: tokenNumber, text: "500", column: 16},
+				{kind: tokenEOF, column: 19},
+			},
+		},
+		{
+			name:   "keywords and list",
+			source: `user.tier in ["gold", 'vip'] && true`,
+			want: []token{
+				{kind: tokenIdent, text: "user.tier", column: 1},
+				{kind: tokenIn, text: "in", column: 11},
+				{kind: tokenLBracket, text: "[", column: 14},
+				{kind: tokenString, text: "gold", column: 15},
+				{kind: tokenComma, text: ",", column: 21},
+				{kind: tokenString, text: "vip", column: 23},
+				{kind: tokenRBracket, text: "]", column: 28},
+				{kind: tokenAnd, text: "&&", column: 30},
+				{kind: tokenTrue, text: "true", column: 33},
+				{kind: tokenEOF, column: 37},
+			},
+		},
+		{
+			name:   "negation and grouping",
+			source: "!(now.hour<-1.5)",
+			want: []token{
+				{kind: tokenNot, text: "!", column: 1},
+				{kind: tokenLParen, text: "(", column: 2},
+				{kind: tokenIdent, text: "now.hour", column: 3},
+				{kind: tokenLt, text: "<", column: 11},
+				{kind: tokenMinus, text: "-", column: 12},
+				{kind: tokenNumber, text: "1.5", column: 13},
+				{kind: tokenRParen, text: ")", column: 16},
+				{kind: tokenEOF, column: 17},
+			},
+		},
+		{
+			name:   "escaped quote",
+			source: `campaign.name == "say \"hi\""`,
+			want: []token{
+				{kind: tokenIdent, text: "campaign.name", column: 1},
+				{kind: tokenEq, text: "==", column: 15},
+				{kind: tokenString, text: `say "hi"`, column: 18},
+				{kind: tokenEOF, column: 30},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tokenize(tt.source)
+			if err != nil {
+				t.Fatalf("tokenize(%q) error = %v", tt.source, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tokenize(%q) = %+v, want %+v", tt.source, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenizeErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     string
+		wantColumn int
+	}{
+		{name: "single ampersand", source: "true & false", wantColumn: 6},
+		{name: "single pipe", source: "true | false", wantColumn: 6},
+		{name: "single equals", source: "order.value = 5", wantColumn: 13},
+		{name: "unknown character", source: "order.value > 5 #", wantColumn: 17},
+		{name: "unterminated string", source: `user.tier == "gold`, wantColumn: 14},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tokenize(tt.source)
+			var compileErr *CompileError
+			if !errors.As(err, &compileErr) {
+				t.Fatalf("tokenize(%q) error = %v, want a *CompileError", tt.source, err)
+			}
+			if compileErr.Column != tt.wantColumn {
+				t.Errorf("tokenize(%q) error at column %d, want %d", tt.source, compileErr.Column, tt.wantColumn)
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package conditions
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var weekdays = map[string]time.Weekday{
+	"MON": time.Monday,
+	"TUE": time.Tuesday,
+	"WED": time.Wednesday,
+	"THU": time.Thursday,
+	"FRI": time.Friday,
+	"SAT": time.Saturday,
+	"SUN": time.Sunday,
+}
+
+type node interface {
+	column() int
+}
+
+type literal struct {
+	at    int
+	typ   Type
+	value interface{} // bool, float64, string or time.Weekday
+}
+
+type variableRef struct {
+	at   int
+	name string
+	def  variable
+}
+
+type list struct {
+	at    int
+	elems []node
+}
+
+type unary struct {
+	at      int
+	operand node
+}
+
+type binary struct {
+	at          int
+	op          token
+	left, right node
+}
+
+func (n *literal) column() int     { return n.at }
+func (n *variableRef) column() int { return n.at }
+func (n *list) column() int        { return n.at }
+func (n *unary) column() int       { return n.at }
+func (n *binary) column() int      { return n.at }
+
+// parser is a recursive descent parser over the grammar
+//
+//	or         = and { "||" and }
+//	and        = not { "&&" not }
+//	not        = "!" not | comparison
+//	comparison = primary [ ( "==" | "!=" | "<" | "<=" | ">" | ">=" | "in" ) primary ]
+//	primary    = number | "-" number | string | "true" | "false" | identifier | "(" or ")" | "[" [ primary { "," primary } ] "]"
+type parser struct {
+	tokens []token
+	next   int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) advance() token {
+	t := p.tokens[p.next]
+	if t.kind != tokenEOF {
+		p.next++
+	}
+	return t
+}
+
+func (p *parser) expect(kind tokenKind, what string) (token, error) {
+	t := p.advance()
+	if t.kind != kind {
+		return t, errorAt(t.column, "expected %s, found %s", what, t)
+	}
+	return t, nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOr {
+		op := p.advance()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binary{at: op.column, op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenAnd {
+		op := p.advance()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &binary{at: op.column, op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.peek().kind != tokenNot {
+		return p.parseComparison()
+	}
+	op := p.advance()
+	operand, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	return &unary{at: op.column, operand: operand}, nil
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if kind := p.peek().kind; !isComparison(kind) && kind != tokenIn {
+		return left, nil
+	}
+	op := p.advance()
+	right, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	return &binary{at: op.column, op: op, left: left, right: right}, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.advance()
+	switch t.kind {
+	case tokenNumber:
+		return parseNumber(t, false)
+	case tokenMinus:
+		number, err := p.expect(tokenNumber, "a number after -")
+		if err != nil {
+			return nil, err
+		}
+		n, err := parseNumber(number, true)
+		if err != nil {
+			return nil, err
+		}
+		n.at = t.column
+		return n, nil
+	case tokenString:
+		return &literal{at: t.column, typ: TypeString, value: t.text}, nil
+	case tokenTrue, tokenFalse:
+		return &literal{at: t.column, typ: TypeBool, value: t.kind == tokenTrue}, nil
+	case tokenIdent:
+		return resolveIdentifier(t)
+	case tokenLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenRParen, `")"`); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	case tokenLBracket:
+		return p.parseList(t)
+	}
+	return nil, errorAt(t.column, "expected a value, found %s", t)
+}
+
+func (p *parser) parseList(open token) (node, error) {
+	l := &list{at: open.column}
+	if p.peek().kind == tokenRBracket {
+		p.advance()
+		return l, nil
+	}
+	for {
+		elem, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		l.elems = append(l.elems, elem)
+
+		t := p.advance()
+		if t.kind == tokenRBracket {
+			return l, nil
+		}
+		if t.kind != tokenComma {
+			return nil, errorAt(t.column, `expected "," or "]" in list, found %s`, t)
+		}
+	}
+}
+
+func parseNumber(t token, negative bool) (*literal, error) {
+	value, err := strconv.ParseFloat(t.text, 64)
+	if err != nil {
+		return nil, errorAt(t.column, "invalid number %s", t.text)
+	}
+	if negative {
+		value = -value
+	}
+	return &literal{at: t.column, typ: TypeNumber, value: value}, nil
+}
+
+// resolveIdentifier turns an identifier into a weekday constant or a variable of the context
+func resolveIdentifier(t token) (node, error) {
+	if day, ok := weekdays[t.text]; ok {
+		return &literal{at: t.column, typ: TypeWeekday, value: day}, nil
+	}
+	if def, ok := variables[t.text]; ok {
+		return &variableRef{at: t.column, name: t.text, def: def}, nil
+	}
+
+	if _, ok := weekdays[strings.ToUpper(t.text)]; ok {
+		return nil, errorAt(t.column, "unknown identifier %s, weekdays are written in capitals as %s", t.text, strings.ToUpper(t.text))
+	}
+	object, field, dotted := strings.Cut(t.text, ".")
+	if !dotted {
+		return nil, errorAt(t.column, "unknown identifier %s, quote it if it is a string: %q", t.text, t.text)
+	}
+	fields := fieldsOf(object)
+	if len(fields) == 0 {
+		return nil, errorAt(t.column, "unknown object %s, expected one of %s", object, strings.Join(objectNames(), ", "))
+	}
+	return nil, errorAt(t.column, "unknown field %s of %s, expected one of %s", field, object, strings.Join(fields, ", "))
+}
+
+func fieldsOf(object string) []string {
+	var fields []string
+	for name := range variables {
+		if prefix, field, _ := strings.Cut(name, "."); prefix == object {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func objectNames() []string {
+	seen := make(map[string]bool)
+	var objects []string
+	for name := range variables {
+		object, _, _ := strings.Cut(name, ".")
+		if !seen[object] {
+			seen[object] = true
+			objects = append(objects, object)
+		}
+	}
+	sort.Strings(objects)
+	return objects
+}
+
+func isComparison(kind tokenKind) bool {
+	switch kind {
+	case tokenEq, tokenNe, tokenLt, tokenLe, tokenGt, tokenGe:
+		return true
+	}
+	return false
+}
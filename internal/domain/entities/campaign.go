@@ -26,8 +26,24 @@ var (
 	ErrInvalidStatus           = errors.New("invalid campaign status")
 	ErrInvalidBudget           = errors.New("budget must be greater than zero")
 	ErrGiftExceedsBudget       = errors.New("allocated gifts exceed budget")
+	ErrBudgetBelowSpent        = errors.New("budget cannot be lower than the amount already spent")
 	ErrNegativeMaxGiftsPerUser = errors.New("max gifts per user cannot be negative")
+	ErrInvalidUserLimitWindow  = errors.New("user limit window is invalid")
+	ErrInvalidProductRules     = errors.New("product rules are invalid")
+	ErrInvalidGeoTargeting     = errors.New("geo targeting is invalid")
+	ErrInvalidTimezone         = errors.New("campaign timezone is invalid")
+	ErrInvalidTimeWindow       = errors.New("campaign time window is invalid")
+	ErrInvalidPaymentRules     = errors.New("payment rules are invalid")
+	ErrInvalidChannelRules     = errors.New("channel rules are invalid")
+	ErrInvalidCombinability    = errors.New("combinability rules are invalid")
+	ErrInvalidConditions       = errors.New("conditions are invalid")
+	ErrInvalidRewardTiers      = errors.New("reward tiers are invalid")
+	ErrInvalidReferralRewards  = errors.New("referral rewards are invalid")
 	ErrInvalidActivation       = errors.New("campaign cannot be activated outside of the date range")
+	ErrInvalidTransition       = errors.New("campaign status transition is not allowed")
+	ErrInvalidSchedule         = errors.New("campaign cannot be scheduled after its end date")
+	ErrCampaignReadOnly        = errors.New("ended or archived campaigns cannot be edited")
+	ErrCampaignNotRunning      = errors.New("campaign is not running")
 )
 
 // CampaignValidator defines the interface for validating CampaignDTOs.
@@ -50,7 +66,7 @@ func (v *campaignValidator) Validate(campaign *CampaignDTO, currentTime time.Tim
 	}
 
 	switch campaign.Status {
-	case Active, Paused, Ended:
+	case Draft, Scheduled, Active, Paused, Ended, Archived:
 		// Valid statuses
 	default:
 		return fmt.Errorf("%w: received status '%s'", ErrInvalidStatus, campaign.Status)
@@ -60,17 +76,53 @@ func (v *campaignValidator) Validate(campaign *CampaignDTO, currentTime time.Tim
 		return fmt.Errorf("%w: budget (%f) is not greater than zero", ErrInvalidBudget, campaign.Budget)
 	}
 
-	// Assuming each gift has a fixed value, e.g., $10. Modify as per actual business logic.
-	const giftValue = 10.0
-	totalGiftValue := float64(campaign.AllocatedRewards) * giftValue
-	if totalGiftValue > campaign.Budget {
-		return fmt.Errorf("%w: total gift value (%f) exceeds budget (%f)", ErrGiftExceedsBudget, totalGiftValue, campaign.Budget)
+	limits := campaign.EligibilityCriteria.UserLimits
+	if limits.MaxPerCampaign < 0 || limits.MaxPerWindow < 0 || limits.MaxLifetime < 0 {
+		return fmt.Errorf("%w: user limits (%+v) contain a negative value", ErrNegativeMaxGiftsPerUser, limits)
+	}
+	if limits.MaxPerWindow > 0 && limits.WindowDays <= 0 {
+		return fmt.Errorf("%w: window days (%d) must be positive when max per window is set", ErrInvalidUserLimitWindow, limits.WindowDays)
+	}
+
+	if err := ValidateProductRules(campaign.EligibilityCriteria.ProductRules); err != nil {
+		return err
+	}
+
+	if err := ValidateGeoTargeting(campaign.EligibilityCriteria.GeoTargeting); err != nil {
+		return err
 	}
 
-	if campaign.MaxGiftsPerUser < 0 {
-		return fmt.Errorf("%w: max gifts per user (%d) is negative", ErrNegativeMaxGiftsPerUser, campaign.MaxGiftsPerUser)
+	if _, err := CampaignLocation(campaign); err != nil {
+		return err
+	}
+	if err := ValidateTimeWindows(campaign.EligibilityCriteria.TimeWindows); err != nil {
+		return err
+	}
+
+	if err := ValidatePaymentRules(campaign.EligibilityCriteria.PaymentRules); err != nil {
+		return err
+	}
+	if err := ValidateChannelRules(campaign.EligibilityCriteria.ChannelRules); err != nil {
+		return err
 	}
 
+	if err := ValidateCombinability(campaign.EligibilityCriteria.Combinability); err != nil {
+		return err
+	}
+
+	if _, err := CompileConditions(campaign.EligibilityCriteria.Conditions); err != nil {
+		return err
+	}
+
+	if err := ValidateRewardTiers(campaign); err != nil {
+		return err
+	}
+	if err := ValidateReferralRewards(campaign); err != nil {
+		return err
+	}
+
+	// Spending against the budget is tracked by the campaign budget ledger, using the real reward and shipping costs.
+
 	// If the campaign is active, ensure current time is within the campaign's date range.
 	if campaign.Status == Active {
 		if currentTime.Before(campaign.StartDate) || currentTime.After(campaign.EndDate) {
@@ -0,0 +1,45 @@
+package entities
+
+import (
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+// BudgetEntryType is the kind of cost booked against a campaign budget
+type BudgetEntryType string
+
+const (
+	BudgetEntryReward   BudgetEntryType = "reward"   // cost of the reward items of an allocation
+	BudgetEntryShipping BudgetEntryType = "shipping" // shipping cost of an allocation
+	BudgetEntryReversal BudgetEntryType = "reversal" // credit of everything booked for a cancelled allocation
+	BudgetEntryRefund   BudgetEntryType = "refund"   // credit of the part of an allocation which was never handed out
+)
+
+// BudgetEntry is a single line of the campaign budget ledger.
+// Debits have a positive amount, credits a negative one.
+type BudgetEntry struct {
+	CampaignID uuid.UUID       `json:"campaign_id"`
+	OrderID    int64           `json:"order_id"`
+	Type       BudgetEntryType `json:"type"`
+	Amount     float64         `json:"amount"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// OrderCredit works out what crediting an order books from its ledger entries, oldest first. The net amount is
+// credited, and the reward counts as released when a reward debit was booked since the last reversal. Rewards without
+// a cost book a zero debit, so they are released like any other. A refund only lowers the net amount, the rest of the
+// reward is still allocated.
+func OrderCredit(entries []*BudgetEntry) (float64, bool) {
+	net := 0.0
+	released := false
+	for _, entry := range entries {
+		net += entry.Amount
+		switch entry.Type {
+		case BudgetEntryReward:
+			released = true
+		case BudgetEntryReversal:
+			released = false
+		}
+	}
+	return net, released
+}
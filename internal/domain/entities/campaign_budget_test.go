@@ -0,0 +1,88 @@
+package entities
+
+import "testing"
+
+// budgetLedger books entries of a single order and keeps the allocated rewards counter of the campaign the way the
+// budget repository does
+type budgetLedger struct {
+	entries   []*BudgetEntry
+	allocated int
+}
+
+func (l *budgetLedger) debit(entryType BudgetEntryType, amount float64) {
+	l.entries = append(l.entries, &BudgetEntry{Type: entryType, Amount: amount})
+	if entryType == BudgetEntryReward {
+		l.allocated++
+	}
+}
+
+func (l *budgetLedger) credit() float64 {
+	net, released := OrderCredit(l.entries)
+	if net <= 0 && !released {
+		return 0
+	}
+	l.entries = append(l.entries, &BudgetEntry{Type: BudgetEntryReversal, Amount: -net})
+	if released && l.allocated > 0 {
+		l.allocated--
+	}
+	return net
+}
+
+func TestOrderCredit(t *testing.T) {
+	type step struct {
+		credit bool
+		entry  BudgetEntryType
+		amount float64
+	}
+	debit := func(entryType BudgetEntryType, amount float64) step { return step{entry: entryType, amount: amount} }
+	credit := step{credit: true}
+
+	tests := []struct {
+		name          string
+		steps         []step
+		wantCredited  float64
+		wantAllocated int
+	}{
+		{name: "zero cost reward cancelled", steps: []step{debit(BudgetEntryReward, 0), credit}, wantAllocated: 0},
+		{name: "zero cost reward kept", steps: []step{debit(BudgetEntryReward, 0)}, wantAllocated: 1},
+		{name: "reward cancelled", steps: []step{debit(BudgetEntryReward, 20), credit}, wantCredited: 20, wantAllocated: 0},
+		{name: "reward and shipping cancelled", steps: []step{debit(BudgetEntryReward, 20), debit(BudgetEntryShipping, 5), credit}, wantCredited: 25, wantAllocated: 0},
+		{name: "cancelled twice", steps: []step{debit(BudgetEntryReward, 0), credit, credit}, wantAllocated: 0},
+		{name: "nothing booked", steps: []step{credit}, wantAllocated: 0},
+		{name: "allocated again after a rollback", steps: []step{debit(BudgetEntryReward, 0), credit, debit(BudgetEntryReward, 0)}, wantAllocated: 1},
+		{name: "cancelled again after a rollback", steps: []step{debit(BudgetEntryReward, 10), credit, debit(BudgetEntryReward, 10), credit}, wantCredited: 10, wantAllocated: 0},
+		{
+			// a shipping debit retried after the cancellation is credited without releasing a second reward
+			name:          "late shipping debit",
+			steps:         []step{debit(BudgetEntryReward, 10), credit, debit(BudgetEntryShipping, 5), credit},
+			wantCredited:  5,
+			wantAllocated: 0,
+		},
+		{name: "refunded part kept", steps: []step{debit(BudgetEntryReward, 30), debit(BudgetEntryRefund, -20)}, wantAllocated: 1},
+		{name: "refunded part cancelled", steps: []step{debit(BudgetEntryReward, 30), debit(BudgetEntryRefund, -20), credit}, wantCredited: 10, wantAllocated: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ledger := &budgetLedger{}
+			credited := 0.0
+			for _, s := range tt.steps {
+				if s.credit {
+					credited = ledger.credit()
+					continue
+				}
+				ledger.debit(s.entry, s.amount)
+			}
+
+			if credited != tt.wantCredited {
+				t.Errorf("last credit = %.2f, want %.2f", credited, tt.wantCredited)
+			}
+			if ledger.allocated != tt.wantAllocated {
+				t.Errorf("allocated rewards = %d, want %d", ledger.allocated, tt.wantAllocated)
+			}
+			if net, _ := OrderCredit(ledger.entries); tt.steps[len(tt.steps)-1].credit && net != 0 {
+				t.Errorf("net amount after the credit = %.2f, want 0", net)
+			}
+		})
+	}
+}
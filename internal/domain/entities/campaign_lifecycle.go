@@ -0,0 +1,101 @@
+package entities
+
+import (
+	"fmt"
+	. "github.com/craftizmv/rewards/internal/data/dtos"
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+// CampaignStatusChange is an audit record of a campaign status transition
+type CampaignStatusChange struct {
+	CampaignID uuid.UUID `json:"campaign_id"`
+	From       Status    `json:"from"`
+	To         Status    `json:"to"`
+	Actor      string    `json:"actor"`            // who triggered the change, e.g. an admin id or "scheduler"
+	Reason     string    `json:"reason,omitempty"` // optional free text
+	ChangedAt  time.Time `json:"changed_at"`
+}
+
+// campaignTransitions lists the statuses reachable from every campaign status.
+//
+//	draft -> scheduled -> active <-> paused -> ended -> archived
+var campaignTransitions = map[Status][]Status{
+	Draft:     {Scheduled, Archived},
+	Scheduled: {Draft, Active, Ended},
+	Active:    {Paused, Ended},
+	Paused:    {Active, Ended},
+	Ended:     {Archived},
+	Archived:  {}, // archived campaigns are read only
+}
+
+// CanTransitionCampaign reports whether the state machine allows moving from one status to the other
+func CanTransitionCampaign(from, to Status) bool {
+	for _, next := range campaignTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionCampaign moves the campaign to the target status after checking the transition and its guards.
+// It returns the audit record of the change, the caller is responsible for persisting both.
+func TransitionCampaign(campaign *CampaignDTO, to Status, actor string, reason string, now time.Time) (*CampaignStatusChange, error) {
+	from := campaign.Status
+	if !CanTransitionCampaign(from, to) {
+		return nil, fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, from, to)
+	}
+
+	switch to {
+	case Scheduled:
+		if now.After(campaign.EndDate) {
+			return nil, fmt.Errorf("%w: end date (%s) has passed", ErrInvalidSchedule, campaign.EndDate)
+		}
+	case Active:
+		if now.Before(campaign.StartDate) || now.After(campaign.EndDate) {
+			return nil, fmt.Errorf("%w: current time (%s) is outside the campaign date range (%s - %s)", ErrInvalidActivation, now, campaign.StartDate, campaign.EndDate)
+		}
+	}
+
+	campaign.Status = to
+
+	return &CampaignStatusChange{
+		CampaignID: campaign.ID,
+		From:       from,
+		To:         to,
+		Actor:      actor,
+		Reason:     reason,
+		ChangedAt:  now,
+	}, nil
+}
+
+// ScheduledTransition returns the status the scheduler should move the campaign to at the given time,
+// ok is false when nothing is due.
+func ScheduledTransition(campaign *CampaignDTO, now time.Time) (Status, bool) {
+	switch campaign.Status {
+	case Scheduled:
+		if now.After(campaign.EndDate) {
+			return Ended, true
+		}
+		if !now.Before(campaign.StartDate) {
+			return Active, true
+		}
+	case Active, Paused:
+		if now.After(campaign.EndDate) {
+			return Ended, true
+		}
+	}
+	return "", false
+}
+
+// CheckCampaignRunning fails with ErrCampaignNotRunning unless the campaign is active and the order was placed within its dates
+func CheckCampaignRunning(campaign *CampaignDTO, placedAt time.Time) error {
+	if campaign.Status != Active {
+		return fmt.Errorf("%w: campaign %s is %s", ErrCampaignNotRunning, campaign.ID, campaign.Status)
+	}
+	if placedAt.Before(campaign.StartDate) || placedAt.After(campaign.EndDate) {
+		return fmt.Errorf("%w: order placed at %s is outside the campaign date range (%s - %s)", ErrCampaignNotRunning, placedAt, campaign.StartDate, campaign.EndDate)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	. "github.com/craftizmv/rewards/internal/data/dtos"
+)
+
+var ErrPromotionConflict = errors.New("reward conflicts with a promotion applied to the order")
+
+// ValidateCombinability checks that the combinability rules are consistent
+func ValidateCombinability(rules Combinability) error {
+	switch rules.OnConflict {
+	case "", ConflictReject:
+	case ConflictDowngrade:
+		if rules.DowngradeRewardGroupID <= 0 {
+			return fmt.Errorf("%w: a downgrade needs a downgrade reward group", ErrInvalidCombinability)
+		}
+		if rules.DowngradeRewardValue < 0 {
+			return fmt.Errorf("%w: downgrade reward value cannot be negative", ErrInvalidCombinability)
+		}
+	default:
+		return fmt.Errorf("%w: unknown conflict action %q", ErrInvalidCombinability, rules.OnConflict)
+	}
+	for _, combinable := range rules.CombinableWith {
+		if containsFold(rules.ConflictsWith, combinable) {
+			return fmt.Errorf("%w: %s is both combinable and conflicting", ErrInvalidCombinability, combinable)
+		}
+	}
+	return nil
+}
+
+// CheckCombinability fails with ErrPromotionConflict on the first applied promotion the reward conflicts with
+func CheckCombinability(rules Combinability, promotions []AppliedPromotion) error {
+	for _, promotion := range promotions {
+		if conflicts(rules, promotion) {
+			return fmt.Errorf("%w: %s %s", ErrPromotionConflict, promotion.Type, promotion.Code)
+		}
+	}
+	return nil
+}
+
+// DowngradesOnConflict reports whether a conflict downgrades the reward instead of rejecting it
+func DowngradesOnConflict(rules Combinability) bool {
+	return rules.OnConflict == ConflictDowngrade
+}
+
+func conflicts(rules Combinability, promotion AppliedPromotion) bool {
+	if matchesPromotion(rules.CombinableWith, promotion) {
+		return false
+	}
+	if matchesPromotion(rules.ConflictsWith, promotion) {
+		return true
+	}
+	if promotion.ExclusivityGroup != "" && containsFold(rules.ExclusivityGroups, promotion.ExclusivityGroup) {
+		return true
+	}
+	return rules.Exclusive
+}
+
+func matchesPromotion(values []string, promotion AppliedPromotion) bool {
+	return containsFold(values, promotion.Code) ||
+		(promotion.ExclusivityGroup != "" && containsFold(values, promotion.ExclusivityGroup))
+}
@@ -0,0 +1,74 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	. "github.com/craftizmv/rewards/internal/data/dtos"
+	"github.com/craftizmv/rewards/internal/domain/conditions"
+	"time"
+)
+
+var ErrConditionsNotMet = errors.New("conditions are not met")
+
+// CompileConditions compiles a condition expression, wrapping compile errors in ErrInvalidConditions.
+// An empty expression has no conditions and compiles to nil.
+func CompileConditions(expression string) (*conditions.Expression, error) {
+	if expression == "" {
+		return nil, nil
+	}
+	compiled, err := conditions.Compile(expression)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConditions, err)
+	}
+	return compiled, nil
+}
+
+// NewConditionContext collects the facts of the order, its user and the campaign.
+// The time is taken in the campaign timezone, so weekdays and hours are the ones the campaign sees.
+func NewConditionContext(campaign *CampaignDTO, order *OrderDTO, user *UserDetail, at time.Time) (*conditions.Context, error) {
+	loc, err := CampaignLocation(campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := &conditions.Context{
+		Order: conditions.OrderFacts{
+			Value:     order.OrderValue,
+			Quantity:  order.Quantity,
+			ItemCount: len(order.Items),
+			Channel:   string(order.Channel),
+		},
+		User: conditions.UserFacts{ID: order.UserID},
+		Campaign: conditions.CampaignFacts{
+			ID:          campaign.ID.String(),
+			Name:        campaign.Name,
+			RewardValue: campaign.RewardValue,
+		},
+		Now: at.In(loc),
+	}
+	if order.Payment != nil {
+		ctx.Order.PaymentType = string(order.Payment.Type)
+		ctx.Order.Issuer = order.Payment.Issuer
+	}
+	if order.Location != nil {
+		ctx.Order.Country = order.Location.Country
+		ctx.Order.State = order.Location.State
+		ctx.Order.City = order.Location.City
+	}
+	if user != nil {
+		ctx.User.Tier = user.Tier
+	}
+	return ctx, nil
+}
+
+// CheckConditions fails with ErrConditionsNotMet if the context does not satisfy the expression
+func CheckConditions(expression string, ctx *conditions.Context) error {
+	compiled, err := CompileConditions(expression)
+	if err != nil || compiled == nil {
+		return err
+	}
+	if !compiled.Eval(ctx) {
+		return fmt.Errorf("%w: %s", ErrConditionsNotMet, expression)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package entities
+
+import (
+	"errors"
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+var ErrAllocationDenied = errors.New("reward allocation denied by fraud screening")
+
+// FraudVerdict is the outcome of screening an allocation, ordered by severity
+type FraudVerdict string
+
+const (
+	FraudAllow  FraudVerdict = "allow"
+	FraudReview FraudVerdict = "review" // a person has to approve the allocation
+	FraudDeny   FraudVerdict = "deny"
+)
+
+var fraudSeverity = map[FraudVerdict]int{
+	FraudAllow:  0,
+	FraudReview: 1,
+	FraudDeny:   2,
+}
+
+// MoreSevere returns the more severe of the two verdicts
+func MoreSevere(a, b FraudVerdict) FraudVerdict {
+	if fraudSeverity[b] > fraudSeverity[a] {
+		return b
+	}
+	return a
+}
+
+// FraudSubject is what gets screened, the allocation of a reward to a user
+type FraudSubject struct {
+	UserID      string
+	Email       string
+	OrderID     int64
+	CampaignID  uuid.UUID
+	DeviceID    string
+	AddressHash string // see fraud.AddressFingerprint, empty when the address is unknown
+}
+
+// FraudSignal is raised by a detector that found something suspicious
+type FraudSignal struct {
+	Detector string       `json:"detector"`
+	Verdict  FraudVerdict `json:"verdict"`
+	Reason   string       `json:"reason"`
+}
+
+// FraudResult is the combined outcome of all detectors
+type FraudResult struct {
+	Verdict FraudVerdict  `json:"verdict"`
+	Signals []FraudSignal `json:"signals"`
+}
+
+// FingerprintKind is an attribute allocations are counted by
+type FingerprintKind string
+
+const (
+	FingerprintUser    FingerprintKind = "user"
+	FingerprintDevice  FingerprintKind = "device"
+	FingerprintAddress FingerprintKind = "address"
+	FingerprintEmail   FingerprintKind = "email"
+)
+
+// RewardFingerprint records who asked for a reward from where, one per screened allocation
+type RewardFingerprint struct {
+	OrderID     int64
+	CampaignID  uuid.UUID
+	UserID      string
+	DeviceID    string
+	AddressHash string
+	CreatedAt   time.Time
+}
+
+// BlocklistEntry blocks a user, device, address or email from getting rewards
+type BlocklistEntry struct {
+	Kind      FingerprintKind `json:"kind"`
+	Value     string          `json:"value"`
+	Reason    string          `json:"reason"`
+	CreatedAt time.Time       `json:"created_at"`
+}
@@ -0,0 +1,165 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	. "github.com/craftizmv/rewards/internal/data/dtos"
+	"math"
+	"strings"
+)
+
+var ErrOutsideTargetArea = errors.New("location is outside the target area")
+
+const earthRadiusKm = 6371.0
+
+// IsGeoTargetingSet reports whether the targeting restricts locations at all
+func IsGeoTargetingSet(targeting GeoTargeting) bool {
+	return len(targeting.AllowedCountries) > 0 || len(targeting.DeniedCountries) > 0 ||
+		len(targeting.AllowedStates) > 0 || len(targeting.DeniedStates) > 0 ||
+		len(targeting.AllowedPostalCodes) > 0 || len(targeting.DeniedPostalCodes) > 0 ||
+		len(targeting.Areas) > 0
+}
+
+// ValidateGeoTargeting checks that the areas of the targeting are well-formed and that no list has a blank entry,
+// a blank postal code prefix would match every location
+func ValidateGeoTargeting(targeting GeoTargeting) error {
+	lists := []struct {
+		name   string
+		values []string
+	}{
+		{"allowed countries", targeting.AllowedCountries},
+		{"denied countries", targeting.DeniedCountries},
+		{"allowed states", targeting.AllowedStates},
+		{"denied states", targeting.DeniedStates},
+		{"allowed postal codes", targeting.AllowedPostalCodes},
+		{"denied postal codes", targeting.DeniedPostalCodes},
+	}
+	for _, list := range lists {
+		for i, value := range list.values {
+			if strings.TrimSpace(value) == "" {
+				return fmt.Errorf("%w: entry %d of the %s is empty", ErrInvalidGeoTargeting, i, list.name)
+			}
+		}
+	}
+
+	for i, area := range targeting.Areas {
+		switch {
+		case area.Center != nil && len(area.Polygon) > 0:
+			return fmt.Errorf("%w: area %d has both a center and a polygon", ErrInvalidGeoTargeting, i)
+		case area.Center != nil:
+			if area.RadiusKm <= 0 {
+				return fmt.Errorf("%w: area %d needs a positive radius", ErrInvalidGeoTargeting, i)
+			}
+			if !isValidGeoPoint(*area.Center) {
+				return fmt.Errorf("%w: area %d has an invalid center", ErrInvalidGeoTargeting, i)
+			}
+		case len(area.Polygon) >= 3:
+			for _, point := range area.Polygon {
+				if !isValidGeoPoint(point) {
+					return fmt.Errorf("%w: area %d has an invalid polygon point", ErrInvalidGeoTargeting, i)
+				}
+			}
+		default:
+			return fmt.Errorf("%w: area %d needs a center and radius, or a polygon of at least 3 points", ErrInvalidGeoTargeting, i)
+		}
+	}
+	return nil
+}
+
+// CheckGeoTargeting fails with ErrOutsideTargetArea if the location is not targeted
+func CheckGeoTargeting(targeting GeoTargeting, location *UserLocation) error {
+	if !IsGeoTargetingSet(targeting) {
+		return nil
+	}
+	if location == nil {
+		return fmt.Errorf("%w: location is unknown", ErrOutsideTargetArea)
+	}
+
+	if containsFold(targeting.DeniedCountries, location.Country) {
+		return fmt.Errorf("%w: country %s is denied", ErrOutsideTargetArea, location.Country)
+	}
+	if len(targeting.AllowedCountries) > 0 && !containsFold(targeting.AllowedCountries, location.Country) {
+		return fmt.Errorf("%w: country %s is not allowed", ErrOutsideTargetArea, location.Country)
+	}
+	if containsFold(targeting.DeniedStates, location.State) {
+		return fmt.Errorf("%w: state %s is denied", ErrOutsideTargetArea, location.State)
+	}
+	if len(targeting.AllowedStates) > 0 && !containsFold(targeting.AllowedStates, location.State) {
+		return fmt.Errorf("%w: state %s is not allowed", ErrOutsideTargetArea, location.State)
+	}
+	if hasPrefix(targeting.DeniedPostalCodes, location.PostalCode) {
+		return fmt.Errorf("%w: postal code %s is denied", ErrOutsideTargetArea, location.PostalCode)
+	}
+	if len(targeting.AllowedPostalCodes) > 0 && !hasPrefix(targeting.AllowedPostalCodes, location.PostalCode) {
+		return fmt.Errorf("%w: postal code %s is not allowed", ErrOutsideTargetArea, location.PostalCode)
+	}
+
+	if len(targeting.Areas) == 0 {
+		return nil
+	}
+	if location.Latitude == nil || location.Longitude == nil {
+		return fmt.Errorf("%w: location has no coordinates", ErrOutsideTargetArea)
+	}
+	point := GeoPoint{Latitude: *location.Latitude, Longitude: *location.Longitude}
+	for _, area := range targeting.Areas {
+		if isInArea(area, point) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: (%f, %f) is in none of the areas", ErrOutsideTargetArea, point.Latitude, point.Longitude)
+}
+
+func isInArea(area GeoArea, point GeoPoint) bool {
+	if area.Center != nil {
+		return distanceKm(*area.Center, point) <= area.RadiusKm
+	}
+	return isInPolygon(area.Polygon, point)
+}
+
+// distanceKm is the great-circle distance between two points, using the haversine formula
+func distanceKm(a, b GeoPoint) float64 {
+	lat1 := a.Latitude * math.Pi / 180
+	lat2 := b.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
+
+// isInPolygon casts a ray from the point and counts the edges it crosses
+func isInPolygon(polygon []GeoPoint, point GeoPoint) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		pi, pj := polygon[i], polygon[j]
+		if (pi.Latitude > point.Latitude) != (pj.Latitude > point.Latitude) &&
+			point.Longitude < (pj.Longitude-pi.Longitude)*(point.Latitude-pi.Latitude)/(pj.Latitude-pi.Latitude)+pi.Longitude {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func isValidGeoPoint(point GeoPoint) bool {
+	return point.Latitude >= -90 && point.Latitude <= 90 && point.Longitude >= -180 && point.Longitude <= 180
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), strings.TrimSpace(value)) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasPrefix(prefixes []string, value string) bool {
+	value = strings.ReplaceAll(strings.ToUpper(value), " ", "")
+	for _, prefix := range prefixes {
+		prefix = strings.ReplaceAll(strings.ToUpper(prefix), " ", "")
+		if prefix != "" && strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
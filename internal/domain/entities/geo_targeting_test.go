@@ -0,0 +1,123 @@
+package entities
+
+import (
+	"errors"
+	. "github.com/craftizmv/rewards/internal/data/dtos"
+	"testing"
+)
+
+func TestHasPrefix(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefixes []string
+		value    string
+		want     bool
+	}{
+		{name: "exact code", prefixes: []string{"10115"}, value: "10115", want: true},
+		{name: "prefix", prefixes: []string{"101"}, value: "10115", want: true},
+		{name: "second prefix", prefixes: []string{"80", "101"}, value: "10115", want: true},
+		{name: "no prefix", prefixes: []string{"80"}, value: "10115", want: false},
+		{name: "longer than the code", prefixes: []string{"101150"}, value: "10115", want: false},
+		{name: "ignores case", prefixes: []string{"sw1a"}, value: "SW1A 1AA", want: true},
+		{name: "ignores spaces", prefixes: []string{"SW1A1"}, value: "sw1a 1aa", want: true},
+		{name: "spaces in the prefix", prefixes: []string{"SW1A 1"}, value: "SW1A1AA", want: true},
+		{name: "empty prefix matches nothing", prefixes: []string{""}, value: "10115", want: false},
+		{name: "blank prefix matches nothing", prefixes: []string{"  "}, value: "10115", want: false},
+		{name: "empty code", prefixes: []string{"101"}, value: "", want: false},
+		{name: "no prefixes", prefixes: nil, value: "10115", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasPrefix(tt.prefixes, tt.value); got != tt.want {
+				t.Errorf("hasPrefix(%q, %q) = %v, want %v", tt.prefixes, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckGeoTargeting(t *testing.T) {
+	latitude, longitude := 52.52, 13.405
+	berlin := &UserLocation{Country: "DE", State: "BE", PostalCode: "10115", Latitude: &latitude, Longitude: &longitude}
+	munich := &UserLocation{Country: "DE", State: "BY", PostalCode: "80331"}
+
+	tests := []struct {
+		name      string
+		targeting GeoTargeting
+		location  *UserLocation
+		wantErr   bool
+	}{
+		{name: "not targeted", targeting: GeoTargeting{}, location: nil},
+		{name: "unknown location", targeting: GeoTargeting{AllowedCountries: []string{"DE"}}, location: nil, wantErr: true},
+		{name: "allowed country ignores case", targeting: GeoTargeting{AllowedCountries: []string{"de"}}, location: berlin},
+		{name: "country not allowed", targeting: GeoTargeting{AllowedCountries: []string{"FR"}}, location: berlin, wantErr: true},
+		{name: "denied country", targeting: GeoTargeting{DeniedCountries: []string{"DE"}}, location: berlin, wantErr: true},
+		{name: "denied state", targeting: GeoTargeting{DeniedStates: []string{"BY"}}, location: munich, wantErr: true},
+		{name: "allowed postal prefix", targeting: GeoTargeting{AllowedPostalCodes: []string{"10"}}, location: berlin},
+		{name: "postal prefix not allowed", targeting: GeoTargeting{AllowedPostalCodes: []string{"10"}}, location: munich, wantErr: true},
+		{name: "denied postal prefix", targeting: GeoTargeting{DeniedPostalCodes: []string{"80"}}, location: munich, wantErr: true},
+		{name: "denied postal prefix elsewhere", targeting: GeoTargeting{DeniedPostalCodes: []string{"80"}}, location: berlin},
+		{name: "empty allowed prefix allows nothing", targeting: GeoTargeting{AllowedPostalCodes: []string{""}}, location: berlin, wantErr: true},
+		{name: "empty denied prefix denies nothing", targeting: GeoTargeting{DeniedPostalCodes: []string{""}}, location: berlin},
+		{
+			name:      "within radius",
+			targeting: GeoTargeting{Areas: []GeoArea{{Center: &GeoPoint{Latitude: 52.5, Longitude: 13.4}, RadiusKm: 10}}},
+			location:  berlin,
+		},
+		{
+			name:      "outside radius",
+			targeting: GeoTargeting{Areas: []GeoArea{{Center: &GeoPoint{Latitude: 48.14, Longitude: 11.58}, RadiusKm: 10}}},
+			location:  berlin,
+			wantErr:   true,
+		},
+		{
+			name: "within polygon",
+			targeting: GeoTargeting{Areas: []GeoArea{{Polygon: []GeoPoint{
+				{Latitude: 52, Longitude: 13}, {Latitude: 53, Longitude: 13}, {Latitude: 53, Longitude: 14}, {Latitude: 52, Longitude: 14},
+			}}}},
+			location: berlin,
+		},
+		{
+			name:      "area without coordinates",
+			targeting: GeoTargeting{Areas: []GeoArea{{Center: &GeoPoint{Latitude: 48.14, Longitude: 11.58}, RadiusKm: 10}}},
+			location:  munich,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckGeoTargeting(tt.targeting, tt.location)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckGeoTargeting() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrOutsideTargetArea) {
+				t.Errorf("CheckGeoTargeting() error = %v, want ErrOutsideTargetArea", err)
+			}
+		})
+	}
+}
+
+func TestValidateGeoTargetingRejectsBlankPostalPrefix(t *testing.T) {
+	tests := []struct {
+		name      string
+		targeting GeoTargeting
+		wantErr   bool
+	}{
+		{name: "prefixes", targeting: GeoTargeting{AllowedPostalCodes: []string{"10", "80"}}},
+		{name: "empty allowed prefix", targeting: GeoTargeting{AllowedPostalCodes: []string{"10", ""}}, wantErr: true},
+		{name: "blank denied prefix", targeting: GeoTargeting{DeniedPostalCodes: []string{" "}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGeoTargeting(tt.targeting)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateGeoTargeting() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidGeoTargeting) {
+				t.Errorf("ValidateGeoTargeting() error = %v, want ErrInvalidGeoTargeting", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+// DefaultChoiceWindow is how long a user has to pick a gift when the reward group does not say
+const DefaultChoiceWindow = 72 * time.Hour
+
+var (
+	ErrGiftChoiceNotOwned = errors.New("gift choice belongs to another user")
+	ErrGiftChoiceClosed   = errors.New("gift choice is no longer open")
+	ErrNotAGiftOption     = errors.New("product is not one of the gift options")
+)
+
+// GiftChoiceStatus represents where a gift choice is in its lifecycle
+type GiftChoiceStatus string
+
+const (
+	GiftChoicePending   GiftChoiceStatus = "pending"   // Waiting for the user to pick a product
+	GiftChoiceSettling  GiftChoiceStatus = "settling"  // Claimed while the picked or default product ships
+	GiftChoiceChosen    GiftChoiceStatus = "chosen"    // The user picked a product and it was shipped
+	GiftChoiceDefaulted GiftChoiceStatus = "defaulted" // The deadline passed and the default product was shipped
+	GiftChoiceCancelled GiftChoiceStatus = "cancelled" // The reward was cancelled before anything was shipped
+)
+
+// GiftChoice is a choose-one reward waiting for the user to pick the product which ships
+type GiftChoice struct {
+	ID               int64            `json:"id"`
+	OrderID          int64            `json:"order_id"`
+	UserID           string           `json:"user_id"`
+	CampaignID       uuid.UUID        `json:"campaign_id"`
+	RewardGroupID    int64            `json:"reward_group_id"`
+	Options          []int64          `json:"options"` // Products of the reward group which were in stock when the choice was offered
+	DefaultProductID int64            `json:"default_product_id"`
+	ChosenProductID  *int64           `json:"chosen_product_id,omitempty"`
+	Status           GiftChoiceStatus `json:"status"`
+	Deadline         time.Time        `json:"deadline"`
+	CreatedAt        time.Time        `json:"created_at"`
+	DecidedAt        *time.Time       `json:"decided_at,omitempty"`
+}
+
+// NewGiftChoice creates a pending choice between the options. The default product of the group is used when it is
+// one of the options, the first option otherwise.
+func NewGiftChoice(group *RewardGroup, orderID int64, userID string, campaignID uuid.UUID, options []int64, at time.Time) (*GiftChoice, error) {
+	if len(options) == 0 {
+		return nil, fmt.Errorf("reward group %d has no product to choose from", group.ID)
+	}
+
+	choice := &GiftChoice{
+		OrderID:          orderID,
+		UserID:           userID,
+		CampaignID:       campaignID,
+		RewardGroupID:    group.ID,
+		Options:          options,
+		DefaultProductID: options[0],
+		Status:           GiftChoicePending,
+		Deadline:         at.Add(group.ChoiceWindow()),
+		CreatedAt:        at,
+	}
+	if group.DefaultProductID != nil && choice.IsOption(*group.DefaultProductID) {
+		choice.DefaultProductID = *group.DefaultProductID
+	}
+	return choice, nil
+}
+
+// IsOption tells whether the product can be picked
+func (c *GiftChoice) IsOption(productID int64) bool {
+	for _, option := range c.Options {
+		if option == productID {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckChoice makes sure the user can still pick the product
+func (c *GiftChoice) CheckChoice(userID string, productID int64, at time.Time) error {
+	if c.UserID != userID {
+		return fmt.Errorf("%w: %d", ErrGiftChoiceNotOwned, c.ID)
+	}
+	if c.Status != GiftChoicePending {
+		return fmt.Errorf("%w: choice %d is %s", ErrGiftChoiceClosed, c.ID, c.Status)
+	}
+	if at.After(c.Deadline) {
+		return fmt.Errorf("%w: choice %d was due at %s", ErrGiftChoiceClosed, c.ID, c.Deadline.Format(time.RFC3339))
+	}
+	if !c.IsOption(productID) {
+		return fmt.Errorf("%w: product %d, choice %d", ErrNotAGiftOption, productID, c.ID)
+	}
+	return nil
+}
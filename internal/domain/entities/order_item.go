@@ -5,6 +5,8 @@ import "errors"
 // OrderItem represents an item in the order
 type OrderItem struct {
 	ProductID int64   // Unique identifier for the product
+	SKU       string  // Stock keeping unit of the product
+	Category  string  // Category the product belongs to
 	Name      string  // Product name
 	Quantity  int     // Number of units ordered
 	Price     float64 // Price per unit
@@ -7,12 +7,22 @@ import (
 
 // OrderRewardItem represents the association between an Order and a RewardItem.
 type OrderRewardItem struct {
-	OrderID       int64      `json:"order_id"`                // Foreign key referencing the Order
-	RewardItemID  int64      `json:"reward_item_id"`          // Foreign key referencing the RewardItem
-	ShipmentID    *int64     `json:"shipment_id,omitempty"`   // Foreign key referencing the Shipment (if applicable)
-	AllocatedDate time.Time  `json:"allocated_date"`          // Date when the reward was allocated to the order
-	IsRedeemed    *bool      `json:"is_redeemed"`             // Indicates if the reward item has been redeemed
-	RedeemedDate  *time.Time `json:"redeemed_date,omitempty"` // Date when the reward was redeemed, if applicable
+	ID               int64      `json:"id"`                           // Identifier of the allocated reward item
+	OrderID          int64      `json:"order_id"`                     // Foreign key referencing the Order
+	RewardItemID     int64      `json:"reward_item_id"`               // Foreign key referencing the RewardItem
+	ShipmentID       *int64     `json:"shipment_id,omitempty"`        // Foreign key referencing the Shipment (if applicable)
+	IssuedRef        *string    `json:"issued_reference,omitempty"`   // Reference of the coupon or wallet credit issued for a non-physical reward item
+	AllocatedDate    time.Time  `json:"allocated_date"`               // Date when the reward was allocated to the order
+	IsRedeemed       *bool      `json:"is_redeemed"`                  // Indicates if the reward item has been redeemed
+	RedeemedDate     *time.Time `json:"redeemed_date,omitempty"`      // Date when the reward was redeemed, if applicable
+	UserID           string     `json:"user_id"`                      // User the reward was allocated to, the only one who can redeem it
+	Value            float64    `json:"value"`                        // Redeemable value, zero for rewards which are redeemed at once
+	RedeemedAmount   float64    `json:"redeemed_amount"`              // Part of the value redeemed so far
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`         // Date after which the reward cannot be redeemed
+	ExpiredAt        *time.Time `json:"expired_at,omitempty"`         // Date the expiration job expired the reward
+	InventoryItemIDs []int64    `json:"inventory_item_ids,omitempty"` // Inventory items blocked for a product reward
+	ShipmentRef      *string    `json:"shipment_reference,omitempty"` // Confirmation of the shipper once the product shipped
+	ShippedAt        *time.Time `json:"shipped_at,omitempty"`         // Date the product was handed to the shipper
 }
 
 // Validate checks the OrderRewardItem against business invariants
@@ -46,6 +56,16 @@ func (ori *OrderRewardItem) Validate() error {
 		return errors.New("shipment ID, if present, must be a valid positive integer")
 	}
 
+	// 6. A shipped item is never issued as a discount and the other way around
+	if ori.ShipmentID != nil && ori.IssuedRef != nil {
+		return errors.New("a reward item is either shipped or issued, not both")
+	}
+
+	// 7. Partial redemptions never go beyond the value of the reward
+	if ori.RedeemedAmount < 0 || (ori.Value > 0 && ori.RedeemedAmount > ori.Value) {
+		return errors.New("redeemed amount must be between 0 and the value of the reward")
+	}
+
 	// If all validations pass, return nil
 	return nil
 }
@@ -0,0 +1,96 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	. "github.com/craftizmv/rewards/internal/data/dtos"
+)
+
+var (
+	ErrPaymentNotAccepted = errors.New("payment method is not accepted by the campaign")
+	ErrChannelNotAccepted = errors.New("sales channel is not accepted by the campaign")
+)
+
+// ValidatePaymentRules checks that no payment type or issuer is both allowed and excluded
+func ValidatePaymentRules(rules PaymentRules) error {
+	for _, allowed := range rules.AllowedTypes {
+		for _, excluded := range rules.ExcludedTypes {
+			if allowed == excluded {
+				return fmt.Errorf("%w: payment type %s is both allowed and excluded", ErrInvalidPaymentRules, allowed)
+			}
+		}
+	}
+	for _, allowed := range rules.AllowedIssuers {
+		if containsFold(rules.ExcludedIssuers, allowed) {
+			return fmt.Errorf("%w: issuer %s is both allowed and excluded", ErrInvalidPaymentRules, allowed)
+		}
+	}
+	return nil
+}
+
+// ValidateChannelRules checks that no channel is both allowed and excluded
+func ValidateChannelRules(rules ChannelRules) error {
+	for _, allowed := range rules.AllowedChannels {
+		if containsChannel(rules.ExcludedChannels, allowed) {
+			return fmt.Errorf("%w: channel %s is both allowed and excluded", ErrInvalidChannelRules, allowed)
+		}
+	}
+	return nil
+}
+
+// CheckPaymentRules fails with ErrPaymentNotAccepted if the payment does not satisfy the rules,
+// an unknown payment only passes campaigns without allow lists
+func CheckPaymentRules(rules PaymentRules, payment *PaymentMethod) error {
+	if payment == nil {
+		if len(rules.AllowedTypes) > 0 || len(rules.AllowedIssuers) > 0 {
+			return fmt.Errorf("%w: payment method is unknown", ErrPaymentNotAccepted)
+		}
+		return nil
+	}
+
+	for _, excluded := range rules.ExcludedTypes {
+		if payment.Type == excluded {
+			return fmt.Errorf("%w: payment type %s is excluded", ErrPaymentNotAccepted, payment.Type)
+		}
+	}
+	if len(rules.AllowedTypes) > 0 {
+		allowed := false
+		for _, paymentType := range rules.AllowedTypes {
+			if payment.Type == paymentType {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("%w: payment type %s is not allowed", ErrPaymentNotAccepted, payment.Type)
+		}
+	}
+	if containsFold(rules.ExcludedIssuers, payment.Issuer) {
+		return fmt.Errorf("%w: issuer %s is excluded", ErrPaymentNotAccepted, payment.Issuer)
+	}
+	if len(rules.AllowedIssuers) > 0 && !containsFold(rules.AllowedIssuers, payment.Issuer) {
+		return fmt.Errorf("%w: issuer %s is not allowed", ErrPaymentNotAccepted, payment.Issuer)
+	}
+	return nil
+}
+
+// CheckChannelRules fails with ErrChannelNotAccepted if the channel does not satisfy the rules,
+// an unknown channel only passes campaigns without an allow list
+func CheckChannelRules(rules ChannelRules, channel SalesChannel) error {
+	if containsChannel(rules.ExcludedChannels, channel) {
+		return fmt.Errorf("%w: channel %s is excluded", ErrChannelNotAccepted, channel)
+	}
+	if len(rules.AllowedChannels) > 0 && !containsChannel(rules.AllowedChannels, channel) {
+		return fmt.Errorf("%w: channel %q is not allowed", ErrChannelNotAccepted, channel)
+	}
+	return nil
+}
+
+func containsChannel(channels []SalesChannel, channel SalesChannel) bool {
+	for _, c := range channels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+var (
+	ErrInvalidPoints      = errors.New("points must be greater than zero")
+	ErrInsufficientPoints = errors.New("not enough points")
+)
+
+// PointsTransactionType is what moved the points of a user
+type PointsTransactionType string
+
+const (
+	PointsAccrue  PointsTransactionType = "accrue"  // Points earned with an order
+	PointsReverse PointsTransactionType = "reverse" // Points of an accrual taken back because the order was cancelled
+	PointsExpire  PointsTransactionType = "expire"  // Points of an accrual which were not spent in time
+	PointsRedeem  PointsTransactionType = "redeem"  // Points spent by the user
+)
+
+// Counter accounts of the points ledger, the points of a user live in UserPointsAccount
+const (
+	PointsIssuedAccount   = "issued"
+	PointsExpiredAccount  = "expired"
+	PointsRedeemedAccount = "redeemed"
+)
+
+// UserPointsAccount returns the ledger account holding the points of the user
+func UserPointsAccount(userID string) string {
+	return "user:" + userID
+}
+
+// PointsEntry is one side of a points transaction, credits are positive and debits negative
+type PointsEntry struct {
+	Account string `json:"account"`
+	Amount  int64  `json:"amount"`
+}
+
+// PointsTransaction moves points between two accounts of the double-entry ledger, its entries always sum to zero.
+// Reversals and expiries take their points from the accrual in SourceID.
+type PointsTransaction struct {
+	ID           int64                 `json:"id"`
+	UserID       string                `json:"user_id"`
+	Type         PointsTransactionType `json:"type"`
+	Points       int64                 `json:"points"`
+	Reference    string                `json:"reference"` // Unique key, posting the same reference twice has no effect
+	OrderID      *int64                `json:"order_id,omitempty"`
+	CampaignID   *uuid.UUID            `json:"campaign_id,omitempty"`
+	RewardItemID *int64                `json:"reward_item_id,omitempty"`
+	SourceID     *int64                `json:"source_id,omitempty"`
+	ExpiresAt    *time.Time            `json:"expires_at,omitempty"` // When the points of an accrual expire, never when nil
+	Remaining    int64                 `json:"remaining,omitempty"`  // Points of an accrual which were neither reversed nor expired
+	CreatedAt    time.Time             `json:"created_at"`
+	Entries      []PointsEntry         `json:"entries,omitempty"`
+}
+
+// PointsBalance is what a user holds, along with the totals per transaction type
+type PointsBalance struct {
+	UserID   string `json:"user_id"`
+	Balance  int64  `json:"balance"` // Can drop below zero when points which were already spent are clawed back
+	Accrued  int64  `json:"accrued"`
+	Reversed int64  `json:"reversed"`
+	Expired  int64  `json:"expired"`
+	Redeemed int64  `json:"redeemed"`
+}
+
+// NewPointsTransaction creates a transaction with the two balanced entries of its type
+func NewPointsTransaction(userID string, txType PointsTransactionType, points int64, reference string) (*PointsTransaction, error) {
+	if points <= 0 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidPoints, points)
+	}
+
+	user := UserPointsAccount(userID)
+	var from, to string
+	switch txType {
+	case PointsAccrue:
+		from, to = PointsIssuedAccount, user
+	case PointsReverse:
+		from, to = user, PointsIssuedAccount
+	case PointsExpire:
+		from, to = user, PointsExpiredAccount
+	case PointsRedeem:
+		from, to = user, PointsRedeemedAccount
+	default:
+		return nil, fmt.Errorf("invalid points transaction type: %s", txType)
+	}
+
+	return &PointsTransaction{
+		UserID:    userID,
+		Type:      txType,
+		Points:    points,
+		Reference: reference,
+		Entries: []PointsEntry{
+			{Account: from, Amount: -points},
+			{Account: to, Amount: points},
+		},
+	}, nil
+}
+
+// PointsSourceReference returns the reference of the reversal or expiry of an accrual, an accrual is reversed or expired at most once
+func PointsSourceReference(txType PointsTransactionType, accrualID int64) string {
+	return fmt.Sprintf("points:%s:%d", txType, accrualID)
+}
+
+// CheckRedeemable fails with ErrInsufficientPoints if the balance does not cover the redemption
+func CheckRedeemable(balance int64, points int64) error {
+	if points > balance {
+		return fmt.Errorf("%w: %d requested, %d available", ErrInsufficientPoints, points, balance)
+	}
+	return nil
+}
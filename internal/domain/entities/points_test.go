@@ -0,0 +1,174 @@
+package entities
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewPointsTransaction(t *testing.T) {
+	user := UserPointsAccount("u-1")
+
+	tests := []struct {
+		name     string
+		txType   PointsTransactionType
+		points   int64
+		wantFrom string
+		wantTo   string
+		wantErr  error
+	}{
+		{name: "accrue", txType: PointsAccrue, points: 100, wantFrom: PointsIssuedAccount, wantTo: user},
+		{name: "reverse", txType: PointsReverse, points: 100, wantFrom: user, wantTo: PointsIssuedAccount},
+		{name: "expire", txType: PointsExpire, points: 40, wantFrom: user, wantTo: PointsExpiredAccount},
+		{name: "redeem", txType: PointsRedeem, points: 25, wantFrom: user, wantTo: PointsRedeemedAccount},
+		{name: "zero points", txType: PointsAccrue, points: 0, wantErr: ErrInvalidPoints},
+		{name: "negative points", txType: PointsRedeem, points: -5, wantErr: ErrInvalidPoints},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := NewPointsTransaction("u-1", tt.txType, tt.points, "ref")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewPointsTransaction() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if len(tx.Entries) != 2 {
+				t.Fatalf("NewPointsTransaction() has %d entries, want 2", len(tx.Entries))
+			}
+			from, to := tx.Entries[0], tx.Entries[1]
+			if from.Account != tt.wantFrom || from.Amount != -tt.points {
+				t.Errorf("debit = %+v, want %d from %s", from, -tt.points, tt.wantFrom)
+			}
+			if to.Account != tt.wantTo || to.Amount != tt.points {
+				t.Errorf("credit = %+v, want %d to %s", to, tt.points, tt.wantTo)
+			}
+		})
+	}
+
+	if _, err := NewPointsTransaction("u-1", "gift", 10, "ref"); err == nil {
+		t.Error("NewPointsTransaction() accepted an unknown transaction type")
+	}
+}
+
+func TestPointsLedgerBalance(t *testing.T) {
+	type posting struct {
+		txType PointsTransactionType
+		points int64
+	}
+
+	tests := []struct {
+		name         string
+		postings     []posting
+		wantBalance  int64
+		wantAccounts map[string]int64
+	}{
+		{
+			name:         "accrual",
+			postings:     []posting{{PointsAccrue, 100}},
+			wantBalance:  100,
+			wantAccounts: map[string]int64{PointsIssuedAccount: -100},
+		},
+		{
+			name:         "accrual partly redeemed",
+			postings:     []posting{{PointsAccrue, 100}, {PointsRedeem, 30}},
+			wantBalance:  70,
+			wantAccounts: map[string]int64{PointsIssuedAccount: -100, PointsRedeemedAccount: 30},
+		},
+		{
+			name:         "accrual reversed",
+			postings:     []posting{{PointsAccrue, 100}, {PointsReverse, 100}},
+			wantBalance:  0,
+			wantAccounts: map[string]int64{PointsIssuedAccount: 0},
+		},
+		{
+			name:         "rest of an accrual expired",
+			postings:     []posting{{PointsAccrue, 100}, {PointsRedeem, 60}, {PointsExpire, 40}},
+			wantBalance:  0,
+			wantAccounts: map[string]int64{PointsIssuedAccount: -100, PointsRedeemedAccount: 60, PointsExpiredAccount: 40},
+		},
+		{
+			// points which were spent already are still clawed back, the user owes them
+			name:         "spent accrual reversed",
+			postings:     []posting{{PointsAccrue, 100}, {PointsRedeem, 80}, {PointsReverse, 100}},
+			wantBalance:  -80,
+			wantAccounts: map[string]int64{PointsIssuedAccount: 0, PointsRedeemedAccount: 80},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accounts := make(map[string]int64)
+			for i, p := range tt.postings {
+				tx, err := NewPointsTransaction("u-1", p.txType, p.points, "ref")
+				if err != nil {
+					t.Fatalf("NewPointsTransaction() error = %v", err)
+				}
+
+				var sum int64
+				for _, entry := range tx.Entries {
+					accounts[entry.Account] += entry.Amount
+					sum += entry.Amount
+				}
+				if sum != 0 {
+					t.Fatalf("posting %d (%s) does not balance, its entries sum to %d", i, p.txType, sum)
+				}
+			}
+
+			if got := accounts[UserPointsAccount("u-1")]; got != tt.wantBalance {
+				t.Errorf("user balance = %d, want %d", got, tt.wantBalance)
+			}
+			for account, want := range tt.wantAccounts {
+				if got := accounts[account]; got != want {
+					t.Errorf("%s balance = %d, want %d", account, got, want)
+				}
+			}
+
+			var total int64
+			for _, amount := range accounts {
+				total += amount
+			}
+			if total != 0 {
+				t.Errorf("ledger does not balance, accounts sum to %d", total)
+			}
+		})
+	}
+}
+
+func TestCheckRedeemable(t *testing.T) {
+	tests := []struct {
+		name    string
+		balance int64
+		points  int64
+		wantErr error
+	}{
+		{name: "less than the balance", balance: 100, points: 40},
+		{name: "whole balance", balance: 100, points: 100},
+		{name: "more than the balance", balance: 100, points: 101, wantErr: ErrInsufficientPoints},
+		{name: "negative balance", balance: -20, points: 1, wantErr: ErrInsufficientPoints},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckRedeemable(tt.balance, tt.points); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckRedeemable(%d, %d) error = %v, want %v", tt.balance, tt.points, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPointsSourceReference(t *testing.T) {
+	reverse := PointsSourceReference(PointsReverse, 7)
+	expire := PointsSourceReference(PointsExpire, 7)
+
+	if reverse != "points:reverse:7" {
+		t.Errorf("PointsSourceReference(reverse, 7) = %s, want points:reverse:7", reverse)
+	}
+	if reverse == expire {
+		t.Errorf("reversal and expiry of accrual 7 share the reference %s", reverse)
+	}
+	if reverse == PointsSourceReference(PointsReverse, 8) {
+		t.Errorf("reversals of accruals 7 and 8 share the reference %s", reverse)
+	}
+}
@@ -0,0 +1,85 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	. "github.com/craftizmv/rewards/internal/data/dtos"
+)
+
+var ErrProductRulesNotMet = errors.New("order does not meet the product rules")
+
+// categoryTotal is what the order contains of a single category
+type categoryTotal struct {
+	quantity int
+	amount   float64
+}
+
+// ValidateProductRules checks that the rules can be satisfied at all
+func ValidateProductRules(rules ProductRules) error {
+	for _, requirement := range rules.RequiredCategories {
+		if requirement.Category == "" {
+			return fmt.Errorf("%w: required category has no name", ErrInvalidProductRules)
+		}
+		if requirement.MinQuantity < 0 || requirement.MinAmount < 0 {
+			return fmt.Errorf("%w: minimums of category %s cannot be negative", ErrInvalidProductRules, requirement.Category)
+		}
+		for _, excluded := range rules.ExcludedCategories {
+			if excluded == requirement.Category {
+				return fmt.Errorf("%w: category %s is both required and excluded", ErrInvalidProductRules, excluded)
+			}
+		}
+	}
+	for _, required := range rules.RequiredProductIDs {
+		for _, excluded := range rules.ExcludedProductIDs {
+			if excluded == required {
+				return fmt.Errorf("%w: product %d is both required and excluded", ErrInvalidProductRules, excluded)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckProductRules fails with ErrProductRulesNotMet if the line items do not satisfy the rules
+func CheckProductRules(rules ProductRules, items []OrderItemDTO) error {
+	products := make(map[int64]bool, len(items))
+	categories := make(map[string]*categoryTotal)
+	for _, item := range items {
+		products[item.ProductID] = true
+		total, ok := categories[item.Category]
+		if !ok {
+			total = &categoryTotal{}
+			categories[item.Category] = total
+		}
+		total.quantity += item.Quantity
+		total.amount += float64(item.Quantity) * item.Price
+	}
+
+	for _, productID := range rules.ExcludedProductIDs {
+		if products[productID] {
+			return fmt.Errorf("%w: product %d is excluded", ErrProductRulesNotMet, productID)
+		}
+	}
+	for _, category := range rules.ExcludedCategories {
+		if _, ok := categories[category]; ok {
+			return fmt.Errorf("%w: category %s is excluded", ErrProductRulesNotMet, category)
+		}
+	}
+	for _, productID := range rules.RequiredProductIDs {
+		if !products[productID] {
+			return fmt.Errorf("%w: product %d is missing", ErrProductRulesNotMet, productID)
+		}
+	}
+	for _, requirement := range rules.RequiredCategories {
+		total, ok := categories[requirement.Category]
+		if !ok {
+			return fmt.Errorf("%w: category %s is missing", ErrProductRulesNotMet, requirement.Category)
+		}
+		if total.quantity < requirement.MinQuantity {
+			return fmt.Errorf("%w: %d units of category %s, %d required", ErrProductRulesNotMet, total.quantity, requirement.Category, requirement.MinQuantity)
+		}
+		if total.amount < requirement.MinAmount {
+			return fmt.Errorf("%w: %.2f spent on category %s, %.2f required", ErrProductRulesNotMet, total.amount, requirement.Category, requirement.MinAmount)
+		}
+	}
+	return nil
+}
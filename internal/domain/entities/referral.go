@@ -0,0 +1,110 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	. "github.com/craftizmv/rewards/internal/data/dtos"
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+var (
+	ErrSelfReferral              = errors.New("users cannot refer themselves")
+	ErrNotReferralCampaign       = errors.New("campaign does not reward referrals")
+	ErrRefereeAlreadyReferred    = errors.New("referee was already referred in the campaign")
+	ErrInvalidReferralAllocation = errors.New("reward cannot be allocated for the referral campaign")
+)
+
+// ReferralStatus represents where a referral is in its lifecycle
+type ReferralStatus string
+
+const (
+	ReferralPending  ReferralStatus = "pending"  // Attributed, the rewards are being allocated or wait for a review
+	ReferralRewarded ReferralStatus = "rewarded" // Both sides got their rewards
+	ReferralReversed ReferralStatus = "reversed" // The order of the referee was cancelled or returned and the rewards taken back
+)
+
+// Referral attributes the first qualifying order of a referee to the referrer
+type Referral struct {
+	ID         int64          `json:"id"`
+	CampaignID uuid.UUID      `json:"campaign_id"`
+	ReferrerID string         `json:"referrer_id"`
+	RefereeID  string         `json:"referee_id"`
+	OrderID    int64          `json:"order_id"` // First qualifying order of the referee
+	Status     ReferralStatus `json:"status"`
+	CreatedAt  time.Time      `json:"created_at"`
+	RewardedAt *time.Time     `json:"rewarded_at,omitempty"`
+	ReversedAt *time.Time     `json:"reversed_at,omitempty"`
+}
+
+// NewReferral creates a pending referral of the order
+func NewReferral(campaignID uuid.UUID, referrerID, refereeID string, orderID int64, at time.Time) (*Referral, error) {
+	if referrerID == "" || refereeID == "" {
+		return nil, errors.New("referrer and referee are required")
+	}
+	if referrerID == refereeID {
+		return nil, fmt.Errorf("%w: %s", ErrSelfReferral, referrerID)
+	}
+	return &Referral{
+		CampaignID: campaignID,
+		ReferrerID: referrerID,
+		RefereeID:  refereeID,
+		OrderID:    orderID,
+		Status:     ReferralPending,
+		CreatedAt:  at,
+	}, nil
+}
+
+// ReferrerOrderID is the order id the reward of the referrer is allocated against. The referrer has no order of
+// its own, the negated referral id keeps the allocation apart from the rewards of the referee order.
+func (r *Referral) ReferrerOrderID() int64 {
+	return -r.ID
+}
+
+// Reversible tells whether a cancellation or return of the referee order still takes the rewards back.
+// Until the order is delivered there is no return window yet and the referral can always be reversed.
+// A pending referral may already have rewarded one of its sides, so it is reversed as well.
+func (r *Referral) Reversible(returnWindowEnd time.Time, at time.Time) bool {
+	if r.Status != ReferralRewarded && r.Status != ReferralPending {
+		return false
+	}
+	return returnWindowEnd.IsZero() || !at.After(returnWindowEnd)
+}
+
+// IsReferralCampaign tells whether the campaign pays out referrals instead of order rewards
+func IsReferralCampaign(campaign *CampaignDTO) bool {
+	referral := campaign.EligibilityCriteria.Referral
+	return referral.ReferrerRewardGroupID > 0 || referral.RefereeRewardGroupID > 0
+}
+
+// ValidateReferralRewards checks the reward groups of a referral campaign, which has no value tiers
+func ValidateReferralRewards(campaign *CampaignDTO) error {
+	referral := campaign.EligibilityCriteria.Referral
+	if referral.ReferrerRewardGroupID < 0 || referral.RefereeRewardGroupID < 0 {
+		return fmt.Errorf("%w: referral reward groups cannot be negative", ErrInvalidReferralRewards)
+	}
+	if IsReferralCampaign(campaign) && len(campaign.EligibilityCriteria.Tiers) > 0 {
+		return fmt.Errorf("%w: referral campaigns cannot have value tiers", ErrInvalidReferralRewards)
+	}
+	return nil
+}
+
+// CheckReferralAllocation fails with ErrInvalidReferralAllocation if a referral campaign is asked for an order reward,
+// or for a reward group which is not one of its referral reward groups
+func CheckReferralAllocation(campaign *CampaignDTO, rewardGroupID int64, referral bool) error {
+	if !IsReferralCampaign(campaign) {
+		if referral {
+			return fmt.Errorf("%w: campaign %s is not a referral campaign", ErrNotReferralCampaign, campaign.ID)
+		}
+		return nil
+	}
+	if !referral {
+		return fmt.Errorf("%w: campaign %s only rewards referrals", ErrInvalidReferralAllocation, campaign.ID)
+	}
+
+	rewards := campaign.EligibilityCriteria.Referral
+	if rewardGroupID != rewards.ReferrerRewardGroupID && rewardGroupID != rewards.RefereeRewardGroupID {
+		return fmt.Errorf("%w: reward group %d is not a referral reward of campaign %s", ErrInvalidReferralAllocation, rewardGroupID, campaign.ID)
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package entities
+
+import "time"
+
+// ExpiringReward is an allocated reward item which is not redeemed and reaches its expiry date
+type ExpiringReward struct {
+	Reward *OrderRewardItem `json:"reward"`
+	Type   RewardItemType   `json:"type"`
+	// DueAt is the earliest of the expiry dates of the allocated item, the reward item and the reward group
+	DueAt time.Time `json:"due_at"`
+}
+
+// HoldsInventory tells whether the reward still holds inventory items blocked for a product which was never shipped
+func (r *ExpiringReward) HoldsInventory() bool {
+	return r.Type == RewardTypeProduct && r.Reward.ShippedAt == nil && len(r.Reward.InventoryItemIDs) > 0
+}
+
+// DaysLeft returns the whole days left until the reward expires
+func (r *ExpiringReward) DaysLeft(now time.Time) int {
+	return int(r.DueAt.Sub(now).Hours() / 24)
+}
@@ -0,0 +1,49 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHoldsInventory(t *testing.T) {
+	shippedAt := time.Date(2024, time.March, 15, 10, 0, 0, 0, time.UTC)
+	confirmation := "SHIP-1"
+
+	tests := []struct {
+		name   string
+		reward *ExpiringReward
+		want   bool
+	}{
+		{
+			name:   "product never shipped",
+			reward: &ExpiringReward{Type: RewardTypeProduct, Reward: &OrderRewardItem{RewardItemID: 7, InventoryItemIDs: []int64{41}}},
+			want:   true,
+		},
+		{
+			name: "product shipped",
+			reward: &ExpiringReward{Type: RewardTypeProduct, Reward: &OrderRewardItem{
+				RewardItemID: 7, InventoryItemIDs: []int64{41}, ShipmentRef: &confirmation, ShippedAt: &shippedAt,
+			}},
+			want: false,
+		},
+		{
+			// rows recorded before the blocked items were stored have nothing to release
+			name:   "product without blocked items",
+			reward: &ExpiringReward{Type: RewardTypeProduct, Reward: &OrderRewardItem{RewardItemID: 7}},
+			want:   false,
+		},
+		{
+			name:   "voucher",
+			reward: &ExpiringReward{Type: RewardTypeVoucher, Reward: &OrderRewardItem{RewardItemID: 7, InventoryItemIDs: []int64{41}}},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.reward.HoldsInventory(); got != tt.want {
+				t.Errorf("HoldsInventory() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
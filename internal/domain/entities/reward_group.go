@@ -7,12 +7,26 @@ import (
 
 // TODO : ReVisit the reward eligibility criteria if needed here.
 
+// ErrInvalidRewardCatalog is returned for a reward group, reward item or mapping which fails its validation
+var ErrInvalidRewardCatalog = errors.New("invalid reward catalog entry")
+
 // RewardGroup represents a reward that may contain multiple reward items
 type RewardGroup struct {
 	ID         int64      `json:"id"`
 	Name       string     `json:"name"`
 	ExpiresAt  *time.Time `json:"expires_at"`
 	CampaignID int64      `json:"campaign_id"`
+
+	ChooseOne         bool   `json:"choose_one"`                   // The user picks one of the products instead of getting all of them
+	DefaultProductID  *int64 `json:"default_product_id,omitempty"` // Product shipped when the user does not choose in time, the first option when nil
+	ChoiceWindowHours int    `json:"choice_window_hours"`          // How long the user has to choose, DefaultChoiceWindow when zero
+}
+
+// RewardGroupCatalog is a reward group along with the reward items and the products it hands out
+type RewardGroupCatalog struct {
+	*RewardGroup
+	Items      []*RewardItem `json:"items"`
+	ProductIDs []int64       `json:"product_ids"`
 }
 
 // NewRewardGroup creates a new RewardGroup with necessary validations
@@ -36,3 +50,36 @@ func NewRewardGroup(rewardGroupID int64, name string, expiresAt *time.Time) (*Re
 
 	return reward, nil
 }
+
+// Validate checks the RewardGroup against business invariants, the ID is left out as it is assigned on creation
+func (rg *RewardGroup) Validate() error {
+	if rg.Name == "" {
+		return errors.New("reward name must not be empty")
+	}
+	if rg.CampaignID < 0 {
+		return errors.New("campaign ID must not be negative")
+	}
+	if rg.ChoiceWindowHours < 0 {
+		return errors.New("choice window must not be negative")
+	}
+	if rg.DefaultProductID != nil && *rg.DefaultProductID <= 0 {
+		return errors.New("default product ID must be valid")
+	}
+	if !rg.ChooseOne && (rg.DefaultProductID != nil || rg.ChoiceWindowHours > 0) {
+		return errors.New("default product and choice window need a choose-one reward group")
+	}
+	return nil
+}
+
+// OffersChoice tells whether the user picks one of the products, a group with a single product simply ships it
+func (rg *RewardGroup) OffersChoice(productIDs []int64) bool {
+	return rg.ChooseOne && len(productIDs) > 1
+}
+
+// ChoiceWindow returns how long the user has to pick a product
+func (rg *RewardGroup) ChoiceWindow() time.Duration {
+	if rg.ChoiceWindowHours > 0 {
+		return time.Duration(rg.ChoiceWindowHours) * time.Hour
+	}
+	return DefaultChoiceWindow
+}
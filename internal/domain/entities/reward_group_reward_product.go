@@ -1,7 +1,30 @@
 package entities
 
-// RewardGroupRewardProduct represents the association between a RewardGroup and a RewardItem.
+import (
+	"errors"
+)
+
+// RewardGroupRewardProduct represents the association between a RewardGroup and a Product.
 type RewardGroupRewardProduct struct {
 	RewardGroupID string `json:"reward_group_id"` // Foreign key referencing the RewardGroup
-	ProductID     string `json:"product_id"`      // Foreign key referencing the RewardItem
+	ProductID     string `json:"product_id"`      // Foreign key referencing the Product
+}
+
+// NewRewardGroupRewardProduct Constructor to create a new RewardGroupRewardProduct
+func NewRewardGroupRewardProduct(rewardGroupID, productID string) *RewardGroupRewardProduct {
+	return &RewardGroupRewardProduct{
+		RewardGroupID: rewardGroupID,
+		ProductID:     productID,
+	}
+}
+
+// Validate checks the RewardGroupRewardProduct against business invariants
+func (rgrp *RewardGroupRewardProduct) Validate() error {
+	if rgrp.RewardGroupID == "" {
+		return errors.New("reward group ID must be set")
+	}
+	if rgrp.ProductID == "" {
+		return errors.New("product ID must be set")
+	}
+	return nil
 }
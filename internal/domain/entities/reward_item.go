@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/craftizmv/rewards/internal/data/dtos"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -14,23 +17,37 @@ const (
 	RewardTypeProduct  RewardItemType = "product"  // A physical product
 	RewardTypeDiscount RewardItemType = "discount" // A discount or coupon
 	RewardTypeVoucher  RewardItemType = "voucher"  // A reward voucher or code
+	RewardTypePoints   RewardItemType = "points"   // Loyalty points credited to the points ledger of the user
 )
 
 // RewardConditions is a custom type to hold conditions in a flexible structure like JSON
 type RewardConditions map[string]interface{}
 
+// ConditionExpressionKey is the key of RewardConditions holding the condition expression of the reward
+const ConditionExpressionKey = "expression"
+
+// legacyWeekendsKey is the key of RewardConditions stored rewards limited their validity to weekends with,
+// false or a missing key put no limit on the day
+const legacyWeekendsKey = "valid_on_weekends"
+
+// weekendsRule is the expression the legacy valid_on_weekends key stands for
+const weekendsRule = "now.weekday in [SAT, SUN]"
+
 // RewardItem represents the structure for reward items in the reward system.
 type RewardItem struct {
-	RewardItemID     string           `json:"reward_item_id"`              // Unique identifier for the reward item
-	Type             RewardItemType   `json:"type"`                        // Type of reward (Discount, Product, Voucher)
-	ItemID           *string          `json:"item_id,omitempty"`           // ItemID if the reward is related to a product
-	DiscountAmount   *float64         `json:"discount_amount,omitempty"`   // Discount amount (only for Discount type)
-	VoucherCode      *string          `json:"voucher_code,omitempty"`      // Voucher code (only for Voucher type)
-	ProductID        *string          `json:"product_id,omitempty"`        // ProductID if the reward item is a product
-	ExpirationDate   *time.Time       `json:"expiration_date,omitempty"`   // Expiration date of the reward
-	RewardConditions RewardConditions `json:"reward_conditions,omitempty"` // Flexible structure to store reward conditions
-	IsActive         bool             `json:"is_active"`                   // Indicates if the reward is currently active
-	Metadata         json.RawMessage  `json:"metadata,omitempty"`          // Any additional metadata as a JSON object
+	RewardItemID     string            `json:"reward_item_id"`              // Unique identifier for the reward item
+	Type             RewardItemType    `json:"type"`                        // Type of reward (Discount, Product, Voucher)
+	ItemID           *string           `json:"item_id,omitempty"`           // ItemID if the reward is related to a product
+	DiscountAmount   *float64          `json:"discount_amount,omitempty"`   // Discount amount (only for Discount type)
+	DiscountKind     dtos.DiscountKind `json:"discount_kind,omitempty"`     // Coupon or store credit (only for Discount type), a coupon when empty
+	VoucherCode      *string           `json:"voucher_code,omitempty"`      // Static voucher code (only for Voucher type), unset when the codes come from the voucher pools of the reward group
+	Points           *int64            `json:"points,omitempty"`            // Points credited to the user (only for Points type)
+	ProductID        *string           `json:"product_id,omitempty"`        // ProductID if the reward item is a product
+	ExpirationDate   *time.Time        `json:"expiration_date,omitempty"`   // Expiration date of the reward
+	Cost             float64           `json:"cost"`                        // What allocating the reward item costs the business
+	RewardConditions RewardConditions  `json:"reward_conditions,omitempty"` // Flexible structure to store reward conditions
+	IsActive         bool              `json:"is_active"`                   // Indicates if the reward is currently active
+	Metadata         json.RawMessage   `json:"metadata,omitempty"`          // Any additional metadata as a JSON object
 }
 
 // NewRewardItem A constructor function to create a new reward item with default values
@@ -49,6 +66,19 @@ func (r *RewardItem) SetDiscount(discount float64) {
 	}
 }
 
+// IsPhysical tells whether the reward item has to be taken from the inventory and shipped
+func (r *RewardItem) IsPhysical() bool {
+	return r.Type == RewardTypeProduct
+}
+
+// IssuedDiscountKind returns how the discount is handed out
+func (r *RewardItem) IssuedDiscountKind() dtos.DiscountKind {
+	if r.DiscountKind == "" {
+		return dtos.DiscountKindCoupon
+	}
+	return r.DiscountKind
+}
+
 // SetVoucherCode Example method to set the voucher code for Voucher reward type
 func (r *RewardItem) SetVoucherCode(code string) {
 	if r.Type == RewardTypeVoucher {
@@ -76,31 +106,48 @@ func (r *RewardItem) Deactivate() {
 	r.IsActive = false
 }
 
-// Validate checks the RewardItem against business invariants
-func (r *RewardItem) Validate() error {
+// CheckAvailable checks whether the reward item can still be handed out
+func (r *RewardItem) CheckAvailable(at time.Time) error {
 	// Check if reward is active
 	if !r.IsActive {
 		return errors.New("reward item is inactive")
 	}
 
 	// Check if the reward has expired (if expiration date is set)
-	if r.ExpirationDate != nil && time.Now().After(*r.ExpirationDate) {
+	if r.ExpirationDate != nil && at.After(*r.ExpirationDate) {
 		return errors.New("reward item is expired")
 	}
 
+	return nil
+}
+
+// Validate checks the RewardItem against business invariants, an inactive or expired item can still be valid
+func (r *RewardItem) Validate() error {
+	if r.Cost < 0 {
+		return errors.New("reward item cost cannot be negative")
+	}
+
 	// Validate based on the type of reward
 	switch r.Type {
 	case RewardTypeDiscount:
 		if r.DiscountAmount == nil || *r.DiscountAmount <= 0 {
 			return errors.New("discount amount must be set and greater than 0 for discount type reward")
 		}
+		if kind := r.IssuedDiscountKind(); kind != dtos.DiscountKindCoupon && kind != dtos.DiscountKindStoreCredit {
+			return fmt.Errorf("invalid discount kind: %s", kind)
+		}
 	case RewardTypeProduct:
 		if r.ProductID == nil && r.ItemID == nil {
 			return errors.New("product or item ID must be set for product type reward")
 		}
 	case RewardTypeVoucher:
-		if r.VoucherCode == nil || *r.VoucherCode == "" {
-			return errors.New("voucher code must be set for voucher type reward")
+		// a pool-backed voucher leaves the code unset, every allocation gets its own code out of the pools
+		if r.VoucherCode != nil && strings.TrimSpace(*r.VoucherCode) == "" {
+			return errors.New("voucher code cannot be empty, leave it unset for a pool-backed voucher type reward")
+		}
+	case RewardTypePoints:
+		if r.Points == nil || *r.Points <= 0 {
+			return errors.New("points must be set and greater than 0 for points type reward")
 		}
 	default:
 		return fmt.Errorf("invalid reward type: %s", r.Type)
@@ -115,29 +162,82 @@ func (r *RewardItem) Validate() error {
 	return nil
 }
 
-// validateConditions checks the reward-specific conditions (optional implementation)
+// validateConditions checks that the conditions of the reward compile
 func (r *RewardItem) validateConditions() error {
-	if r.RewardConditions != nil {
-		// Example condition: check if a minimum purchase amount is required
-		if minPurchase, ok := r.RewardConditions["min_purchase_amount"]; ok {
-			if minPurchaseAmount, ok := minPurchase.(float64); !ok || minPurchaseAmount <= 0 {
-				return errors.New("invalid minimum purchase amount condition")
-			}
+	expression, err := r.ConditionExpression()
+	if err != nil {
+		return err
+	}
+	_, err = CompileConditions(expression)
+	return err
+}
+
+// ConditionExpression returns the expression an order has to satisfy to get the reward, empty when there is none.
+// The legacy min_purchase_amount key is folded in as an order.value comparison, and a true legacy valid_on_weekends
+// key as a now.weekday rule evaluated in the campaign timezone.
+func (r *RewardItem) ConditionExpression() (string, error) {
+	if r.RewardConditions == nil {
+		return "", nil
+	}
+
+	var expression string
+	if raw, ok := r.RewardConditions[ConditionExpressionKey]; ok {
+		if expression, ok = raw.(string); !ok {
+			return "", fmt.Errorf("%w: %s must be a string", ErrInvalidConditions, ConditionExpressionKey)
 		}
+	}
 
-		// Add other condition validations as needed
-		// Example: check if a condition "valid_on_weekends" is true
-		if validOnWeekends, ok := r.RewardConditions["valid_on_weekends"]; ok {
-			if validOnWeekendsBool, ok := validOnWeekends.(bool); ok && validOnWeekendsBool {
-				// Only allow the reward on weekends (Saturday, Sunday)
-				weekday := time.Now().Weekday()
-				if weekday != time.Saturday && weekday != time.Sunday {
-					return errors.New("reward can only be used on weekends")
-				}
-			}
+	if minPurchase, ok := r.RewardConditions["min_purchase_amount"]; ok {
+		minPurchaseAmount, ok := minPurchase.(float64)
+		if !ok || minPurchaseAmount <= 0 {
+			return "", fmt.Errorf("%w: invalid minimum purchase amount", ErrInvalidConditions)
 		}
+		expression = andExpression("order.value >= "+strconv.FormatFloat(minPurchaseAmount, 'f', -1, 64), expression)
 	}
 
-	// All conditions passed
+	weekends, err := r.validOnWeekends()
+	if err != nil {
+		return "", err
+	}
+	if weekends {
+		expression = andExpression(weekendsRule, expression)
+	}
+
+	return expression, nil
+}
+
+// NormalizeConditions rewrites the legacy valid_on_weekends key into the condition expression before the reward is
+// written, so rewards stored with it can be updated and new ones only carry expressions for time based rules
+func (r *RewardItem) NormalizeConditions() error {
+	weekends, err := r.validOnWeekends()
+	if err != nil {
+		return err
+	}
+	if weekends {
+		expression, _ := r.RewardConditions[ConditionExpressionKey].(string)
+		r.RewardConditions[ConditionExpressionKey] = andExpression(weekendsRule, expression)
+	}
+	delete(r.RewardConditions, legacyWeekendsKey)
 	return nil
 }
+
+// validOnWeekends reads the legacy valid_on_weekends key, only true limits the reward to weekends
+func (r *RewardItem) validOnWeekends() (bool, error) {
+	raw, ok := r.RewardConditions[legacyWeekendsKey]
+	if !ok {
+		return false, nil
+	}
+	weekends, ok := raw.(bool)
+	if !ok {
+		return false, fmt.Errorf("%w: %s must be a boolean", ErrInvalidConditions, legacyWeekendsKey)
+	}
+	return weekends, nil
+}
+
+// andExpression prepends the rule to the expression
+func andExpression(rule, expression string) string {
+	if expression == "" {
+		return rule
+	}
+	return fmt.Sprintf("%s && (%s)", rule, expression)
+}
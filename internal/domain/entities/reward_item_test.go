@@ -0,0 +1,78 @@
+package entities
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConditionExpression(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions RewardConditions
+		want       string
+		wantErr    error
+	}{
+		{name: "no conditions", conditions: nil, want: ""},
+		{name: "expression", conditions: RewardConditions{"expression": "order.value >= 50"}, want: "order.value >= 50"},
+		{name: "minimum purchase", conditions: RewardConditions{"min_purchase_amount": 50.0}, want: "order.value >= 50"},
+		{name: "weekends only", conditions: RewardConditions{"valid_on_weekends": true}, want: "now.weekday in [SAT, SUN]"},
+		{name: "not limited to weekends", conditions: RewardConditions{"valid_on_weekends": false}, want: ""},
+		{
+			name:       "weekends and expression",
+			conditions: RewardConditions{"valid_on_weekends": true, "expression": `user.tier == "gold"`},
+			want:       `now.weekday in [SAT, SUN] && (user.tier == "gold")`,
+		},
+		{name: "weekends not a boolean", conditions: RewardConditions{"valid_on_weekends": "yes"}, wantErr: ErrInvalidConditions},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := &RewardItem{RewardConditions: tt.conditions}
+			got, err := item.ConditionExpression()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ConditionExpression() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ConditionExpression() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeConditions(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions RewardConditions
+		want       RewardConditions
+	}{
+		{name: "weekends only", conditions: RewardConditions{"valid_on_weekends": true}, want: RewardConditions{"expression": "now.weekday in [SAT, SUN]"}},
+		{name: "not limited to weekends", conditions: RewardConditions{"valid_on_weekends": false}, want: RewardConditions{}},
+		{
+			name:       "weekends and expression",
+			conditions: RewardConditions{"valid_on_weekends": true, "expression": "order.quantity > 1"},
+			want:       RewardConditions{"expression": "now.weekday in [SAT, SUN] && (order.quantity > 1)"},
+		},
+		{name: "expression only", conditions: RewardConditions{"expression": "order.quantity > 1"}, want: RewardConditions{"expression": "order.quantity > 1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := &RewardItem{RewardConditions: tt.conditions}
+			before, err := item.ConditionExpression()
+			if err != nil {
+				t.Fatalf("ConditionExpression() error = %v", err)
+			}
+			if err := item.NormalizeConditions(); err != nil {
+				t.Fatalf("NormalizeConditions() error = %v", err)
+			}
+
+			if len(item.RewardConditions) != len(tt.want) || item.RewardConditions["expression"] != tt.want["expression"] {
+				t.Errorf("NormalizeConditions() = %v, want %v", item.RewardConditions, tt.want)
+			}
+			// the stored reward keeps applying to the same orders
+			if after, _ := item.ConditionExpression(); after != before {
+				t.Errorf("expression after NormalizeConditions() = %q, want %q", after, before)
+			}
+		})
+	}
+}
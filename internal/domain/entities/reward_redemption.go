@@ -0,0 +1,86 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrRewardNotOwned            = errors.New("reward belongs to another user")
+	ErrRewardAlreadyRedeemed     = errors.New("reward is already redeemed")
+	ErrRewardExpired             = errors.New("reward has expired")
+	ErrInvalidRedemptionAmount   = errors.New("invalid redemption amount")
+	ErrRedemptionExceedsBalance  = errors.New("redemption exceeds the reward balance")
+	ErrRedemptionReferenceReused = errors.New("redemption reference is already used")
+)
+
+// RewardRedemption is one redemption of an allocated reward item, a reward with a value can be redeemed in several parts
+type RewardRedemption struct {
+	ID                int64     `json:"id"`
+	OrderRewardItemID int64     `json:"reward_id"`          // Allocated reward item which is redeemed
+	UserID            string    `json:"user_id"`            // User redeeming the reward
+	OrderID           *int64    `json:"order_id,omitempty"` // Checkout order the reward is redeemed on, if any
+	Reference         string    `json:"reference"`          // Unique reference of the redemption, redeeming twice with it has no effect
+	Amount            float64   `json:"amount"`             // Part of the value redeemed, zero for rewards without a value
+	RedeemedAt        time.Time `json:"redeemed_at"`
+}
+
+// RewardBalance tells what is left of an allocated reward item
+type RewardBalance struct {
+	Reward      *OrderRewardItem    `json:"reward"`
+	Balance     float64             `json:"balance"`
+	Redemptions []*RewardRedemption `json:"redemptions"`
+}
+
+// Balance returns the part of the value which can still be redeemed
+func (ori *OrderRewardItem) Balance() float64 {
+	if ori.IsRedeemed != nil && *ori.IsRedeemed {
+		return 0
+	}
+	if balance := ori.Value - ori.RedeemedAmount; balance > 0 {
+		return balance
+	}
+	return 0
+}
+
+// Redeem checks the redemption against the reward and applies it.
+// A zero amount redeems the whole balance, a reward without a value is always redeemed at once.
+func (ori *OrderRewardItem) Redeem(redemption *RewardRedemption) error {
+	if ori.UserID != redemption.UserID {
+		return fmt.Errorf("%w: reward %d", ErrRewardNotOwned, ori.ID)
+	}
+	if ori.IsRedeemed != nil && *ori.IsRedeemed {
+		return fmt.Errorf("%w: reward %d", ErrRewardAlreadyRedeemed, ori.ID)
+	}
+	if ori.ExpiredAt != nil {
+		return fmt.Errorf("%w: reward %d expired on %s", ErrRewardExpired, ori.ID, ori.ExpiredAt.Format(time.RFC3339))
+	}
+	if ori.ExpiresAt != nil && !redemption.RedeemedAt.Before(*ori.ExpiresAt) {
+		return fmt.Errorf("%w: reward %d expired on %s", ErrRewardExpired, ori.ID, ori.ExpiresAt.Format(time.RFC3339))
+	}
+
+	if ori.Value > 0 {
+		balance := ori.Balance()
+		if redemption.Amount == 0 {
+			redemption.Amount = balance
+		}
+		if redemption.Amount < 0 {
+			return fmt.Errorf("%w: %.2f", ErrInvalidRedemptionAmount, redemption.Amount)
+		}
+		if redemption.Amount > balance {
+			return fmt.Errorf("%w: %.2f requested, %.2f left", ErrRedemptionExceedsBalance, redemption.Amount, balance)
+		}
+		ori.RedeemedAmount += redemption.Amount
+	} else if redemption.Amount != 0 {
+		return fmt.Errorf("%w: reward %d has no value to redeem %.2f from", ErrInvalidRedemptionAmount, ori.ID, redemption.Amount)
+	}
+
+	fullyRedeemed := ori.Value == 0 || ori.Balance() == 0
+	ori.IsRedeemed = &fullyRedeemed
+	if fullyRedeemed {
+		redeemedAt := redemption.RedeemedAt
+		ori.RedeemedDate = &redeemedAt
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"encoding/json"
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+// ReviewStatus is where a reward review stands
+type ReviewStatus string
+
+const (
+	ReviewPending  ReviewStatus = "pending"
+	ReviewApproved ReviewStatus = "approved"
+	ReviewRejected ReviewStatus = "rejected"
+)
+
+// ReviewReason is why an allocation was held back
+type ReviewReason string
+
+const (
+	ReviewReasonFraud        ReviewReason = "fraud"         // fraud screening asked for a review
+	ReviewReasonHighValue    ReviewReason = "high_value"    // the reward costs more than the review threshold
+	ReviewReasonShippingCost ReviewReason = "shipping_cost" // the shipping quote is above the review threshold
+)
+
+// RewardReview is an allocation held back until a person approves or rejects it
+type RewardReview struct {
+	ID         int64           `json:"id"`
+	OrderID    int64           `json:"order_id"`
+	UserID     string          `json:"user_id"`
+	CampaignID uuid.UUID       `json:"campaign_id"`
+	Event      json.RawMessage `json:"event"` // the allocation event, replayed on approval
+	Reasons    []ReviewReason  `json:"reasons"`
+	Signals    []FraudSignal   `json:"signals"` // set when fraud screening is one of the reasons
+	Status     ReviewStatus    `json:"status"`
+	CreatedAt  time.Time       `json:"created_at"`
+	DecidedAt  *time.Time      `json:"decided_at,omitempty"`
+	DecidedBy  string          `json:"decided_by,omitempty"`
+	Note       string          `json:"note,omitempty"`
+}
@@ -0,0 +1,96 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	. "github.com/craftizmv/rewards/internal/data/dtos"
+	"sort"
+)
+
+var (
+	ErrTierNotReached = errors.New("order value is below the reward tier")
+	ErrTierCapReached = errors.New("reward tier gave out all of its rewards")
+	ErrUnknownTier    = errors.New("reward group is not a tier of the campaign")
+)
+
+// ValidateRewardTiers checks that every tier sits above the base reward and that tiers do not overlap
+func ValidateRewardTiers(campaign *CampaignDTO) error {
+	criteria := campaign.EligibilityCriteria
+	amounts := make(map[float64]bool)
+	groups := make(map[int64]bool)
+	for i, tier := range criteria.Tiers {
+		if tier.RewardGroupID <= 0 {
+			return fmt.Errorf("%w: tier %d has no reward group", ErrInvalidRewardTiers, i)
+		}
+		if tier.RewardGroupID == campaign.RewardGroupID {
+			return fmt.Errorf("%w: tier %d gives the base reward group %d", ErrInvalidRewardTiers, i, tier.RewardGroupID)
+		}
+		if tier.MinimumPurchaseAmount <= criteria.MinimumPurchaseAmount {
+			return fmt.Errorf("%w: tier %d starts at %.2f, which is not above the minimum purchase amount %.2f",
+				ErrInvalidRewardTiers, i, tier.MinimumPurchaseAmount, criteria.MinimumPurchaseAmount)
+		}
+		if tier.MaxRewards < 0 || tier.RewardValue < 0 {
+			return fmt.Errorf("%w: tier %d has a negative cap or reward value", ErrInvalidRewardTiers, i)
+		}
+		if amounts[tier.MinimumPurchaseAmount] {
+			return fmt.Errorf("%w: more than one tier starts at %.2f", ErrInvalidRewardTiers, tier.MinimumPurchaseAmount)
+		}
+		if groups[tier.RewardGroupID] {
+			return fmt.Errorf("%w: reward group %d is used by more than one tier", ErrInvalidRewardTiers, tier.RewardGroupID)
+		}
+		amounts[tier.MinimumPurchaseAmount] = true
+		groups[tier.RewardGroupID] = true
+	}
+	return nil
+}
+
+// QualifyingTiers returns the tiers the order value reaches, highest first
+func QualifyingTiers(criteria EligibilityCriteria, orderValue float64) []RewardTier {
+	var tiers []RewardTier
+	for _, tier := range criteria.Tiers {
+		if orderValue >= tier.MinimumPurchaseAmount {
+			tiers = append(tiers, tier)
+		}
+	}
+	sort.Slice(tiers, func(i, j int) bool {
+		return tiers[i].MinimumPurchaseAmount > tiers[j].MinimumPurchaseAmount
+	})
+	return tiers
+}
+
+// FindTier returns the tier giving the reward group, nil if the group is not a tier of the campaign
+func FindTier(criteria EligibilityCriteria, rewardGroupID int64) *RewardTier {
+	for i := range criteria.Tiers {
+		if criteria.Tiers[i].RewardGroupID == rewardGroupID {
+			return &criteria.Tiers[i]
+		}
+	}
+	return nil
+}
+
+// ApplyTier returns a copy of the campaign giving the reward group of the tier
+func ApplyTier(campaign *CampaignDTO, tier RewardTier) *CampaignDTO {
+	tiered := *campaign
+	tiered.RewardGroupID = tier.RewardGroupID
+	if tier.RewardValue > 0 {
+		tiered.RewardValue = tier.RewardValue
+	}
+	return &tiered
+}
+
+// CheckTier fails with ErrTierNotReached if the order value is below the tier
+func CheckTier(tier RewardTier, orderValue float64) error {
+	if orderValue < tier.MinimumPurchaseAmount {
+		return fmt.Errorf("%w: %.2f is below %.2f of tier %s", ErrTierNotReached, orderValue, tier.MinimumPurchaseAmount, tier.Name)
+	}
+	return nil
+}
+
+// CheckTierCap fails with ErrTierCapReached if the tier gave out more rewards than its cap.
+// The allocated count includes the reward being checked.
+func CheckTierCap(tier RewardTier, allocated int) error {
+	if tier.MaxRewards > 0 && allocated > tier.MaxRewards {
+		return fmt.Errorf("%w: tier %s is capped at %d rewards", ErrTierCapReached, tier.Name, tier.MaxRewards)
+	}
+	return nil
+}
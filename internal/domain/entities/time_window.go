@@ -0,0 +1,125 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	. "github.com/craftizmv/rewards/internal/data/dtos"
+	"time"
+)
+
+var ErrOutsideTimeWindow = errors.New("outside of the campaign time windows")
+
+const timeOfDayLayout = "15:04"
+
+// CampaignLocation returns the timezone of the campaign, UTC when none is set
+func CampaignLocation(campaign *CampaignDTO) (*time.Location, error) {
+	if campaign.Timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(campaign.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q: %v", ErrInvalidTimezone, campaign.Timezone, err)
+	}
+	return loc, nil
+}
+
+// ValidateTimeWindows checks that every window can match at some point
+func ValidateTimeWindows(windows []TimeWindow) error {
+	for i, window := range windows {
+		if !inRange(window.Months, 1, 12) {
+			return fmt.Errorf("%w: window %d has a month outside 1-12", ErrInvalidTimeWindow, i)
+		}
+		if !inRange(window.DaysOfMonth, 1, 31) {
+			return fmt.Errorf("%w: window %d has a day outside 1-31", ErrInvalidTimeWindow, i)
+		}
+		if !inRange(window.Weekdays, 0, 6) {
+			return fmt.Errorf("%w: window %d has a weekday outside 0-6", ErrInvalidTimeWindow, i)
+		}
+		if (window.StartTime == "") != (window.EndTime == "") {
+			return fmt.Errorf("%w: window %d needs both a start and an end time", ErrInvalidTimeWindow, i)
+		}
+		if window.StartTime != "" {
+			start, err := time.Parse(timeOfDayLayout, window.StartTime)
+			if err != nil {
+				return fmt.Errorf("%w: window %d start time %q is not HH:MM", ErrInvalidTimeWindow, i, window.StartTime)
+			}
+			end, err := time.Parse(timeOfDayLayout, window.EndTime)
+			if err != nil {
+				return fmt.Errorf("%w: window %d end time %q is not HH:MM", ErrInvalidTimeWindow, i, window.EndTime)
+			}
+			if start.Equal(end) {
+				return fmt.Errorf("%w: window %d starts and ends at %s", ErrInvalidTimeWindow, i, window.StartTime)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckTimeWindows fails with ErrOutsideTimeWindow if the moment is in none of the windows of the campaign
+func CheckTimeWindows(campaign *CampaignDTO, at time.Time) error {
+	windows := campaign.EligibilityCriteria.TimeWindows
+	if len(windows) == 0 {
+		return nil
+	}
+	loc, err := CampaignLocation(campaign)
+	if err != nil {
+		return err
+	}
+
+	local := at.In(loc)
+	for _, window := range windows {
+		if isWithinTimeWindow(window, local) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s in %s", ErrOutsideTimeWindow, local.Format("Mon 2 Jan 15:04"), loc)
+}
+
+// isWithinTimeWindow expects the moment in the campaign timezone, the window has been validated.
+// A window crossing midnight belongs to the day it started, so after midnight the month, day and
+// weekday filters are evaluated against the previous day.
+func isWithinTimeWindow(window TimeWindow, local time.Time) bool {
+	day := local
+	if window.StartTime != "" {
+		start, _ := time.Parse(timeOfDayLayout, window.StartTime)
+		end, _ := time.Parse(timeOfDayLayout, window.EndTime)
+		minute := local.Hour()*60 + local.Minute()
+		startMinute := start.Hour()*60 + start.Minute()
+		endMinute := end.Hour()*60 + end.Minute()
+		switch {
+		case startMinute < endMinute:
+			if minute < startMinute || minute >= endMinute {
+				return false
+			}
+		case minute < endMinute:
+			day = local.AddDate(0, 0, -1)
+		case minute < startMinute:
+			return false
+		}
+	}
+
+	return matches(window.Months, int(day.Month())) &&
+		matches(window.DaysOfMonth, day.Day()) &&
+		matches(window.Weekdays, int(day.Weekday()))
+}
+
+func matches(values []int, value int) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func inRange(values []int, min, max int) bool {
+	for _, v := range values {
+		if v < min || v > max {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,86 @@
+package entities
+
+import (
+	"errors"
+	. "github.com/craftizmv/rewards/internal/data/dtos"
+	"testing"
+	"time"
+)
+
+func TestIsWithinTimeWindow(t *testing.T) {
+	// 2024-03-15 is a Friday
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2024, month, day, hour, minute, 0, 0, time.UTC)
+	}
+	fridayNight := TimeWindow{Weekdays: []int{int(time.Friday)}, StartTime: "22:00", EndTime: "02:00"}
+	lunch := TimeWindow{Weekdays: []int{int(time.Friday)}, StartTime: "12:00", EndTime: "14:00"}
+	newYearsEve := TimeWindow{Months: []int{12}, DaysOfMonth: []int{31}, StartTime: "20:00", EndTime: "04:00"}
+	lastOfMonth := TimeWindow{DaysOfMonth: []int{29}, StartTime: "23:00", EndTime: "01:00"}
+
+	tests := []struct {
+		name   string
+		window TimeWindow
+		local  time.Time
+		want   bool
+	}{
+		{name: "same day start inclusive", window: lunch, local: at(time.March, 15, 12, 0), want: true},
+		{name: "same day end exclusive", window: lunch, local: at(time.March, 15, 14, 0), want: false},
+		{name: "same day other weekday", window: lunch, local: at(time.March, 16, 13, 0), want: false},
+		{name: "no time of day", window: TimeWindow{Weekdays: []int{int(time.Friday)}}, local: at(time.March, 15, 3, 0), want: true},
+
+		// the part after midnight belongs to the day the window started
+		{name: "before midnight on the start day", window: fridayNight, local: at(time.March, 15, 23, 30), want: true},
+		{name: "start inclusive", window: fridayNight, local: at(time.March, 15, 22, 0), want: true},
+		{name: "after midnight on the next day", window: fridayNight, local: at(time.March, 16, 1, 59), want: true},
+		{name: "end exclusive", window: fridayNight, local: at(time.March, 16, 2, 0), want: false},
+		{name: "between end and start", window: fridayNight, local: at(time.March, 15, 12, 0), want: false},
+		{name: "after midnight on the start day", window: fridayNight, local: at(time.March, 15, 1, 0), want: false},
+		{name: "before midnight on the next day", window: fridayNight, local: at(time.March, 16, 23, 0), want: false},
+		{name: "after midnight into the next year", window: newYearsEve, local: time.Date(2025, time.January, 1, 3, 0, 0, 0, time.UTC), want: true},
+		{name: "end exclusive into the next year", window: newYearsEve, local: time.Date(2025, time.January, 1, 4, 0, 0, 0, time.UTC), want: false},
+		{name: "after midnight into the next month of a leap year", window: lastOfMonth, local: at(time.March, 1, 0, 30), want: true},
+		{name: "after midnight on the start day of the month", window: lastOfMonth, local: at(time.March, 29, 0, 30), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isWithinTimeWindow(tt.window, tt.local); got != tt.want {
+				t.Errorf("isWithinTimeWindow(%+v, %s) = %v, want %v", tt.window, tt.local.Format(time.RFC1123), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckTimeWindows(t *testing.T) {
+	campaign := func(timezone string, windows ...TimeWindow) *CampaignDTO {
+		return &CampaignDTO{Timezone: timezone, EligibilityCriteria: EligibilityCriteria{TimeWindows: windows}}
+	}
+	// Friday 22:00 to Saturday 02:00 in New York, 2024-03-16 03:30 UTC is Friday 23:30 there
+	fridayNight := TimeWindow{Weekdays: []int{int(time.Friday)}, StartTime: "22:00", EndTime: "02:00"}
+	saturdayMorning := TimeWindow{Weekdays: []int{int(time.Saturday)}, StartTime: "06:00", EndTime: "10:00"}
+
+	tests := []struct {
+		name     string
+		campaign *CampaignDTO
+		at       time.Time
+		wantErr  error
+	}{
+		{name: "no windows", campaign: campaign(""), at: time.Date(2024, time.March, 16, 3, 30, 0, 0, time.UTC)},
+		{name: "crossing midnight in the campaign timezone", campaign: campaign("America/New_York", fridayNight), at: time.Date(2024, time.March, 16, 3, 30, 0, 0, time.UTC)},
+		{name: "after midnight in the campaign timezone", campaign: campaign("America/New_York", fridayNight), at: time.Date(2024, time.March, 16, 5, 30, 0, 0, time.UTC)},
+		{name: "after the window in the campaign timezone", campaign: campaign("America/New_York", fridayNight), at: time.Date(2024, time.March, 16, 6, 30, 0, 0, time.UTC), wantErr: ErrOutsideTimeWindow},
+		{name: "crossing midnight in UTC", campaign: campaign("", fridayNight), at: time.Date(2024, time.March, 16, 1, 0, 0, 0, time.UTC)},
+		{name: "any of the windows", campaign: campaign("", fridayNight, saturdayMorning), at: time.Date(2024, time.March, 16, 7, 0, 0, 0, time.UTC)},
+		{name: "none of the windows", campaign: campaign("", fridayNight, saturdayMorning), at: time.Date(2024, time.March, 16, 12, 0, 0, 0, time.UTC), wantErr: ErrOutsideTimeWindow},
+		{name: "unknown timezone", campaign: campaign("Mars/Olympus_Mons", fridayNight), at: time.Date(2024, time.March, 16, 1, 0, 0, 0, time.UTC), wantErr: ErrInvalidTimezone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckTimeWindows(tt.campaign, tt.at)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckTimeWindows() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	. "github.com/craftizmv/rewards/internal/data/dtos"
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+var ErrUserRewardLimitReached = errors.New("user reward limit reached")
+
+// UserReward is an entry of the user reward ledger, one per allocated reward
+type UserReward struct {
+	UserID        string     `json:"user_id"`
+	CampaignID    uuid.UUID  `json:"campaign_id"`
+	OrderID       int64      `json:"order_id"`
+	RewardGroupID int64      `json:"reward_group_id"` // Reward group given out, tier caps are counted per group
+	AllocatedAt   time.Time  `json:"allocated_at"`
+	ReversedAt    *time.Time `json:"reversed_at,omitempty"` // set when the reward is cancelled, reversed rewards don't count
+}
+
+// UserRewardUsage is what a user already received, reversed rewards excluded
+type UserRewardUsage struct {
+	InCampaign int // rewards from the campaign
+	InWindow   int // rewards from the campaign within the rolling window
+	Lifetime   int // rewards from all campaigns
+}
+
+// WindowStart returns the start of the rolling window of the limits, the zero time if no window is set
+func WindowStart(limits UserRewardLimits, now time.Time) time.Time {
+	if limits.WindowDays <= 0 {
+		return time.Time{}
+	}
+	return now.AddDate(0, 0, -limits.WindowDays)
+}
+
+// CheckUserRewardLimits fails with ErrUserRewardLimitReached if one more reward would breach a limit
+func CheckUserRewardLimits(limits UserRewardLimits, usage *UserRewardUsage) error {
+	if limits.MaxPerCampaign > 0 && usage.InCampaign >= limits.MaxPerCampaign {
+		return fmt.Errorf("%w: %d of %d rewards per campaign", ErrUserRewardLimitReached, usage.InCampaign, limits.MaxPerCampaign)
+	}
+	if limits.MaxPerWindow > 0 && usage.InWindow >= limits.MaxPerWindow {
+		return fmt.Errorf("%w: %d of %d rewards in %d days", ErrUserRewardLimitReached, usage.InWindow, limits.MaxPerWindow, limits.WindowDays)
+	}
+	if limits.MaxLifetime > 0 && usage.Lifetime >= limits.MaxLifetime {
+		return fmt.Errorf("%w: %d of %d lifetime rewards", ErrUserRewardLimitReached, usage.Lifetime, limits.MaxLifetime)
+	}
+	return nil
+}
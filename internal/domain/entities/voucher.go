@@ -0,0 +1,62 @@
+package entities
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidVoucherPool  = errors.New("voucher pool is invalid")
+	ErrInvalidVoucherCode  = errors.New("voucher code is invalid")
+	ErrInvalidVoucherBatch = errors.New("voucher code batch is invalid")
+)
+
+// VoucherCodeStatus is where a voucher code stands in its life
+type VoucherCodeStatus string
+
+const (
+	VoucherAvailable VoucherCodeStatus = "available"
+	VoucherAssigned  VoucherCodeStatus = "assigned" // handed out with a reward
+	VoucherConsumed  VoucherCodeStatus = "consumed" // used by the customer
+	VoucherVoided    VoucherCodeStatus = "voided"   // the pool expired before the handed out code was used
+)
+
+// VoucherCodeFormat describes how the codes of a pool are generated
+type VoucherCodeFormat struct {
+	Alphabet   string `json:"alphabet"`    // characters codes are made of, see services.DefaultVoucherAlphabet
+	Length     int    `json:"length"`      // random characters, the check digit and prefix excluded
+	CheckDigit bool   `json:"check_digit"` // appends a check character so typos can be caught
+	Prefix     string `json:"prefix"`      // fixed start of every code, e.g. "XMAS-"
+}
+
+// VoucherPool is a stock of voucher codes, a reward group hands out one code of each of its pools per allocation
+type VoucherPool struct {
+	ID            int64             `json:"id"`
+	Name          string            `json:"name"`
+	RewardGroupID int64             `json:"reward_group_id"`
+	Format        VoucherCodeFormat `json:"format"`
+	ExpiresAt     *time.Time        `json:"expires_at,omitempty"` // codes cannot be handed out after this moment
+	CreatedAt     time.Time         `json:"created_at"`
+	Stock         VoucherStock      `json:"stock"`
+}
+
+// VoucherStock counts the codes of a pool per status
+type VoucherStock struct {
+	Total     int `json:"total"`
+	Available int `json:"available"`
+	Assigned  int `json:"assigned"`
+	Consumed  int `json:"consumed"`
+	Voided    int `json:"voided"`
+}
+
+// VoucherCode is a single code of a pool
+type VoucherCode struct {
+	ID         int64             `json:"id"`
+	PoolID     int64             `json:"pool_id"`
+	Code       string            `json:"code"`
+	Status     VoucherCodeStatus `json:"status"`
+	OrderID    *int64            `json:"order_id,omitempty"`
+	UserID     string            `json:"user_id,omitempty"`
+	AssignedAt *time.Time        `json:"assigned_at,omitempty"`
+	ConsumedAt *time.Time        `json:"consumed_at,omitempty"`
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"fmt"
+	. "github.com/craftizmv/rewards/internal/data/dtos"
+	"sort"
+)
+
+// SelectionStrategy decides which of the eligible campaigns an order wins first
+type SelectionStrategy string
+
+const (
+	StrategyPriority     SelectionStrategy = "priority"      // highest campaign priority
+	StrategyHighestValue SelectionStrategy = "highest_value" // highest reward value for the customer
+	StrategyLowestCost   SelectionStrategy = "lowest_cost"   // cheapest reward for the business
+	StrategyFirstCome    SelectionStrategy = "first_come"    // the campaign which started first
+)
+
+// CampaignSelectionConfig struct for the campaign selection configuration
+type CampaignSelectionConfig struct {
+	Strategy            SelectionStrategy `mapstructure:"strategy"`
+	MaxStackedCampaigns int               `mapstructure:"maxStackedCampaigns"` // upper bound of campaigns an order can win, 1 disables stacking
+}
+
+// CampaignSelector ranks the campaigns an order is eligible for and applies the stacking rules
+type CampaignSelector struct {
+	strategy   SelectionStrategy
+	maxStacked int
+}
+
+// NewCampaignSelector creates a CampaignSelector, priority with no stacking is used when nothing is configured
+func NewCampaignSelector(cfg *CampaignSelectionConfig) (*CampaignSelector, error) {
+	selector := &CampaignSelector{
+		strategy:   StrategyPriority,
+		maxStacked: 1,
+	}
+	if cfg == nil {
+		return selector, nil
+	}
+
+	switch cfg.Strategy {
+	case "":
+	case StrategyPriority, StrategyHighestValue, StrategyLowestCost, StrategyFirstCome:
+		selector.strategy = cfg.Strategy
+	default:
+		return nil, fmt.Errorf("unknown campaign selection strategy %q", cfg.Strategy)
+	}
+
+	if cfg.MaxStackedCampaigns > 0 {
+		selector.maxStacked = cfg.MaxStackedCampaigns
+	}
+
+	return selector, nil
+}
+
+// Select returns the campaigns the order wins, best first.
+// The best ranked campaign always wins. Further campaigns are added while they are stackable,
+// the winning ones are stackable too, and no other winner shares their stacking group.
+func (s *CampaignSelector) Select(eligible []*CampaignDTO) []*CampaignDTO {
+	if len(eligible) == 0 {
+		return nil
+	}
+
+	ranked := make([]*CampaignDTO, len(eligible))
+	copy(ranked, eligible)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return s.less(ranked[i], ranked[j])
+	})
+
+	selected := []*CampaignDTO{ranked[0]}
+	if !ranked[0].Stackable {
+		return selected
+	}
+
+	usedGroups := map[string]bool{ranked[0].StackingGroup: true}
+	for _, campaign := range ranked[1:] {
+		if len(selected) >= s.maxStacked {
+			break
+		}
+		if !campaign.Stackable {
+			continue
+		}
+		if campaign.StackingGroup != "" && usedGroups[campaign.StackingGroup] {
+			continue
+		}
+
+		selected = append(selected, campaign)
+		usedGroups[campaign.StackingGroup] = true
+	}
+
+	return selected
+}
+
+// less reports whether campaign a ranks before b under the configured strategy,
+// ties fall back to the earliest start date so the order is deterministic.
+func (s *CampaignSelector) less(a, b *CampaignDTO) bool {
+	switch s.strategy {
+	case StrategyPriority:
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+	case StrategyHighestValue:
+		if a.RewardValue != b.RewardValue {
+			return a.RewardValue > b.RewardValue
+		}
+	case StrategyLowestCost:
+		if a.RewardCost != b.RewardCost {
+			return a.RewardCost < b.RewardCost
+		}
+	}
+
+	return a.StartDate.Before(b.StartDate)
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"crypto/rand"
+	"fmt"
+	"github.com/craftizmv/rewards/internal/domain/entities"
+	"math/big"
+	"strings"
+)
+
+// DefaultVoucherAlphabet leaves out characters that are easily confused, such as 0/O and 1/I
+const DefaultVoucherAlphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+const (
+	defaultVoucherLength = 12
+	minVoucherLength     = 6
+)
+
+// VoucherCodeGenerator generates non-guessable voucher codes from a cryptographically secure source.
+// The optional check character follows the Luhn mod N algorithm over the alphabet.
+type VoucherCodeGenerator struct {
+	format entities.VoucherCodeFormat
+	index  map[rune]int
+}
+
+// NewVoucherCodeGenerator fills in the defaults of the format and validates it
+func NewVoucherCodeGenerator(format entities.VoucherCodeFormat) (*VoucherCodeGenerator, error) {
+	if format.Alphabet == "" {
+		format.Alphabet = DefaultVoucherAlphabet
+	}
+	if format.Length == 0 {
+		format.Length = defaultVoucherLength
+	}
+	if format.Length < minVoucherLength {
+		return nil, fmt.Errorf("%w: codes need at least %d random characters", entities.ErrInvalidVoucherPool, minVoucherLength)
+	}
+
+	index := make(map[rune]int)
+	for i, r := range []rune(format.Alphabet) {
+		if _, ok := index[r]; ok {
+			return nil, fmt.Errorf("%w: alphabet repeats %q", entities.ErrInvalidVoucherPool, r)
+		}
+		index[r] = i
+	}
+	if len(index) < 2 {
+		return nil, fmt.Errorf("%w: alphabet needs at least 2 characters", entities.ErrInvalidVoucherPool)
+	}
+
+	return &VoucherCodeGenerator{format: format, index: index}, nil
+}
+
+// Format returns the format with its defaults filled in
+func (g *VoucherCodeGenerator) Format() entities.VoucherCodeFormat {
+	return g.format
+}
+
+// Generate returns a new random code
+func (g *VoucherCodeGenerator) Generate() (string, error) {
+	alphabet := []rune(g.format.Alphabet)
+	max := big.NewInt(int64(len(alphabet)))
+
+	body := make([]rune, g.format.Length)
+	for i := range body {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate voucher code: %v", err)
+		}
+		body[i] = alphabet[n.Int64()]
+	}
+	if g.format.CheckDigit {
+		body = append(body, alphabet[g.checkIndex(body)])
+	}
+
+	return g.format.Prefix + string(body), nil
+}
+
+// Validate checks that the code has the shape of the format and, if enabled, a valid check character
+func (g *VoucherCodeGenerator) Validate(code string) error {
+	if !strings.HasPrefix(code, g.format.Prefix) {
+		return fmt.Errorf("%w: %s does not start with %s", entities.ErrInvalidVoucherCode, code, g.format.Prefix)
+	}
+	body := []rune(strings.TrimPrefix(code, g.format.Prefix))
+
+	length := g.format.Length
+	if g.format.CheckDigit {
+		length++
+	}
+	if len(body) != length {
+		return fmt.Errorf("%w: %s has the wrong length", entities.ErrInvalidVoucherCode, code)
+	}
+	for _, r := range body {
+		if _, ok := g.index[r]; !ok {
+			return fmt.Errorf("%w: %s contains %q", entities.ErrInvalidVoucherCode, code, r)
+		}
+	}
+
+	if g.format.CheckDigit {
+		last := len(body) - 1
+		if g.index[body[last]] != g.checkIndex(body[:last]) {
+			return fmt.Errorf("%w: %s has a wrong check character", entities.ErrInvalidVoucherCode, code)
+		}
+	}
+	return nil
+}
+
+// checkIndex computes the Luhn mod N check character of the body
+func (g *VoucherCodeGenerator) checkIndex(body []rune) int {
+	n := len(g.index)
+	factor := 2
+	sum := 0
+	for i := len(body) - 1; i >= 0; i-- {
+		addend := factor * g.index[body[i]]
+		addend = addend/n + addend%n
+		sum += addend
+		if factor == 2 {
+			factor = 1
+		} else {
+			factor = 2
+		}
+	}
+	return (n - sum%n) % n
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"errors"
+	"github.com/craftizmv/rewards/internal/domain/entities"
+	"strings"
+	"testing"
+)
+
+func TestNewVoucherCodeGenerator(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  entities.VoucherCodeFormat
+		wantErr bool
+	}{
+		{name: "defaults", format: entities.VoucherCodeFormat{}},
+		{name: "custom alphabet", format: entities.VoucherCodeFormat{Alphabet: "0123456789", Length: 8}},
+		{name: "too short", format: entities.VoucherCodeFormat{Length: minVoucherLength - 1}, wantErr: true},
+		{name: "repeated character", format: entities.VoucherCodeFormat{Alphabet: "ABCA"}, wantErr: true},
+		{name: "single character", format: entities.VoucherCodeFormat{Alphabet: "A"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewVoucherCodeGenerator(tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewVoucherCodeGenerator() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, entities.ErrInvalidVoucherPool) {
+				t.Errorf("NewVoucherCodeGenerator() error = %v, want ErrInvalidVoucherPool", err)
+			}
+		})
+	}
+}
+
+func TestVoucherCodeGeneratorGenerate(t *testing.T) {
+	tests := []struct {
+		name       string
+		format     entities.VoucherCodeFormat
+		wantLength int
+	}{
+		{name: "defaults", format: entities.VoucherCodeFormat{}, wantLength: defaultVoucherLength},
+		{name: "check digit", format: entities.VoucherCodeFormat{CheckDigit: true}, wantLength: defaultVoucherLength + 1},
+		{name: "prefix", format: entities.VoucherCodeFormat{Prefix: "XMAS-", Length: 8}, wantLength: len("XMAS-") + 8},
+		{name: "prefix and check digit", format: entities.VoucherCodeFormat{Prefix: "XMAS-", Length: 8, CheckDigit: true}, wantLength: len("XMAS-") + 9},
+		{name: "decimal", format: entities.VoucherCodeFormat{Alphabet: "0123456789", Length: 10, CheckDigit: true}, wantLength: 11},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator, err := NewVoucherCodeGenerator(tt.format)
+			if err != nil {
+				t.Fatalf("NewVoucherCodeGenerator() error = %v", err)
+			}
+
+			for i := 0; i < 100; i++ {
+				code, err := generator.Generate()
+				if err != nil {
+					t.Fatalf("Generate() error = %v", err)
+				}
+				if len(code) != tt.wantLength {
+					t.Errorf("Generate() = %s, want %d characters", code, tt.wantLength)
+				}
+				if !strings.HasPrefix(code, tt.format.Prefix) {
+					t.Errorf("Generate() = %s, want prefix %s", code, tt.format.Prefix)
+				}
+				if err := generator.Validate(code); err != nil {
+					t.Errorf("Validate(%s) error = %v", code, err)
+				}
+			}
+		})
+	}
+}
+
+func TestVoucherCodeGeneratorValidate(t *testing.T) {
+	decimal := entities.VoucherCodeFormat{Alphabet: "0123456789", Length: 10, CheckDigit: true}
+	hex := entities.VoucherCodeFormat{Alphabet: "0123456789ABCDEF", Length: 6, CheckDigit: true}
+	prefixed := entities.VoucherCodeFormat{Alphabet: "0123456789", Length: 10, CheckDigit: true, Prefix: "XMAS-"}
+	plain := entities.VoucherCodeFormat{Alphabet: "0123456789", Length: 10}
+
+	tests := []struct {
+		name    string
+		format  entities.VoucherCodeFormat
+		code    string
+		wantErr bool
+	}{
+		// with a decimal alphabet Luhn mod N is the plain Luhn algorithm
+		{name: "luhn check digit", format: decimal, code: "79927398713"},
+		{name: "luhn all zeros", format: decimal, code: "00000000000"},
+		{name: "luhn wrong check digit", format: decimal, code: "79927398710", wantErr: true},
+		{name: "luhn single digit typo", format: decimal, code: "79927398813", wantErr: true},
+		{name: "luhn adjacent transposition", format: decimal, code: "97927398713", wantErr: true},
+		{name: "mod 16 check character", format: hex, code: "1A2B3C5"},
+		{name: "mod 16 wrong check character", format: hex, code: "1A2B3C8", wantErr: true},
+		{name: "prefix", format: prefixed, code: "XMAS-79927398713"},
+		{name: "missing prefix", format: prefixed, code: "79927398713", wantErr: true},
+		{name: "without check digit", format: plain, code: "7992739871"},
+		{name: "too short", format: plain, code: "799273987", wantErr: true},
+		{name: "too long", format: plain, code: "79927398711", wantErr: true},
+		{name: "outside of alphabet", format: plain, code: "799273987A", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator, err := NewVoucherCodeGenerator(tt.format)
+			if err != nil {
+				t.Fatalf("NewVoucherCodeGenerator() error = %v", err)
+			}
+
+			err = generator.Validate(tt.code)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate(%s) error = %v, wantErr %v", tt.code, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, entities.ErrInvalidVoucherCode) {
+				t.Errorf("Validate(%s) error = %v, want ErrInvalidVoucherCode", tt.code, err)
+			}
+		})
+	}
+}
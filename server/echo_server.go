@@ -15,10 +15,14 @@ const (
 )
 
 type EchoServer struct {
-	app     *echo.Echo
-	conf    *EchoConfig
-	log     logger.ILogger
-	useCase usecase.RewardUseCase
+	app             *echo.Echo
+	conf            *EchoConfig
+	log             logger.ILogger
+	useCase         usecase.RewardUseCase
+	campaignUseCase usecase.CampaignUseCase
+	voucherUseCase  usecase.VoucherUseCase
+	catalogUseCase  usecase.RewardCatalogUseCase
+	pointsUseCase   usecase.PointsUseCase
 }
 
 type EchoConfig struct {
@@ -31,13 +35,17 @@ type EchoConfig struct {
 	Host                string   `mapstructure:"host"`
 }
 
-func NewEchoServer(conf *EchoConfig, log logger.ILogger, useCase usecase.RewardUseCase) *EchoServer {
+func NewEchoServer(conf *EchoConfig, log logger.ILogger, useCase usecase.RewardUseCase, campaignUseCase usecase.CampaignUseCase, voucherUseCase usecase.VoucherUseCase, catalogUseCase usecase.RewardCatalogUseCase, pointsUseCase usecase.PointsUseCase) *EchoServer {
 	e := echo.New()
 	return &EchoServer{
-		app:     e,
-		conf:    conf,
-		log:     log,
-		useCase: useCase,
+		app:             e,
+		conf:            conf,
+		log:             log,
+		useCase:         useCase,
+		campaignUseCase: campaignUseCase,
+		voucherUseCase:  voucherUseCase,
+		catalogUseCase:  catalogUseCase,
+		pointsUseCase:   pointsUseCase,
 	}
 }
 
@@ -55,6 +63,13 @@ func (s *EchoServer) Start() {
 
 	// init http handlers
 	s.initRewardHttpHandler(s.useCase)
+	s.initCampaignHttpHandler(s.campaignUseCase)
+	s.initReviewHttpHandler(s.useCase)
+	s.initVoucherHttpHandler(s.voucherUseCase)
+	s.initRedemptionHttpHandler(s.useCase)
+	s.initRewardCatalogHttpHandler(s.catalogUseCase)
+	s.initPointsHttpHandler(s.pointsUseCase)
+	s.initGiftChoiceHttpHandler(s.useCase)
 
 	s.app.Logger.Fatal(s.app.Start(s.conf.Port))
 }
@@ -70,3 +85,99 @@ func (s *EchoServer) initRewardHttpHandler(usecase usecase.RewardUseCase) {
 	rewardRouter.POST("eligibility", rewardHandler.CheckRewardEligibility)
 
 }
+
+func (s *EchoServer) initCampaignHttpHandler(usecase usecase.CampaignUseCase) {
+
+	campaignHandler := http.NewCampaignHandler(usecase, s.log)
+
+	// routers
+	campaignRouter := s.app.Group(s.conf.BasePath + "/campaigns")
+	campaignRouter.POST("", campaignHandler.CreateCampaign)
+	campaignRouter.GET("", campaignHandler.ListCampaigns)
+	campaignRouter.GET("/:id", campaignHandler.GetCampaign)
+	campaignRouter.PUT("/:id", campaignHandler.UpdateCampaign)
+	campaignRouter.GET("/:id/audit", campaignHandler.ListCampaignStatusChanges)
+	campaignRouter.POST("/:id/schedule", campaignHandler.ScheduleCampaign)
+	campaignRouter.POST("/:id/unschedule", campaignHandler.UnscheduleCampaign)
+	campaignRouter.POST("/:id/pause", campaignHandler.PauseCampaign)
+	campaignRouter.POST("/:id/resume", campaignHandler.ResumeCampaign)
+	campaignRouter.POST("/:id/end", campaignHandler.EndCampaign)
+	campaignRouter.POST("/:id/archive", campaignHandler.ArchiveCampaign)
+}
+
+func (s *EchoServer) initReviewHttpHandler(usecase usecase.RewardUseCase) {
+
+	reviewHandler := http.NewReviewHandler(usecase, s.log)
+
+	// routers
+	reviewRouter := s.app.Group(s.conf.BasePath + "/reviews")
+	reviewRouter.GET("", reviewHandler.ListReviews)
+	reviewRouter.POST("/:id/approve", reviewHandler.ApproveReview)
+	reviewRouter.POST("/:id/reject", reviewHandler.RejectReview)
+}
+
+func (s *EchoServer) initRedemptionHttpHandler(usecase usecase.RewardUseCase) {
+
+	redemptionHandler := http.NewRedemptionHandler(usecase, s.log)
+
+	// routers
+	redemptionRouter := s.app.Group(s.conf.BasePath + "/rewards")
+	redemptionRouter.POST("/:id/redeem", redemptionHandler.RedeemReward)
+	redemptionRouter.GET("/:id/balance", redemptionHandler.GetRewardBalance)
+}
+
+func (s *EchoServer) initGiftChoiceHttpHandler(usecase usecase.RewardUseCase) {
+
+	giftChoiceHandler := http.NewGiftChoiceHandler(usecase, s.log)
+
+	// routers
+	giftChoiceRouter := s.app.Group(s.conf.BasePath + "/gift-choices")
+	giftChoiceRouter.GET("/:id", giftChoiceHandler.GetGiftChoice)
+	giftChoiceRouter.POST("/:id/choose", giftChoiceHandler.ChooseGift)
+}
+
+func (s *EchoServer) initPointsHttpHandler(usecase usecase.PointsUseCase) {
+
+	pointsHandler := http.NewPointsHandler(usecase, s.log)
+
+	// routers
+	pointsRouter := s.app.Group(s.conf.BasePath + "/users/:userId/points")
+	pointsRouter.GET("", pointsHandler.GetPointsBalance)
+	pointsRouter.GET("/history", pointsHandler.GetPointsHistory)
+	pointsRouter.POST("/redeem", pointsHandler.RedeemPoints)
+}
+
+func (s *EchoServer) initRewardCatalogHttpHandler(usecase usecase.RewardCatalogUseCase) {
+
+	catalogHandler := http.NewRewardCatalogHandler(usecase, s.log)
+
+	// routers
+	groupRouter := s.app.Group(s.conf.BasePath + "/reward-groups")
+	groupRouter.POST("", catalogHandler.CreateRewardGroup)
+	groupRouter.GET("", catalogHandler.ListRewardGroups)
+	groupRouter.GET("/:id", catalogHandler.GetRewardGroup)
+	groupRouter.PUT("/:id", catalogHandler.UpdateRewardGroup)
+	groupRouter.POST("/:id/items", catalogHandler.AddRewardItem)
+	groupRouter.PUT("/:id/items/:itemId", catalogHandler.AttachRewardItem)
+	groupRouter.DELETE("/:id/items/:itemId", catalogHandler.DetachRewardItem)
+	groupRouter.PUT("/:id/products/:productId", catalogHandler.AddRewardGroupProduct)
+	groupRouter.DELETE("/:id/products/:productId", catalogHandler.RemoveRewardGroupProduct)
+
+	itemRouter := s.app.Group(s.conf.BasePath + "/reward-items")
+	itemRouter.GET("/:id", catalogHandler.GetRewardItem)
+	itemRouter.PUT("/:id", catalogHandler.UpdateRewardItem)
+}
+
+func (s *EchoServer) initVoucherHttpHandler(usecase usecase.VoucherUseCase) {
+
+	voucherHandler := http.NewVoucherHandler(usecase, s.log)
+
+	// routers
+	voucherRouter := s.app.Group(s.conf.BasePath + "/voucher-pools")
+	voucherRouter.POST("", voucherHandler.CreatePool)
+	voucherRouter.GET("", voucherHandler.ListPools)
+	voucherRouter.GET("/:id", voucherHandler.GetPool)
+	voucherRouter.POST("/:id/generate", voucherHandler.GenerateCodes)
+	voucherRouter.POST("/:id/import", voucherHandler.ImportCodes)
+	voucherRouter.POST("/codes/:code/consume", voucherHandler.ConsumeCode)
+}